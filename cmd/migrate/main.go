@@ -1,30 +1,41 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"time"
 
-	"go-gin-clean/internal/core/domain/entities"
+	"go-gin-clean/internal/adapters/secondary/database"
+	"go-gin-clean/internal/adapters/secondary/database/migrations"
 	"go-gin-clean/pkg/config"
 
 	"github.com/joho/godotenv"
-	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
-var (
-	models = []any{
-		&entities.User{},
-		&entities.RefreshToken{},
+const usage = "Usage: migrate [up [N]|down [N]|status|create <name>|force <version>|fresh]"
+
+func main() {
+	// Check command line arguments
+	if len(os.Args) < 2 {
+		log.Fatal(usage)
 	}
 
-	enums = map[string][]string{
-		"gender": {"Male", "Female", "Unknown"},
+	command := os.Args[1]
+
+	// create only writes files and does not need a database
+	if command == "create" {
+		if len(os.Args) < 3 {
+			log.Fatal("Usage: migrate create <name>")
+		}
+		runCreate(os.Args[2])
+		return
 	}
-)
 
-func main() {
 	// Load environment variables
 	if err := godotenv.Load(".env"); err != nil {
 		log.Println("Warning: .env file not found")
@@ -42,92 +53,131 @@ func main() {
 		log.Fatalf("Error connecting to database: %v", err)
 	}
 
-	// Check command line arguments
-	if len(os.Args) < 2 {
-		log.Fatal("Usage: migrate [migrate|rollback|fresh]")
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		log.Fatalf("Error loading migrations: %v", err)
 	}
 
-	command := os.Args[1]
+	ctx := context.Background()
 
 	switch command {
-	case "migrate":
-		runMigrations(db)
-	case "rollback":
-		runRollback(db)
+	case "up":
+		runUp(ctx, migrator, countArg(0))
+	case "down":
+		runDown(ctx, migrator, countArg(1))
+	case "status":
+		runStatus(ctx, migrator)
+	case "force":
+		if len(os.Args) < 3 {
+			log.Fatal("Usage: migrate force <version>")
+		}
+		version, err := strconv.ParseInt(os.Args[2], 10, 64)
+		if err != nil || version < 0 {
+			log.Fatalf("Invalid version %q", os.Args[2])
+		}
+		runForce(ctx, migrator, version)
 	case "fresh":
-		runFreshMigrations(db)
+		runFresh(ctx, migrator)
 	default:
-		log.Fatal("Unknown command. Available commands: migrate, rollback, fresh")
+		log.Fatal("Unknown command. Available commands: up, down, status, create, force, fresh")
 	}
 }
 
 func setupDatabase(cfg *config.DatabaseConfig) (*gorm.DB, error) {
-	dsn := cfg.DSN()
-
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+	return database.Open(cfg, &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Warn),
 	})
+}
 
-	if err != nil {
-		return nil, err
+// countArg reads the optional N of "up [N]" and "down [N]".
+func countArg(fallback int) int {
+	if len(os.Args) < 3 {
+		return fallback
+	}
+
+	n, err := strconv.Atoi(os.Args[2])
+	if err != nil || n < 1 {
+		log.Fatalf("Invalid count %q", os.Args[2])
 	}
 
-	return db, nil
+	return n
 }
 
-func runMigrations(db *gorm.DB) {
+func runUp(ctx context.Context, migrator *migrations.Migrator, n int) {
 	log.Println("Running database migrations...")
 
-	for name, values := range enums {
-		quotedValues := make([]string, len(values))
-		for i, value := range values {
-			quotedValues[i] = "'" + value + "'"
-		}
-		sql := "DO $$ BEGIN " +
-			"IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = '" + name + "') THEN " +
-			"CREATE TYPE " + name + " AS ENUM (" + quotedValues[0]
-		for _, v := range quotedValues[1:] {
-			sql += ", " + v
-		}
-		sql += "); END IF; END $$;"
-
-		if err := db.Exec(sql).Error; err != nil {
-			log.Printf("Error creating enum %s: %v", name, err)
-		}
+	applied, err := migrator.Up(ctx, n)
+	for _, migration := range applied {
+		log.Printf("Applied %06d_%s", migration.Version, migration.Name)
 	}
-
-	// Run auto migrations
-	err := db.AutoMigrate(
-		models...,
-	)
-
 	if err != nil {
 		log.Fatalf("Migration failed: %v", err)
 	}
 
+	if len(applied) == 0 {
+		log.Println("No pending migrations")
+		return
+	}
+
 	log.Println("Database migrations completed successfully")
 }
 
-func runRollback(db *gorm.DB) {
+func runDown(ctx context.Context, migrator *migrations.Migrator, n int) {
 	log.Println("Running database rollback...")
 
-	for _, model := range models {
-		if err := db.Migrator().DropTable(model); err != nil {
-			log.Printf("Error dropping table %T: %v", model, err)
-		}
+	reverted, err := migrator.Down(ctx, n)
+	for _, migration := range reverted {
+		log.Printf("Reverted %06d_%s", migration.Version, migration.Name)
+	}
+	if err != nil {
+		log.Fatalf("Rollback failed: %v", err)
+	}
+
+	log.Println("Database rollback completed successfully")
+}
+
+func runStatus(ctx context.Context, migrator *migrations.Migrator) {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		log.Fatalf("Error reading migration status: %v", err)
 	}
 
-	for name := range enums {
-		if err := db.Exec("DROP TYPE IF EXISTS " + name).Error; err != nil {
-			log.Printf("Error dropping enum %s: %v", name, err)
+	fmt.Printf("%-8s %-40s %-8s %s\n", "VERSION", "NAME", "STATE", "APPLIED AT")
+	for _, status := range statuses {
+		state, appliedAt := "pending", ""
+		if status.Applied {
+			state = "applied"
+			appliedAt = status.AppliedAt.Format(time.RFC3339)
 		}
+		if status.Dirty {
+			state = "dirty"
+		}
+
+		fmt.Printf("%06d   %-40s %-8s %s\n", status.Version, status.Name, state, appliedAt)
 	}
+}
 
-	log.Println("Database rollback completed successfully")
+func runCreate(name string) {
+	paths, err := migrations.Create(migrations.Dir, name)
+	if err != nil {
+		log.Fatalf("Error creating migration: %v", err)
+	}
+
+	for _, path := range paths {
+		log.Printf("Created %s", path)
+	}
+}
+
+func runForce(ctx context.Context, migrator *migrations.Migrator, version int64) {
+	if err := migrator.Force(ctx, version); err != nil {
+		log.Fatalf("Error forcing version: %v", err)
+	}
+
+	log.Printf("Schema version forced to %d", version)
 }
 
-func runFreshMigrations(db *gorm.DB) {
+func runFresh(ctx context.Context, migrator *migrations.Migrator) {
 	log.Println("Running fresh migrations...")
-	runRollback(db)
-	runMigrations(db)
+	runDown(ctx, migrator, 0)
+	runUp(ctx, migrator, 0)
 }
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"go-gin-clean/internal/adapters/secondary/database"
+	"go-gin-clean/pkg/config"
+
+	"github.com/joho/godotenv"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// purge permanently removes users that were soft-deleted longer ago than the
+// retention period, expired tokens and idle login attempt counters, e.g. from
+// a daily cron job.
+func main() {
+	// Load environment variables
+	if err := godotenv.Load(".env"); err != nil {
+		log.Println("Warning: .env file not found")
+	}
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Error loading configuration: %v", err)
+	}
+
+	retention := flag.Duration("retention", cfg.Database.SoftDeleteRetention, "keep soft-deleted rows for this long")
+	flag.Parse()
+
+	if *retention < 0 {
+		log.Fatal("Retention must not be negative")
+	}
+
+	// Setup database connection
+	db, err := setupDatabase(&cfg.Database)
+	if err != nil {
+		log.Fatalf("Error connecting to database: %v", err)
+	}
+
+	deletedBefore := time.Now().Add(-*retention)
+	log.Printf("Purging users deleted before %s...", deletedBefore.Format(time.RFC3339))
+
+	userRepo := database.NewUserRepository(db)
+
+	purged, err := userRepo.Purge(context.Background(), deletedBefore)
+	if err != nil {
+		log.Fatalf("Error purging users: %v", err)
+	}
+
+	log.Printf("Purged %d users", purged)
+
+	// Expired tokens are rejected anyway, their rows only take up space
+	tokenStores := []struct {
+		name          string
+		deleteExpired func(ctx context.Context) error
+	}{
+		{"revoked access tokens", database.NewRevokedTokenRepository(db).DeleteExpired},
+		{"refresh tokens", database.NewRefreshTokenRepository(db).DeleteExpired},
+		{"one-time tokens", database.NewOneTimeTokenRepository(db).DeleteExpired},
+	}
+
+	for _, store := range tokenStores {
+		if err := store.deleteExpired(context.Background()); err != nil {
+			log.Fatalf("Error purging expired %s: %v", store.name, err)
+		}
+		log.Printf("Purged expired %s", store.name)
+	}
+
+	attemptsBefore := time.Now().Add(-cfg.LoginAttemptRetention())
+	if err := database.NewLoginAttemptRepository(db).DeleteStale(context.Background(), attemptsBefore); err != nil {
+		log.Fatalf("Error purging login attempts: %v", err)
+	}
+	log.Printf("Purged login attempts unlocked and idle since %s", attemptsBefore.Format(time.RFC3339))
+}
+
+func setupDatabase(cfg *config.DatabaseConfig) (*gorm.DB, error) {
+	return database.Open(cfg, &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Warn),
+	})
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// envReference matches ${NAME} placeholders in user names, emails and
+// passwords. They are replaced with the environment variable so secrets such
+// as the production admin password never live in the repository.
+var envReference = regexp.MustCompile(`\$\{(\w+)\}`)
+
+type Fixtures struct {
+	Permissions []PermissionFixture `json:"permissions" yaml:"permissions"`
+	Roles       []RoleFixture       `json:"roles" yaml:"roles"`
+	Users       []UserFixture       `json:"users" yaml:"users"`
+}
+
+type PermissionFixture struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description" yaml:"description"`
+}
+
+type RoleFixture struct {
+	Name        string   `json:"name" yaml:"name"`
+	Description string   `json:"description" yaml:"description"`
+	Permissions []string `json:"permissions" yaml:"permissions"`
+}
+
+type UserFixture struct {
+	Name     string   `json:"name" yaml:"name"`
+	Email    string   `json:"email" yaml:"email"`
+	Password string   `json:"password" yaml:"password"`
+	Gender   string   `json:"gender" yaml:"gender"`
+	Active   bool     `json:"active" yaml:"active"`
+	Roles    []string `json:"roles" yaml:"roles"`
+}
+
+// loadFixtures reads every .yaml, .yml and .json file in dir/common followed
+// by dir/<environment>, in file name order.
+func loadFixtures(dir, environment string) (*Fixtures, []string, error) {
+	fixtures := &Fixtures{}
+	var loaded []string
+
+	for _, scope := range []string{"common", environment} {
+		paths, err := fixtureFiles(filepath.Join(dir, scope))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, path := range paths {
+			if err := fixtures.load(path); err != nil {
+				return nil, nil, fmt.Errorf("%s: %w", path, err)
+			}
+			loaded = append(loaded, path)
+		}
+	}
+
+	return fixtures, loaded, nil
+}
+
+func fixtureFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".yaml", ".yml", ".json":
+			if !entry.IsDir() {
+				paths = append(paths, filepath.Join(dir, entry.Name()))
+			}
+		}
+	}
+	sort.Strings(paths)
+
+	return paths, nil
+}
+
+func (f *Fixtures) load(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var file Fixtures
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(content, &file)
+	} else {
+		err = yaml.Unmarshal(content, &file)
+	}
+	if err != nil {
+		return err
+	}
+
+	var missing []string
+	expand := func(value string) string {
+		return envReference.ReplaceAllStringFunc(value, func(ref string) string {
+			name := envReference.FindStringSubmatch(ref)[1]
+			resolved := os.Getenv(name)
+			if resolved == "" {
+				missing = append(missing, name)
+			}
+			return resolved
+		})
+	}
+
+	for i := range file.Users {
+		file.Users[i].Name = expand(file.Users[i].Name)
+		file.Users[i].Email = expand(file.Users[i].Email)
+		file.Users[i].Password = expand(file.Users[i].Password)
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("environment variables not set: %s", strings.Join(missing, ", "))
+	}
+
+	f.Permissions = append(f.Permissions, file.Permissions...)
+	f.Roles = append(f.Roles, file.Roles...)
+	f.Users = append(f.Users, file.Users...)
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"go-gin-clean/internal/adapters/secondary/database"
+	"go-gin-clean/internal/adapters/secondary/security"
+	"go-gin-clean/internal/core/domain/entities"
+	"go-gin-clean/pkg/config"
+
+	"github.com/joho/godotenv"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// seed loads fixtures from seeds/common and seeds/<environment> into a
+// migrated database. It is safe to run on every deploy.
+func main() {
+	// Load environment variables
+	if err := godotenv.Load(".env"); err != nil {
+		log.Println("Warning: .env file not found")
+	}
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Error loading configuration: %v", err)
+	}
+
+	dir := flag.String("dir", "seeds", "directory containing the fixture folders")
+	environment := flag.String("env", cfg.Server.Environment, "fixture folder to load after common")
+	flag.Parse()
+
+	fixtures, files, err := loadFixtures(*dir, *environment)
+	if err != nil {
+		log.Fatalf("Error loading fixtures: %v", err)
+	}
+
+	if len(files) == 0 {
+		log.Fatalf("No fixtures found for environment %q in %s", *environment, *dir)
+	}
+
+	// Setup database connection
+	db, err := setupDatabase(&cfg.Database)
+	if err != nil {
+		log.Fatalf("Error connecting to database: %v", err)
+	}
+
+	seeder := &seeder{
+		permissionRepo: database.NewBaseRepository[entities.Permission](db),
+		roleRepo:       database.NewRoleRepository(db),
+		userRepo:       database.NewUserRepository(db),
+		passwordHasher: security.NewPasswordHasher(&cfg.Password),
+	}
+
+	log.Printf("Seeding %s fixtures from %d files...", *environment, len(files))
+
+	if err := seeder.Seed(context.Background(), fixtures); err != nil {
+		log.Fatalf("Seeding failed: %v", err)
+	}
+
+	log.Println("Database seeding completed successfully")
+}
+
+func setupDatabase(cfg *config.DatabaseConfig) (*gorm.DB, error) {
+	return database.Open(cfg, &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Warn),
+	})
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"go-gin-clean/internal/core/domain/entities"
+	"go-gin-clean/internal/core/domain/enums"
+	"go-gin-clean/internal/core/ports"
+
+	"gorm.io/gorm"
+)
+
+// seeder inserts fixtures through the repositories. Every step only creates
+// what is missing, so running it repeatedly is safe.
+type seeder struct {
+	permissionRepo ports.BaseRepository[entities.Permission]
+	roleRepo       ports.RoleRepository
+	userRepo       ports.UserRepository
+	passwordHasher ports.PasswordHasher
+}
+
+func (s *seeder) Seed(ctx context.Context, fixtures *Fixtures) error {
+	if err := s.seedPermissions(ctx, fixtures.Permissions); err != nil {
+		return fmt.Errorf("seeding permissions: %w", err)
+	}
+
+	if err := s.seedRoles(ctx, fixtures.Roles); err != nil {
+		return fmt.Errorf("seeding roles: %w", err)
+	}
+
+	if err := s.seedUsers(ctx, fixtures.Users); err != nil {
+		return fmt.Errorf("seeding users: %w", err)
+	}
+
+	return nil
+}
+
+func (s *seeder) seedPermissions(ctx context.Context, fixtures []PermissionFixture) error {
+	for _, fixture := range fixtures {
+		_, err := s.permissionRepo.WithDeleted().FindFirst(ctx, "name = ?", fixture.Name)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if _, err := s.permissionRepo.Create(ctx, entities.NewPermission(fixture.Name, fixture.Description)); err != nil {
+			return err
+		}
+		log.Printf("Created permission %s", fixture.Name)
+	}
+
+	return nil
+}
+
+// seedRoles creates missing roles and sets their permissions to exactly the
+// ones listed in the fixture.
+func (s *seeder) seedRoles(ctx context.Context, fixtures []RoleFixture) error {
+	for _, fixture := range fixtures {
+		role, err := s.roleRepo.FindByName(ctx, fixture.Name)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			role, err = s.roleRepo.Create(ctx, entities.NewRole(fixture.Name, fixture.Description, nil))
+			if err == nil {
+				log.Printf("Created role %s", fixture.Name)
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		permissions, err := s.permissionRepo.Where(ctx, "name IN ?", fixture.Permissions)
+		if err != nil {
+			return err
+		}
+		if len(permissions) != len(fixture.Permissions) {
+			return fmt.Errorf("role %s references unknown permissions", fixture.Name)
+		}
+
+		if err := s.roleRepo.AssignPermissions(ctx, role, permissions); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// seedUsers creates users that do not exist yet. Existing users, including
+// soft-deleted ones, are left untouched so a rerun never resets a password.
+func (s *seeder) seedUsers(ctx context.Context, fixtures []UserFixture) error {
+	for _, fixture := range fixtures {
+		if fixture.Email == "" || fixture.Password == "" {
+			return fmt.Errorf("user %q needs an email and a password", fixture.Name)
+		}
+
+		if s.userRepo.ExistsByEmail(ctx, fixture.Email) {
+			log.Printf("Skipped user %s: already exists", fixture.Email)
+			continue
+		}
+
+		gender := enums.Gender(fixture.Gender)
+		if gender == "" {
+			gender = enums.Unknown
+		}
+		if !gender.IsValid() {
+			return fmt.Errorf("user %s has invalid gender %q", fixture.Email, fixture.Gender)
+		}
+
+		hashedPassword, err := s.passwordHasher.HashPassword(fixture.Password)
+		if err != nil {
+			return err
+		}
+
+		user, err := entities.NewUser(fixture.Name, fixture.Email, hashedPassword, "", gender)
+		if err != nil {
+			return err
+		}
+
+		if len(fixture.Roles) > 0 {
+			roles, err := s.roleRepo.FindByNames(ctx, fixture.Roles)
+			if err != nil {
+				return err
+			}
+			if len(roles) != len(fixture.Roles) {
+				return fmt.Errorf("user %s references unknown roles", fixture.Email)
+			}
+
+			for _, role := range roles {
+				user.Roles = append(user.Roles, *role)
+			}
+		}
+
+		if fixture.Active {
+			user.Activate()
+		}
+
+		if _, err := s.userRepo.Create(ctx, user); err != nil {
+			return err
+		}
+		log.Printf("Created user %s", fixture.Email)
+	}
+
+	return nil
+}
@@ -10,12 +10,12 @@ import (
 	"time"
 
 	httpAdapter "go-gin-clean/internal/adapters/primary/http"
+	"go-gin-clean/internal/adapters/secondary/database"
 	"go-gin-clean/internal/infrastructure"
 	"go-gin-clean/pkg/config"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
-	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
@@ -43,7 +43,7 @@ func main() {
 
 	router := gin.Default()
 
-	httpAdapter.SetupRoutes(router, container.UserUseCase, container.JWTService)
+	httpAdapter.SetupRoutes(router, container.UserUseCase, container.APIKeyUseCase, container.AuditLogUseCase, container.TokenRevocation, container.JWTService, cfg.Server.MaxPageSize)
 
 	srv := &http.Server{
 		Addr:    cfg.Server.Address(),
@@ -74,8 +74,6 @@ func main() {
 }
 
 func setupDatabase(cfg *config.DatabaseConfig) (*gorm.DB, error) {
-	dsn := cfg.DSN()
-
 	var logLevel logger.LogLevel
 	if cfg.Host == "localhost" || cfg.Host == "127.0.0.1" {
 		logLevel = logger.Info
@@ -83,21 +81,7 @@ func setupDatabase(cfg *config.DatabaseConfig) (*gorm.DB, error) {
 		logLevel = logger.Error
 	}
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+	return database.Open(cfg, &gorm.Config{
 		Logger: logger.Default.LogMode(logLevel),
 	})
-
-	if err != nil {
-		return nil, err
-	}
-
-	psqlDB, err := db.DB()
-	if err != nil {
-		return nil, err
-	}
-
-	psqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
-	psqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
-
-	return db, nil
 }
@@ -0,0 +1,26 @@
+package dto
+
+import "time"
+
+type (
+	CreateAPIKeyRequest struct {
+		Name      string     `json:"name" binding:"required,max=100"`
+		Scopes    []string   `json:"scopes" binding:"required,min=1"`
+		ExpiresAt *time.Time `json:"expires_at" binding:"required"`
+	}
+
+	CreateAPIKeyResponse struct {
+		Key    string     `json:"key"`
+		APIKey APIKeyInfo `json:"api_key"`
+	}
+
+	APIKeyInfo struct {
+		ID         int64      `json:"id"`
+		Name       string     `json:"name"`
+		Prefix     string     `json:"prefix"`
+		Scopes     []string   `json:"scopes"`
+		ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+		LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+		CreatedAt  time.Time  `json:"created_at"`
+	}
+)
@@ -0,0 +1,30 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type (
+	AuditLogQuery struct {
+		Page     int        `form:"page"`
+		PerPage  int        `form:"per_page"`
+		ActorID  *int64     `form:"actor_id"`
+		TargetID *int64     `form:"target_id"`
+		Action   string     `form:"action"`
+		From     *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+		To       *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	}
+
+	AuditLogInfo struct {
+		ID        int64           `json:"id"`
+		ActorID   *int64          `json:"actor_id"`
+		TargetID  *int64          `json:"target_id"`
+		Action    string          `json:"action"`
+		IPAddress string          `json:"ip_address,omitempty"`
+		UserAgent string          `json:"user_agent,omitempty"`
+		Changes   json.RawMessage `json:"changes,omitempty"`
+		Details   json.RawMessage `json:"details,omitempty"`
+		CreatedAt time.Time       `json:"created_at"`
+	}
+)
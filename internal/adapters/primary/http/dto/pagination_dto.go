@@ -13,3 +13,30 @@ type PaginationResponse[T any] struct {
 	Total      int `json:"total"`
 	TotalPages int `json:"total_pages"`
 }
+
+type CursorResponse[T any] struct {
+	Data       []T    `json:"data"`
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+	Total      *int   `json:"total,omitempty"`
+}
+
+// ListRequest adds sorting to a page request, e.g. sort=-created_at,name.
+// Every other query parameter is read as a filter, either field=value or
+// field[op]=value with op one of eq, ne, gt, gte, lt, lte, like and in.
+//
+// Sending cursor or limit switches to cursor pagination, which ignores page
+// and per_page and only counts the total when include_total is set.
+type ListRequest struct {
+	PaginationRequest
+	Sort         string `form:"sort,omitempty" json:"sort,omitempty"`
+	Cursor       string `form:"cursor,omitempty" json:"cursor,omitempty"`
+	Limit        int    `form:"limit,omitempty" json:"limit,omitempty"`
+	IncludeTotal bool   `form:"include_total,omitempty" json:"include_total,omitempty"`
+}
+
+// UsesCursor reports whether the request asked for cursor pagination.
+func (r *ListRequest) UsesCursor() bool {
+	return r.Cursor != "" || r.Limit > 0
+}
@@ -3,6 +3,7 @@ package dto
 import (
 	"go-gin-clean/internal/core/domain/enums"
 	"mime/multipart"
+	"time"
 )
 
 type (
@@ -13,6 +14,18 @@ type (
 		Avatar   string       `json:"avatar,omitempty"`
 		Gender   enums.Gender `json:"gender"`
 		IsActive bool         `json:"is_active"`
+		Roles    []string     `json:"roles"`
+
+		PendingEmail     string     `json:"pending_email,omitempty"`
+		TwoFactorEnabled bool       `json:"two_factor_enabled"`
+		DeletedAt        *time.Time `json:"deleted_at,omitempty"`
+
+		Match *SearchMatch `json:"match,omitempty"`
+	}
+
+	SearchMatch struct {
+		Field     string `json:"field"`
+		Highlight string `json:"highlight"`
 	}
 
 	LoginRequest struct {
@@ -24,12 +37,37 @@ type (
 		AccessToken  string   `json:"access_token"`
 		RefreshToken string   `json:"refresh_token"`
 		User         UserInfo `json:"user"`
+		MFARequired  bool     `json:"mfa_required"`
+		MFAToken     string   `json:"mfa_token,omitempty"`
+	}
+
+	TwoFactorLoginRequest struct {
+		MFAToken string `json:"mfa_token" binding:"required"`
+		Code     string `json:"code" binding:"required"`
+	}
+
+	TwoFactorSetupResponse struct {
+		Secret string `json:"secret"`
+		URI    string `json:"otpauth_uri"`
+	}
+
+	TwoFactorEnableRequest struct {
+		Code string `json:"code" binding:"required"`
+	}
+
+	TwoFactorEnableResponse struct {
+		RecoveryCodes []string `json:"recovery_codes"`
+	}
+
+	TwoFactorDisableRequest struct {
+		Password string `json:"password" binding:"required"`
+		Code     string `json:"code" binding:"required"`
 	}
 
 	RegisterRequest struct {
 		Name     string `json:"name" binding:"required"`
 		Email    string `json:"email" binding:"required,email"`
-		Password string `json:"password" binding:"required,min=8"`
+		Password string `json:"password" binding:"required"`
 	}
 
 	RefreshTokenResponse struct {
@@ -43,27 +81,76 @@ type (
 
 	ResetPasswordRequest struct {
 		Token       string `json:"token" binding:"required"`
-		NewPassword string `json:"new_password" binding:"required,min=8"`
+		NewPassword string `json:"new_password" binding:"required"`
 	}
 
 	SendVerifyEmailRequest struct {
 		Email string `json:"email" binding:"required,email"`
 	}
 
+	SendMagicLinkRequest struct {
+		Email string `json:"email" binding:"required,email"`
+	}
+
+	MagicLinkLoginRequest struct {
+		Token string `json:"token" binding:"required"`
+	}
+
 	VerifyEmailRequest struct {
 		Token string `json:"token" binding:"required"`
 	}
 
 	ChangePasswordRequest struct {
 		OldPassword string `json:"old_password" binding:"required"`
-		NewPassword string `json:"new_password" binding:"required,min=8"`
+		NewPassword string `json:"new_password" binding:"required"`
+	}
+
+	ChangeEmailRequest struct {
+		NewEmail string `json:"new_email" binding:"required,email"`
+		Password string `json:"password" binding:"required"`
+	}
+
+	ConfirmEmailChangeRequest struct {
+		Token string `json:"token" binding:"required"`
 	}
 
 	CreateUserRequest struct {
 		Name     string       `json:"name" binding:"required"`
 		Email    string       `json:"email" binding:"required,email"`
-		Password string       `json:"password" binding:"required,min=8"`
+		Password string       `json:"password" binding:"required"`
 		Gender   enums.Gender `json:"gender,omitempty"`
+		Roles    []string     `json:"roles,omitempty"`
+	}
+
+	SessionInfo struct {
+		ID         string    `json:"id"`
+		UserAgent  string    `json:"user_agent"`
+		IPAddress  string    `json:"ip_address"`
+		CreatedAt  time.Time `json:"created_at"`
+		LastUsedAt time.Time `json:"last_used_at"`
+		ExpiresAt  time.Time `json:"expires_at"`
+		Current    bool      `json:"current"`
+	}
+
+	OAuthStartResponse struct {
+		AuthURL     string
+		StateCookie string
+	}
+
+	OAuthCallbackRequest struct {
+		Code             string `form:"code"`
+		State            string `form:"state"`
+		Error            string `form:"error"`
+		ErrorDescription string `form:"error_description"`
+	}
+
+	PasswordViolation struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+
+	AssignRolesRequest struct {
+		Roles []string `json:"roles" binding:"required,min=1"`
 	}
 
 	UpdateUserRequest struct {
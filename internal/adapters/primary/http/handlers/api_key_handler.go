@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"go-gin-clean/internal/adapters/primary/http/dto"
+	"go-gin-clean/internal/adapters/primary/http/mappers"
+	"go-gin-clean/internal/adapters/primary/http/messages"
+	"go-gin-clean/internal/adapters/primary/http/response"
+	"go-gin-clean/internal/core/ports"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyHandler struct {
+	apiKeyUseCase ports.APIKeyUseCase
+	apiKeyMapper  mappers.APIKeyMapper
+}
+
+func NewAPIKeyHandler(apiKeyUseCase ports.APIKeyUseCase, apiKeyMapper mappers.APIKeyMapper) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyUseCase: apiKeyUseCase,
+		apiKeyMapper:  apiKeyMapper,
+	}
+}
+
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, messages.FAILED_UNAUTHORIZED, "user credentials not found", http.StatusUnauthorized)
+		return
+	}
+
+	var req dto.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, messages.FAILED_TO_BIND_BODY, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	contractReq := h.apiKeyMapper.CreateAPIKeyRequestToContract(&req)
+	contractResult, err := h.apiKeyUseCase.CreateAPIKey(c.Request.Context(), userID.(int64), contractReq)
+	if err != nil {
+		response.Error(c, messages.FAILED_CREATE_API_KEY, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result := h.apiKeyMapper.CreateAPIKeyResponseToDTO(contractResult)
+	response.Success(c, messages.SUCCESS_CREATE_API_KEY, result, http.StatusCreated)
+}
+
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, messages.FAILED_UNAUTHORIZED, "user credentials not found", http.StatusUnauthorized)
+		return
+	}
+
+	contractResult, err := h.apiKeyUseCase.ListAPIKeys(c.Request.Context(), userID.(int64))
+	if err != nil {
+		response.Error(c, messages.FAILED_GET_API_KEYS, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	result := h.apiKeyMapper.APIKeyInfosToDTO(contractResult)
+	response.Success(c, messages.SUCCESS_GET_API_KEYS, result, http.StatusOK)
+}
+
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, messages.FAILED_UNAUTHORIZED, "user credentials not found", http.StatusUnauthorized)
+		return
+	}
+
+	keyID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(c, messages.FAILED_TO_BIND_PARAMS, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.apiKeyUseCase.RevokeAPIKey(c.Request.Context(), userID.(int64), keyID); err != nil {
+		response.Error(c, messages.FAILED_REVOKE_API_KEY, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	response.Success(c, messages.SUCCESS_REVOKE_API_KEY, nil, http.StatusOK)
+}
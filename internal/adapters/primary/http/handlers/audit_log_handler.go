@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"go-gin-clean/internal/adapters/primary/http/dto"
+	"go-gin-clean/internal/adapters/primary/http/mappers"
+	"go-gin-clean/internal/adapters/primary/http/messages"
+	"go-gin-clean/internal/adapters/primary/http/response"
+	"go-gin-clean/internal/core/ports"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditLogHandler struct {
+	auditLogUseCase ports.AuditLogUseCase
+	auditLogMapper  mappers.AuditLogMapper
+	maxPageSize     int
+}
+
+func NewAuditLogHandler(auditLogUseCase ports.AuditLogUseCase, auditLogMapper mappers.AuditLogMapper, maxPageSize int) *AuditLogHandler {
+	return &AuditLogHandler{
+		auditLogUseCase: auditLogUseCase,
+		auditLogMapper:  auditLogMapper,
+		maxPageSize:     maxPageSize,
+	}
+}
+
+func (h *AuditLogHandler) ListAuditLogs(c *gin.Context) {
+	var req dto.AuditLogQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, messages.FAILED_TO_BIND_QUERY, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	req.PerPage = pageSize(req.PerPage, h.maxPageSize)
+
+	filter := h.auditLogMapper.AuditLogQueryToContract(&req)
+	contractResult, err := h.auditLogUseCase.ListAuditLogs(c.Request.Context(), req.Page, req.PerPage, filter)
+	if err != nil {
+		response.Error(c, messages.FAILED_GET_AUDIT_LOGS, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	result := h.auditLogMapper.PaginationResponseToDTO(contractResult)
+	response.SuccessPagination(c, result.Data, response.SetMeta(req.Page, req.PerPage, result.Total, result.TotalPages))
+}
@@ -0,0 +1,12 @@
+package handlers
+
+const defaultPageSize = 10
+
+// pageSize defaults an unset page size and caps it at maxPageSize so a single
+// request cannot load a whole table.
+func pageSize(size, maxPageSize int) int {
+	if size <= 0 {
+		size = defaultPageSize
+	}
+	return min(size, maxPageSize)
+}
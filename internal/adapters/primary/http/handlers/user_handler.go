@@ -5,8 +5,12 @@ import (
 	"go-gin-clean/internal/adapters/primary/http/mappers"
 	"go-gin-clean/internal/adapters/primary/http/messages"
 	"go-gin-clean/internal/adapters/primary/http/response"
+	"go-gin-clean/internal/core/contracts"
+	"go-gin-clean/internal/core/domain/enums"
+	"go-gin-clean/internal/core/domain/errors"
 	"go-gin-clean/internal/core/ports"
 	"net/http"
+	"slices"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -15,12 +19,16 @@ import (
 type UserHandler struct {
 	userUseCase ports.UserUseCase
 	userMapper  mappers.UserMapper
+	queryMapper mappers.QueryMapper
+	maxPageSize int
 }
 
-func NewUserHandler(userUseCase ports.UserUseCase, userMapper mappers.UserMapper) *UserHandler {
+func NewUserHandler(userUseCase ports.UserUseCase, userMapper mappers.UserMapper, queryMapper mappers.QueryMapper, maxPageSize int) *UserHandler {
 	return &UserHandler{
 		userUseCase: userUseCase,
 		userMapper:  userMapper,
+		queryMapper: queryMapper,
+		maxPageSize: maxPageSize,
 	}
 }
 
@@ -32,21 +40,34 @@ func (h *UserHandler) Login(c *gin.Context) {
 	}
 
 	contractReq := h.userMapper.LoginRequestToContract(&req)
+	contractReq.Client = h.userMapper.ClientInfoToContract(c.Request.UserAgent(), c.ClientIP())
 	contractResult, err := h.userUseCase.Login(c.Request.Context(), contractReq)
 	if err != nil {
-		response.Error(c, messages.FAILED_LOGIN, err.Error(), http.StatusUnauthorized)
+		respondLoginError(c, err)
+		return
+	}
+
+	h.respondLoginSuccess(c, contractResult)
+}
+
+func (h *UserHandler) LoginTwoFactor(c *gin.Context) {
+	var req dto.TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, messages.FAILED_TO_BIND_BODY, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	contractReq := h.userMapper.TwoFactorLoginRequestToContract(&req)
+	contractReq.Client = h.userMapper.ClientInfoToContract(c.Request.UserAgent(), c.ClientIP())
+	contractResult, err := h.userUseCase.LoginTwoFactor(c.Request.Context(), contractReq)
+	if err != nil {
+		respondLoginError(c, err)
 		return
 	}
 
 	result := h.userMapper.LoginResponseToDTO(contractResult)
 
-	http.SetCookie(c.Writer, &http.Cookie{
-		Name:     "refresh_token",
-		Value:    result.RefreshToken,
-		HttpOnly: true,
-		SameSite: http.SameSiteStrictMode,
-		Path:     "/",
-	})
+	setRefreshTokenCookie(c, result.RefreshToken)
 
 	response.Success(c, messages.SUCCESS_LOGIN, gin.H{
 		"access_token": result.AccessToken,
@@ -54,6 +75,89 @@ func (h *UserHandler) Login(c *gin.Context) {
 	}, http.StatusOK)
 }
 
+func (h *UserHandler) SendMagicLink(c *gin.Context) {
+	var req dto.SendMagicLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, messages.FAILED_TO_BIND_BODY, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.userUseCase.SendMagicLink(c.Request.Context(), req.Email); err != nil {
+		if lockedErr, ok := errors.AsLockedError(err); ok {
+			c.Header("Retry-After", strconv.Itoa(lockedErr.RetryAfterSeconds()))
+			response.Error(c, messages.FAILED_SEND_MAGIC_LINK, err.Error(), http.StatusTooManyRequests)
+			return
+		}
+		response.Error(c, messages.FAILED_SEND_MAGIC_LINK, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, messages.SUCCESS_SEND_MAGIC_LINK, nil, http.StatusOK)
+}
+
+func (h *UserHandler) LoginMagicLink(c *gin.Context) {
+	var req dto.MagicLinkLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, messages.FAILED_TO_BIND_BODY, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	contractReq := h.userMapper.MagicLinkLoginRequestToContract(&req)
+	contractReq.Client = h.userMapper.ClientInfoToContract(c.Request.UserAgent(), c.ClientIP())
+	contractResult, err := h.userUseCase.LoginMagicLink(c.Request.Context(), contractReq)
+	if err != nil {
+		response.Error(c, messages.FAILED_MAGIC_LINK_LOGIN, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	h.respondLoginSuccess(c, contractResult)
+}
+
+func (h *UserHandler) OAuthStart(c *gin.Context) {
+	provider := c.Param("provider")
+
+	contractResult, err := h.userUseCase.StartOAuth(c.Request.Context(), provider)
+	if err != nil {
+		if err == errors.ErrOAuthProviderNotFound {
+			response.Error(c, messages.FAILED_OAUTH_LOGIN, err.Error(), http.StatusNotFound)
+			return
+		}
+		response.Error(c, messages.FAILED_OAUTH_LOGIN, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	result := h.userMapper.OAuthStartResponseToDTO(contractResult)
+
+	setOAuthStateCookie(c, result.StateCookie, oauthStateCookieMaxAge)
+	c.Redirect(http.StatusFound, result.AuthURL)
+}
+
+func (h *UserHandler) OAuthCallback(c *gin.Context) {
+	var req dto.OAuthCallbackRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, messages.FAILED_TO_BIND_QUERY, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if req.Error != "" {
+		response.Error(c, messages.FAILED_OAUTH_LOGIN, req.Error+": "+req.ErrorDescription, http.StatusBadRequest)
+		return
+	}
+
+	stateCookie, _ := c.Cookie(oauthStateCookieName)
+	setOAuthStateCookie(c, "", -1)
+
+	contractReq := h.userMapper.OAuthCallbackRequestToContract(c.Param("provider"), stateCookie, &req)
+	contractReq.Client = h.userMapper.ClientInfoToContract(c.Request.UserAgent(), c.ClientIP())
+	contractResult, err := h.userUseCase.CompleteOAuth(c.Request.Context(), contractReq)
+	if err != nil {
+		response.Error(c, messages.FAILED_OAUTH_LOGIN, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	h.respondLoginSuccess(c, contractResult)
+}
+
 func (h *UserHandler) Register(c *gin.Context) {
 	var req dto.RegisterRequest
 	if err := c.ShouldBind(&req); err != nil {
@@ -63,7 +167,7 @@ func (h *UserHandler) Register(c *gin.Context) {
 
 	contractReq := h.userMapper.RegisterRequestToContract(&req)
 	if err := h.userUseCase.Register(c.Request.Context(), contractReq); err != nil {
-		response.Error(c, messages.FAILED_REGISTRATION, err.Error(), http.StatusBadRequest)
+		h.respondPasswordError(c, messages.FAILED_REGISTRATION, err)
 		return
 	}
 
@@ -77,7 +181,9 @@ func (h *UserHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	contractResult, err := h.userUseCase.RefreshToken(c.Request.Context(), cookie)
+	contractReq := h.userMapper.RefreshTokenRequestToContract(cookie)
+	contractReq.Client = h.userMapper.ClientInfoToContract(c.Request.UserAgent(), c.ClientIP())
+	contractResult, err := h.userUseCase.RefreshToken(c.Request.Context(), contractReq)
 	if err != nil {
 		response.Error(c, messages.FAILED_REFRESH_TOKEN, err.Error(), http.StatusUnauthorized)
 		return
@@ -85,13 +191,7 @@ func (h *UserHandler) RefreshToken(c *gin.Context) {
 
 	result := h.userMapper.RefreshTokenResponseToDTO(contractResult)
 
-	http.SetCookie(c.Writer, &http.Cookie{
-		Name:     "refresh_token",
-		Value:    result.RefreshToken,
-		HttpOnly: true,
-		SameSite: http.SameSiteStrictMode,
-		Path:     "/",
-	})
+	setRefreshTokenCookie(c, result.RefreshToken)
 
 	response.Success(c, messages.SUCCESS_REFRESH_TOKEN, result.AccessToken, http.StatusOK)
 }
@@ -103,7 +203,7 @@ func (h *UserHandler) Logout(c *gin.Context) {
 		return
 	}
 
-	err := h.userUseCase.Logout(c.Request.Context(), userID.(int64))
+	err := h.userUseCase.Logout(c.Request.Context(), userID.(int64), currentAccessToken(c))
 	if err != nil {
 		response.Error(c, messages.FAILED_LOGOUT, err.Error(), http.StatusInternalServerError)
 		return
@@ -111,6 +211,59 @@ func (h *UserHandler) Logout(c *gin.Context) {
 	response.Success(c, messages.SUCCESS_LOGOUT, nil, http.StatusOK)
 }
 
+func (h *UserHandler) ListSessions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, messages.FAILED_UNAUTHORIZED, "user credentials not found", http.StatusUnauthorized)
+		return
+	}
+
+	contractResult, err := h.userUseCase.ListSessions(c.Request.Context(), userID.(int64), c.GetString("session_id"))
+	if err != nil {
+		response.Error(c, messages.FAILED_GET_SESSIONS, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	result := h.userMapper.SessionInfosToDTO(contractResult)
+	response.Success(c, messages.SUCCESS_GET_SESSIONS, result, http.StatusOK)
+}
+
+func (h *UserHandler) RevokeSession(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, messages.FAILED_UNAUTHORIZED, "user credentials not found", http.StatusUnauthorized)
+		return
+	}
+
+	sessionID := c.Param("id")
+	if sessionID == "" {
+		response.Error(c, messages.FAILED_PARAMS_REQUIRED, "session id is required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.userUseCase.RevokeSession(c.Request.Context(), userID.(int64), sessionID, currentAccessToken(c)); err != nil {
+		response.Error(c, messages.FAILED_REVOKE_SESSION, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	response.Success(c, messages.SUCCESS_REVOKE_SESSION, nil, http.StatusOK)
+}
+
+func (h *UserHandler) RevokeOtherSessions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, messages.FAILED_UNAUTHORIZED, "user credentials not found", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.userUseCase.RevokeOtherSessions(c.Request.Context(), userID.(int64), c.GetString("session_id")); err != nil {
+		response.Error(c, messages.FAILED_REVOKE_SESSION, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	response.Success(c, messages.SUCCESS_REVOKE_OTHER_SESSIONS, nil, http.StatusOK)
+}
+
 func (h *UserHandler) SendVerifyEmail(c *gin.Context) {
 	var req dto.SendVerifyEmailRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -164,7 +317,8 @@ func (h *UserHandler) ResetPassword(c *gin.Context) {
 
 	contractReq := h.userMapper.ResetPasswordRequestToContract(&req)
 	if err := h.userUseCase.ResetPassword(c.Request.Context(), contractReq); err != nil {
-		response.Error(c, messages.FAILED_RESET_PASSWORD, err.Error(), http.StatusBadRequest)
+		h.respondPasswordError(c, messages.FAILED_RESET_PASSWORD, err)
+		return
 	}
 
 	response.Success(c, messages.SUCCESS_RESET_PASSWORD, nil, http.StatusOK)
@@ -212,20 +366,67 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 }
 
 func (h *UserHandler) GetAllUsers(c *gin.Context) {
-	var req dto.PaginationRequest
+	var req dto.ListRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
 		response.Error(c, messages.FAILED_TO_BIND_QUERY, err.Error(), http.StatusBadRequest)
 		return
 	}
 
+	spec := h.queryMapper.ListRequestToContract(&req, c.Request.URL.Query())
+	if req.UsesCursor() {
+		h.getUsersByCursor(c, &req, spec)
+		return
+	}
+
 	if req.Page <= 0 {
 		req.Page = 1
 	}
-	if req.PerPage <= 0 {
-		req.PerPage = 10
+	req.PerPage = pageSize(req.PerPage, h.maxPageSize)
+
+	contractResult, err := h.userUseCase.GetAllUsers(c.Request.Context(), req.Page, req.PerPage, spec)
+	if err != nil {
+		if _, ok := errors.AsQueryError(err); ok {
+			response.Error(c, messages.FAILED_TO_BIND_QUERY, err.Error(), http.StatusBadRequest)
+			return
+		}
+		response.Error(c, messages.FAILED_GET_ALL_USERS, err.Error(), http.StatusInternalServerError)
+		return
 	}
 
-	contractResult, err := h.userUseCase.GetAllUsers(c.Request.Context(), req.Page, req.PerPage, req.Search)
+	result := h.userMapper.PaginationResponseToDTO(contractResult)
+	response.SuccessPagination(c, result.Data, response.SetMeta(req.Page, req.PerPage, result.Total, result.TotalPages))
+}
+
+func (h *UserHandler) getUsersByCursor(c *gin.Context, req *dto.ListRequest, spec *contracts.QuerySpec) {
+	req.Limit = pageSize(req.Limit, h.maxPageSize)
+
+	contractResult, err := h.userUseCase.GetUsersByCursor(c.Request.Context(), h.queryMapper.CursorRequestToContract(req), spec)
+	if err != nil {
+		if _, ok := errors.AsQueryError(err); ok {
+			response.Error(c, messages.FAILED_TO_BIND_QUERY, err.Error(), http.StatusBadRequest)
+			return
+		}
+		response.Error(c, messages.FAILED_GET_ALL_USERS, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	result := h.userMapper.CursorResponseToDTO(contractResult)
+	response.SuccessPagination(c, result.Data, response.SetCursorMeta(result.Limit, result.NextCursor, result.PrevCursor, result.Total))
+}
+
+func (h *UserHandler) GetDeletedUsers(c *gin.Context) {
+	var req dto.PaginationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, messages.FAILED_TO_BIND_QUERY, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	req.PerPage = pageSize(req.PerPage, h.maxPageSize)
+
+	contractResult, err := h.userUseCase.GetDeletedUsers(c.Request.Context(), req.Page, req.PerPage, req.Search)
 	if err != nil {
 		response.Error(c, messages.FAILED_GET_ALL_USERS, err.Error(), http.StatusInternalServerError)
 		return
@@ -235,6 +436,28 @@ func (h *UserHandler) GetAllUsers(c *gin.Context) {
 	response.SuccessPagination(c, result.Data, response.SetMeta(req.Page, req.PerPage, result.Total, result.TotalPages))
 }
 
+func (h *UserHandler) RestoreUser(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(c, messages.FAILED_TO_BIND_PARAMS, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	contractResult, err := h.userUseCase.RestoreUser(c.Request.Context(), id)
+	if err != nil {
+		code := http.StatusInternalServerError
+		if err == errors.ErrUserNotFound {
+			code = http.StatusNotFound
+		}
+
+		response.Error(c, messages.FAILED_RESTORE_USER, err.Error(), code)
+		return
+	}
+
+	result := h.userMapper.UserInfoToDTO(contractResult)
+	response.Success(c, messages.SUCCESS_RESTORE_USER, result, http.StatusOK)
+}
+
 func (h *UserHandler) GetUserByID(c *gin.Context) {
 	userIDStr := c.Param("id")
 	userID, err := strconv.ParseInt(userIDStr, 10, 64)
@@ -260,10 +483,16 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 		return
 	}
 
+	// Picking roles is role assignment, which users:create alone must not grant.
+	if len(req.Roles) > 0 && !hasPermission(c, enums.PermissionRolesAssign) {
+		response.Error(c, messages.FAILED_CREATE_USER, errors.ErrPermissionDenied.Error(), http.StatusForbidden)
+		return
+	}
+
 	contractReq := h.userMapper.CreateUserRequestToContract(&req)
 	contractResult, err := h.userUseCase.CreateUser(c.Request.Context(), contractReq)
 	if err != nil {
-		response.Error(c, messages.FAILED_CREATE_USER, err.Error(), http.StatusBadRequest)
+		h.respondPasswordError(c, messages.FAILED_CREATE_USER, err)
 		return
 	}
 
@@ -312,13 +541,66 @@ func (h *UserHandler) ChangePassword(c *gin.Context) {
 	contractReq := h.userMapper.ChangePasswordRequestToContract(&req)
 	err := h.userUseCase.ChangePassword(c.Request.Context(), userID.(int64), contractReq)
 	if err != nil {
-		response.Error(c, messages.FAILED_PASSWORD_CHANGE, err.Error(), http.StatusBadRequest)
+		h.respondPasswordError(c, messages.FAILED_PASSWORD_CHANGE, err)
 		return
 	}
 
 	response.Success(c, messages.SUCCESS_PASSWORD_CHANGE, nil, http.StatusOK)
 }
 
+func (h *UserHandler) ChangeEmail(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, messages.FAILED_UNAUTHORIZED, "user credentials not found", http.StatusUnauthorized)
+		return
+	}
+
+	var req dto.ChangeEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, messages.FAILED_TO_BIND_BODY, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	contractReq := h.userMapper.ChangeEmailRequestToContract(&req)
+	if err := h.userUseCase.RequestEmailChange(c.Request.Context(), userID.(int64), contractReq); err != nil {
+		if lockedErr, ok := errors.AsLockedError(err); ok {
+			c.Header("Retry-After", strconv.Itoa(lockedErr.RetryAfterSeconds()))
+			response.Error(c, messages.FAILED_CHANGE_EMAIL, err.Error(), http.StatusTooManyRequests)
+			return
+		}
+
+		code := http.StatusBadRequest
+		if err == errors.ErrEmailAlreadyExists {
+			code = http.StatusConflict
+		}
+
+		response.Error(c, messages.FAILED_CHANGE_EMAIL, err.Error(), code)
+		return
+	}
+
+	response.Success(c, messages.SUCCESS_CHANGE_EMAIL, nil, http.StatusOK)
+}
+
+func (h *UserHandler) ConfirmEmailChange(c *gin.Context) {
+	var req dto.ConfirmEmailChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, messages.FAILED_TO_BIND_BODY, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.userUseCase.ConfirmEmailChange(c.Request.Context(), req.Token); err != nil {
+		code := http.StatusBadRequest
+		if err == errors.ErrEmailAlreadyExists {
+			code = http.StatusConflict
+		}
+
+		response.Error(c, messages.FAILED_CONFIRM_EMAIL_CHANGE, err.Error(), code)
+		return
+	}
+
+	response.Success(c, messages.SUCCESS_CONFIRM_EMAIL_CHANGE, nil, http.StatusOK)
+}
+
 func (h *UserHandler) DeleteUser(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
@@ -335,3 +617,204 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 
 	response.Success(c, messages.SUCCESS_DELETE_USER, nil, http.StatusNoContent)
 }
+
+func (h *UserHandler) AssignRoles(c *gin.Context) {
+	userIDStr := c.Param("id")
+	userID, err := strconv.ParseInt(userIDStr, 10, 64)
+	if err != nil {
+		response.Error(c, messages.FAILED_TO_BIND_PARAMS, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req dto.AssignRolesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, messages.FAILED_TO_BIND_BODY, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	contractReq := h.userMapper.AssignRolesRequestToContract(&req)
+	contractResult, err := h.userUseCase.AssignRoles(c.Request.Context(), userID, contractReq)
+	if err != nil {
+		response.Error(c, messages.FAILED_ASSIGN_ROLES, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result := h.userMapper.UserInfoToDTO(contractResult)
+	response.Success(c, messages.SUCCESS_ASSIGN_ROLES, result, http.StatusOK)
+}
+
+func (h *UserHandler) SetupTwoFactor(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, messages.FAILED_UNAUTHORIZED, "user credentials not found", http.StatusUnauthorized)
+		return
+	}
+
+	contractResult, err := h.userUseCase.SetupTwoFactor(c.Request.Context(), userID.(int64))
+	if err != nil {
+		response.Error(c, messages.FAILED_SETUP_TWO_FACTOR, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result := h.userMapper.TwoFactorSetupResponseToDTO(contractResult)
+	response.Success(c, messages.SUCCESS_SETUP_TWO_FACTOR, result, http.StatusOK)
+}
+
+func (h *UserHandler) EnableTwoFactor(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, messages.FAILED_UNAUTHORIZED, "user credentials not found", http.StatusUnauthorized)
+		return
+	}
+
+	var req dto.TwoFactorEnableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, messages.FAILED_TO_BIND_BODY, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	contractReq := h.userMapper.TwoFactorEnableRequestToContract(&req)
+	contractResult, err := h.userUseCase.EnableTwoFactor(c.Request.Context(), userID.(int64), contractReq)
+	if err != nil {
+		response.Error(c, messages.FAILED_ENABLE_TWO_FACTOR, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result := h.userMapper.TwoFactorEnableResponseToDTO(contractResult)
+	response.Success(c, messages.SUCCESS_ENABLE_TWO_FACTOR, result, http.StatusOK)
+}
+
+func (h *UserHandler) DisableTwoFactor(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, messages.FAILED_UNAUTHORIZED, "user credentials not found", http.StatusUnauthorized)
+		return
+	}
+
+	var req dto.TwoFactorDisableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, messages.FAILED_TO_BIND_BODY, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	contractReq := h.userMapper.TwoFactorDisableRequestToContract(&req)
+	if err := h.userUseCase.DisableTwoFactor(c.Request.Context(), userID.(int64), contractReq); err != nil {
+		if lockedErr, ok := errors.AsLockedError(err); ok {
+			c.Header("Retry-After", strconv.Itoa(lockedErr.RetryAfterSeconds()))
+			response.Error(c, messages.FAILED_DISABLE_TWO_FACTOR, err.Error(), http.StatusTooManyRequests)
+			return
+		}
+
+		response.Error(c, messages.FAILED_DISABLE_TWO_FACTOR, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	response.Success(c, messages.SUCCESS_DISABLE_TWO_FACTOR, nil, http.StatusOK)
+}
+
+func (h *UserHandler) UnlockUser(c *gin.Context) {
+	userIDStr := c.Param("id")
+	userID, err := strconv.ParseInt(userIDStr, 10, 64)
+	if err != nil {
+		response.Error(c, messages.FAILED_TO_BIND_PARAMS, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.userUseCase.UnlockUser(c.Request.Context(), userID); err != nil {
+		response.Error(c, messages.FAILED_UNLOCK_USER, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	response.Success(c, messages.SUCCESS_UNLOCK_USER, nil, http.StatusOK)
+}
+
+// currentAccessToken returns the claims of the access token the request was
+// authenticated with, or nil for API keys.
+func currentAccessToken(c *gin.Context) *contracts.AccessTokenClaims {
+	claims, _ := c.Value("access_token_claims").(*contracts.AccessTokenClaims)
+	return claims
+}
+
+// respondLoginError answers locked accounts with 429 and a Retry-After header.
+func respondLoginError(c *gin.Context, err error) {
+	if lockedErr, ok := errors.AsLockedError(err); ok {
+		c.Header("Retry-After", strconv.Itoa(lockedErr.RetryAfterSeconds()))
+		response.Error(c, messages.FAILED_ACCOUNT_LOCKED, err.Error(), http.StatusTooManyRequests)
+		return
+	}
+
+	response.Error(c, messages.FAILED_LOGIN, err.Error(), http.StatusUnauthorized)
+}
+
+// hasPermission reports whether the authenticated caller holds permission.
+func hasPermission(c *gin.Context, permission enums.Permission) bool {
+	permissions, _ := c.Value("user_permissions").([]string)
+	return slices.Contains(permissions, permission.String())
+}
+
+// respondPasswordError answers password policy violations with 422 and the
+// list of failed rules, lockouts with 429 and any other error with 400.
+func (h *UserHandler) respondPasswordError(c *gin.Context, message string, err error) {
+	if lockedErr, ok := errors.AsLockedError(err); ok {
+		c.Header("Retry-After", strconv.Itoa(lockedErr.RetryAfterSeconds()))
+		response.Error(c, message, err.Error(), http.StatusTooManyRequests)
+		return
+	}
+
+	if policyErr, ok := errors.AsPasswordPolicyError(err); ok {
+		violations := h.userMapper.PasswordViolationsToDTO(policyErr.Violations)
+		response.ErrorWithDetails(c, message, err.Error(), violations, http.StatusUnprocessableEntity)
+		return
+	}
+
+	response.Error(c, message, err.Error(), http.StatusBadRequest)
+}
+
+// respondLoginSuccess returns either the MFA challenge or the issued tokens,
+// with the refresh token set as an HttpOnly cookie.
+func (h *UserHandler) respondLoginSuccess(c *gin.Context, contractResult *contracts.LoginResponse) {
+	result := h.userMapper.LoginResponseToDTO(contractResult)
+
+	if result.MFARequired {
+		response.Success(c, messages.SUCCESS_MFA_REQUIRED, gin.H{
+			"mfa_required": true,
+			"mfa_token":    result.MFAToken,
+		}, http.StatusOK)
+		return
+	}
+
+	setRefreshTokenCookie(c, result.RefreshToken)
+
+	response.Success(c, messages.SUCCESS_LOGIN, gin.H{
+		"access_token": result.AccessToken,
+		"user":         result.User,
+	}, http.StatusOK)
+}
+
+func setRefreshTokenCookie(c *gin.Context, refreshToken string) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     "refresh_token",
+		Value:    refreshToken,
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+		Path:     "/",
+	})
+}
+
+const (
+	oauthStateCookieName   = "oauth_state"
+	oauthStateCookieMaxAge = 600
+)
+
+// setOAuthStateCookie uses SameSite=Lax so the cookie is sent on the
+// top-level redirect back from the provider.
+func setOAuthStateCookie(c *gin.Context, value string, maxAge int) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     oauthStateCookieName,
+		Value:    value,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+		Path:     "/api/v1/auth/oauth",
+	})
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"go-gin-clean/internal/adapters/primary/http/mappers"
+	"go-gin-clean/internal/core/ports"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WellKnownHandler struct {
+	jwtService ports.JWTService
+	keyMapper  mappers.KeyMapper
+}
+
+func NewWellKnownHandler(jwtService ports.JWTService, keyMapper mappers.KeyMapper) *WellKnownHandler {
+	return &WellKnownHandler{
+		jwtService: jwtService,
+		keyMapper:  keyMapper,
+	}
+}
+
+// JWKS publishes the access token verification keys. The body follows RFC 7517
+// instead of the usual response envelope so standard JWT libraries can use it.
+func (h *WellKnownHandler) JWKS(c *gin.Context) {
+	result := h.keyMapper.JSONWebKeySetToDTO(h.jwtService.PublicKeys())
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,55 @@
+package mappers
+
+import (
+	"go-gin-clean/internal/adapters/primary/http/dto"
+	"go-gin-clean/internal/core/contracts"
+)
+
+// apiKeyMapper implements the APIKeyMapper interface
+type apiKeyMapper struct{}
+
+// NewAPIKeyMapper creates a new api key mapper
+func NewAPIKeyMapper() APIKeyMapper {
+	return &apiKeyMapper{}
+}
+
+func (m *apiKeyMapper) CreateAPIKeyRequestToContract(req *dto.CreateAPIKeyRequest) *contracts.CreateAPIKeyRequest {
+	return &contracts.CreateAPIKeyRequest{
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+	}
+}
+
+func (m *apiKeyMapper) CreateAPIKeyResponseToDTO(resp *contracts.CreateAPIKeyResponse) *dto.CreateAPIKeyResponse {
+	return &dto.CreateAPIKeyResponse{
+		Key:    resp.Key,
+		APIKey: m.APIKeyInfoToDTO(resp.APIKey),
+	}
+}
+
+func (m *apiKeyMapper) APIKeyInfoToDTO(key contracts.APIKeyInfo) dto.APIKeyInfo {
+	scopes := key.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+
+	return dto.APIKeyInfo{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     scopes,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
+
+func (m *apiKeyMapper) APIKeyInfosToDTO(keys []contracts.APIKeyInfo) []dto.APIKeyInfo {
+	dtoKeys := make([]dto.APIKeyInfo, len(keys))
+	for i, key := range keys {
+		dtoKeys[i] = m.APIKeyInfoToDTO(key)
+	}
+
+	return dtoKeys
+}
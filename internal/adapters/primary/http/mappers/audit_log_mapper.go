@@ -0,0 +1,49 @@
+package mappers
+
+import (
+	"go-gin-clean/internal/adapters/primary/http/dto"
+	"go-gin-clean/internal/core/contracts"
+)
+
+// auditLogMapper implements the AuditLogMapper interface
+type auditLogMapper struct{}
+
+// NewAuditLogMapper creates a new audit log mapper
+func NewAuditLogMapper() AuditLogMapper {
+	return &auditLogMapper{}
+}
+
+func (m *auditLogMapper) AuditLogQueryToContract(req *dto.AuditLogQuery) *contracts.AuditLogFilter {
+	return &contracts.AuditLogFilter{
+		ActorID:  req.ActorID,
+		TargetID: req.TargetID,
+		Action:   req.Action,
+		From:     req.From,
+		To:       req.To,
+	}
+}
+
+func (m *auditLogMapper) PaginationResponseToDTO(resp *contracts.PaginationResponse[contracts.AuditLogInfo]) *dto.PaginationResponse[dto.AuditLogInfo] {
+	dtoLogs := make([]dto.AuditLogInfo, len(resp.Data))
+	for i, entry := range resp.Data {
+		dtoLogs[i] = dto.AuditLogInfo{
+			ID:        entry.ID,
+			ActorID:   entry.ActorID,
+			TargetID:  entry.TargetID,
+			Action:    entry.Action,
+			IPAddress: entry.IPAddress,
+			UserAgent: entry.UserAgent,
+			Changes:   entry.Changes,
+			Details:   entry.Details,
+			CreatedAt: entry.CreatedAt,
+		}
+	}
+
+	return &dto.PaginationResponse[dto.AuditLogInfo]{
+		Data:       dtoLogs,
+		Page:       resp.Page,
+		PerPage:    resp.PerPage,
+		Total:      resp.Total,
+		TotalPages: resp.TotalPages,
+	}
+}
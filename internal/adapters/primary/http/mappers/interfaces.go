@@ -3,26 +3,64 @@ package mappers
 import (
 	"go-gin-clean/internal/adapters/primary/http/dto"
 	"go-gin-clean/internal/core/contracts"
+	"go-gin-clean/internal/core/domain/errors"
+	"net/url"
 )
 
 type UserMapper interface {
 	// DTO to Contract mappings
 	LoginRequestToContract(req *dto.LoginRequest) *contracts.LoginRequest
+	ClientInfoToContract(userAgent, ipAddress string) contracts.ClientInfo
+	RefreshTokenRequestToContract(refreshToken string) *contracts.RefreshTokenRequest
+	TwoFactorLoginRequestToContract(req *dto.TwoFactorLoginRequest) *contracts.TwoFactorLoginRequest
+	TwoFactorEnableRequestToContract(req *dto.TwoFactorEnableRequest) *contracts.TwoFactorEnableRequest
+	TwoFactorDisableRequestToContract(req *dto.TwoFactorDisableRequest) *contracts.TwoFactorDisableRequest
+	MagicLinkLoginRequestToContract(req *dto.MagicLinkLoginRequest) *contracts.MagicLinkLoginRequest
+	OAuthCallbackRequestToContract(provider, stateCookie string, req *dto.OAuthCallbackRequest) *contracts.OAuthCallbackRequest
 	RegisterRequestToContract(req *dto.RegisterRequest) *contracts.RegisterRequest
 	ResetPasswordRequestToContract(req *dto.ResetPasswordRequest) *contracts.ResetPasswordRequest
 	ChangePasswordRequestToContract(req *dto.ChangePasswordRequest) *contracts.ChangePasswordRequest
+	ChangeEmailRequestToContract(req *dto.ChangeEmailRequest) *contracts.ChangeEmailRequest
 	CreateUserRequestToContract(req *dto.CreateUserRequest) *contracts.CreateUserRequest
 	UpdateUserRequestToContract(req *dto.UpdateUserRequest) *contracts.UpdateUserRequest
+	AssignRolesRequestToContract(req *dto.AssignRolesRequest) *contracts.AssignRolesRequest
 	PaginationRequestToContract(req *dto.PaginationRequest) *contracts.PaginationRequest
 
 	// Contract to DTO mappings
 	LoginResponseToDTO(resp *contracts.LoginResponse) *dto.LoginResponse
+	OAuthStartResponseToDTO(resp *contracts.OAuthStartResponse) *dto.OAuthStartResponse
 	RefreshTokenResponseToDTO(resp *contracts.RefreshTokenResponse) *dto.RefreshTokenResponse
+	TwoFactorSetupResponseToDTO(resp *contracts.TwoFactorSetupResponse) *dto.TwoFactorSetupResponse
+	TwoFactorEnableResponseToDTO(resp *contracts.TwoFactorEnableResponse) *dto.TwoFactorEnableResponse
 	UserInfoToDTO(user *contracts.UserInfo) *dto.UserInfo
+	SessionInfosToDTO(sessions []contracts.SessionInfo) []dto.SessionInfo
+	PasswordViolationsToDTO(violations []errors.PasswordViolation) []dto.PasswordViolation
 	PaginationResponseToDTO(resp *contracts.PaginationResponse[contracts.UserInfo]) *dto.PaginationResponse[dto.UserInfo]
+	CursorResponseToDTO(resp *contracts.CursorResponse[contracts.UserInfo]) *dto.CursorResponse[dto.UserInfo]
+}
+
+type QueryMapper interface {
+	ListRequestToContract(req *dto.ListRequest, params url.Values) *contracts.QuerySpec
+	CursorRequestToContract(req *dto.ListRequest) *contracts.CursorRequest
 }
 
 type PaginationMapper interface {
 	RequestToContract(req *dto.PaginationRequest) *contracts.PaginationRequest
 	UserInfoResponseToDTO(resp *contracts.PaginationResponse[contracts.UserInfo]) *dto.PaginationResponse[dto.UserInfo]
 }
+
+type APIKeyMapper interface {
+	CreateAPIKeyRequestToContract(req *dto.CreateAPIKeyRequest) *contracts.CreateAPIKeyRequest
+	CreateAPIKeyResponseToDTO(resp *contracts.CreateAPIKeyResponse) *dto.CreateAPIKeyResponse
+	APIKeyInfoToDTO(key contracts.APIKeyInfo) dto.APIKeyInfo
+	APIKeyInfosToDTO(keys []contracts.APIKeyInfo) []dto.APIKeyInfo
+}
+
+type AuditLogMapper interface {
+	AuditLogQueryToContract(req *dto.AuditLogQuery) *contracts.AuditLogFilter
+	PaginationResponseToDTO(resp *contracts.PaginationResponse[contracts.AuditLogInfo]) *dto.PaginationResponse[dto.AuditLogInfo]
+}
+
+type KeyMapper interface {
+	JSONWebKeySetToDTO(keys []contracts.JSONWebKey) *dto.JSONWebKeySet
+}
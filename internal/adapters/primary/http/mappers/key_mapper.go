@@ -0,0 +1,34 @@
+package mappers
+
+import (
+	"go-gin-clean/internal/adapters/primary/http/dto"
+	"go-gin-clean/internal/core/contracts"
+)
+
+// keyMapper implements the KeyMapper interface
+type keyMapper struct{}
+
+// NewKeyMapper creates a new key mapper
+func NewKeyMapper() KeyMapper {
+	return &keyMapper{}
+}
+
+func (m *keyMapper) JSONWebKeySetToDTO(keys []contracts.JSONWebKey) *dto.JSONWebKeySet {
+	dtoKeys := make([]dto.JSONWebKey, len(keys))
+	for i, key := range keys {
+		dtoKeys[i] = dto.JSONWebKey{
+			KeyType:   key.KeyType,
+			KeyID:     key.KeyID,
+			Use:       key.Use,
+			Algorithm: key.Algorithm,
+			Modulus:   key.Modulus,
+			Exponent:  key.Exponent,
+			Curve:     key.Curve,
+			X:         key.X,
+		}
+	}
+
+	return &dto.JSONWebKeySet{
+		Keys: dtoKeys,
+	}
+}
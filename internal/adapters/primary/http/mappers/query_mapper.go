@@ -0,0 +1,83 @@
+package mappers
+
+import (
+	"go-gin-clean/internal/adapters/primary/http/dto"
+	"go-gin-clean/internal/core/contracts"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// listParams are the query parameters of a list request that are not filters.
+var listParams = map[string]bool{
+	"page":          true,
+	"per_page":      true,
+	"search":        true,
+	"sort":          true,
+	"cursor":        true,
+	"limit":         true,
+	"include_total": true,
+}
+
+// queryMapper implements the QueryMapper interface
+type queryMapper struct{}
+
+// NewQueryMapper creates a new query mapper
+func NewQueryMapper() QueryMapper {
+	return &queryMapper{}
+}
+
+// ListRequestToContract only splits the parameters into filters and sort
+// keys. Whether a field may be used is decided by the entity whitelist.
+func (m *queryMapper) ListRequestToContract(req *dto.ListRequest, params url.Values) *contracts.QuerySpec {
+	spec := &contracts.QuerySpec{
+		Search: req.Search,
+	}
+
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		if !listParams[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		field, operator := key, contracts.FilterEq
+		if open := strings.IndexByte(key, '['); open > 0 && strings.HasSuffix(key, "]") {
+			field = key[:open]
+			operator = contracts.FilterOperator(key[open+1 : len(key)-1])
+		}
+
+		for _, value := range params[key] {
+			spec.Filters = append(spec.Filters, contracts.Filter{
+				Field:    field,
+				Operator: operator,
+				Value:    value,
+			})
+		}
+	}
+
+	for _, key := range strings.Split(req.Sort, ",") {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+
+		desc := strings.HasPrefix(key, "-")
+		spec.Sort = append(spec.Sort, contracts.Sort{
+			Field: strings.TrimLeft(key, "+-"),
+			Desc:  desc,
+		})
+	}
+
+	return spec
+}
+
+func (m *queryMapper) CursorRequestToContract(req *dto.ListRequest) *contracts.CursorRequest {
+	return &contracts.CursorRequest{
+		Cursor:       req.Cursor,
+		Limit:        req.Limit,
+		IncludeTotal: req.IncludeTotal,
+	}
+}
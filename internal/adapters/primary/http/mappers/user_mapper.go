@@ -6,6 +6,7 @@ import (
 
 	"go-gin-clean/internal/adapters/primary/http/dto"
 	"go-gin-clean/internal/core/contracts"
+	"go-gin-clean/internal/core/domain/errors"
 )
 
 // userMapper implements the UserMapper interface
@@ -24,6 +25,54 @@ func (m *userMapper) LoginRequestToContract(req *dto.LoginRequest) *contracts.Lo
 	}
 }
 
+func (m *userMapper) ClientInfoToContract(userAgent, ipAddress string) contracts.ClientInfo {
+	return contracts.ClientInfo{
+		UserAgent: userAgent,
+		IPAddress: ipAddress,
+	}
+}
+
+func (m *userMapper) RefreshTokenRequestToContract(refreshToken string) *contracts.RefreshTokenRequest {
+	return &contracts.RefreshTokenRequest{
+		RefreshToken: refreshToken,
+	}
+}
+
+func (m *userMapper) TwoFactorLoginRequestToContract(req *dto.TwoFactorLoginRequest) *contracts.TwoFactorLoginRequest {
+	return &contracts.TwoFactorLoginRequest{
+		MFAToken: req.MFAToken,
+		Code:     req.Code,
+	}
+}
+
+func (m *userMapper) TwoFactorEnableRequestToContract(req *dto.TwoFactorEnableRequest) *contracts.TwoFactorEnableRequest {
+	return &contracts.TwoFactorEnableRequest{
+		Code: req.Code,
+	}
+}
+
+func (m *userMapper) TwoFactorDisableRequestToContract(req *dto.TwoFactorDisableRequest) *contracts.TwoFactorDisableRequest {
+	return &contracts.TwoFactorDisableRequest{
+		Password: req.Password,
+		Code:     req.Code,
+	}
+}
+
+func (m *userMapper) MagicLinkLoginRequestToContract(req *dto.MagicLinkLoginRequest) *contracts.MagicLinkLoginRequest {
+	return &contracts.MagicLinkLoginRequest{
+		Token: req.Token,
+	}
+}
+
+func (m *userMapper) OAuthCallbackRequestToContract(provider, stateCookie string, req *dto.OAuthCallbackRequest) *contracts.OAuthCallbackRequest {
+	return &contracts.OAuthCallbackRequest{
+		Provider:    provider,
+		Code:        req.Code,
+		State:       req.State,
+		StateCookie: stateCookie,
+	}
+}
+
 func (m *userMapper) RegisterRequestToContract(req *dto.RegisterRequest) *contracts.RegisterRequest {
 	return &contracts.RegisterRequest{
 		Name:     req.Name,
@@ -46,12 +95,20 @@ func (m *userMapper) ChangePasswordRequestToContract(req *dto.ChangePasswordRequ
 	}
 }
 
+func (m *userMapper) ChangeEmailRequestToContract(req *dto.ChangeEmailRequest) *contracts.ChangeEmailRequest {
+	return &contracts.ChangeEmailRequest{
+		NewEmail: req.NewEmail,
+		Password: req.Password,
+	}
+}
+
 func (m *userMapper) CreateUserRequestToContract(req *dto.CreateUserRequest) *contracts.CreateUserRequest {
 	return &contracts.CreateUserRequest{
 		Name:     req.Name,
 		Email:    req.Email,
 		Password: req.Password,
 		Gender:   req.Gender,
+		Roles:    req.Roles,
 	}
 }
 
@@ -82,6 +139,12 @@ func (m *userMapper) UpdateUserRequestToContract(req *dto.UpdateUserRequest) *co
 	return contractReq
 }
 
+func (m *userMapper) AssignRolesRequestToContract(req *dto.AssignRolesRequest) *contracts.AssignRolesRequest {
+	return &contracts.AssignRolesRequest{
+		Roles: req.Roles,
+	}
+}
+
 func (m *userMapper) PaginationRequestToContract(req *dto.PaginationRequest) *contracts.PaginationRequest {
 	return &contracts.PaginationRequest{
 		Page:    req.Page,
@@ -96,6 +159,15 @@ func (m *userMapper) LoginResponseToDTO(resp *contracts.LoginResponse) *dto.Logi
 		AccessToken:  resp.AccessToken,
 		RefreshToken: resp.RefreshToken,
 		User:         *m.UserInfoToDTO(&resp.User),
+		MFARequired:  resp.MFARequired,
+		MFAToken:     resp.MFAToken,
+	}
+}
+
+func (m *userMapper) OAuthStartResponseToDTO(resp *contracts.OAuthStartResponse) *dto.OAuthStartResponse {
+	return &dto.OAuthStartResponse{
+		AuthURL:     resp.AuthURL,
+		StateCookie: resp.StateCookie,
 	}
 }
 
@@ -106,7 +178,28 @@ func (m *userMapper) RefreshTokenResponseToDTO(resp *contracts.RefreshTokenRespo
 	}
 }
 
+func (m *userMapper) TwoFactorSetupResponseToDTO(resp *contracts.TwoFactorSetupResponse) *dto.TwoFactorSetupResponse {
+	return &dto.TwoFactorSetupResponse{
+		Secret: resp.Secret,
+		URI:    resp.URI,
+	}
+}
+
+func (m *userMapper) TwoFactorEnableResponseToDTO(resp *contracts.TwoFactorEnableResponse) *dto.TwoFactorEnableResponse {
+	return &dto.TwoFactorEnableResponse{
+		RecoveryCodes: resp.RecoveryCodes,
+	}
+}
+
 func (m *userMapper) UserInfoToDTO(user *contracts.UserInfo) *dto.UserInfo {
+	var match *dto.SearchMatch
+	if user.Match != nil {
+		match = &dto.SearchMatch{
+			Field:     user.Match.Field,
+			Highlight: user.Match.Highlight,
+		}
+	}
+
 	return &dto.UserInfo{
 		ID:       user.ID,
 		Name:     user.Name,
@@ -114,7 +207,43 @@ func (m *userMapper) UserInfoToDTO(user *contracts.UserInfo) *dto.UserInfo {
 		Avatar:   user.Avatar,
 		Gender:   user.Gender,
 		IsActive: user.IsActive,
+		Roles:    user.Roles,
+
+		PendingEmail:     user.PendingEmail,
+		TwoFactorEnabled: user.TwoFactorEnabled,
+		DeletedAt:        user.DeletedAt,
+
+		Match: match,
+	}
+}
+
+func (m *userMapper) SessionInfosToDTO(sessions []contracts.SessionInfo) []dto.SessionInfo {
+	dtoSessions := make([]dto.SessionInfo, len(sessions))
+	for i, session := range sessions {
+		dtoSessions[i] = dto.SessionInfo{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    session.Current,
+		}
 	}
+
+	return dtoSessions
+}
+
+func (m *userMapper) PasswordViolationsToDTO(violations []errors.PasswordViolation) []dto.PasswordViolation {
+	dtoViolations := make([]dto.PasswordViolation, len(violations))
+	for i, violation := range violations {
+		dtoViolations[i] = dto.PasswordViolation{
+			Code:    violation.Code,
+			Message: violation.Message,
+		}
+	}
+
+	return dtoViolations
 }
 
 func (m *userMapper) PaginationResponseToDTO(resp *contracts.PaginationResponse[contracts.UserInfo]) *dto.PaginationResponse[dto.UserInfo] {
@@ -131,3 +260,18 @@ func (m *userMapper) PaginationResponseToDTO(resp *contracts.PaginationResponse[
 		TotalPages: resp.TotalPages,
 	}
 }
+
+func (m *userMapper) CursorResponseToDTO(resp *contracts.CursorResponse[contracts.UserInfo]) *dto.CursorResponse[dto.UserInfo] {
+	dtoUsers := make([]dto.UserInfo, len(resp.Data))
+	for i, user := range resp.Data {
+		dtoUsers[i] = *m.UserInfoToDTO(&user)
+	}
+
+	return &dto.CursorResponse[dto.UserInfo]{
+		Data:       dtoUsers,
+		Limit:      resp.Limit,
+		NextCursor: resp.NextCursor,
+		PrevCursor: resp.PrevCursor,
+		Total:      resp.Total,
+	}
+}
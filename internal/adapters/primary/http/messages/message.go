@@ -39,6 +39,24 @@ const (
 	FAILED_UPDATE_USER               = "Failed to update user"
 	FAILED_REFRESH_TOKEN             = "Failed to refresh token"
 	FAILED_VERIFY_EMAIL              = "Email verification failed"
+	FAILED_ASSIGN_ROLES              = "Failed to assign roles"
+	FAILED_SETUP_TWO_FACTOR          = "Failed to set up two-factor authentication"
+	FAILED_ENABLE_TWO_FACTOR         = "Failed to enable two-factor authentication"
+	FAILED_DISABLE_TWO_FACTOR        = "Failed to disable two-factor authentication"
+	FAILED_GET_SESSIONS              = "Failed to get sessions"
+	FAILED_REVOKE_SESSION            = "Failed to revoke session"
+	FAILED_ACCOUNT_LOCKED            = "Too many failed login attempts"
+	FAILED_UNLOCK_USER               = "Failed to unlock user"
+	FAILED_OAUTH_LOGIN               = "Social login failed"
+	FAILED_SEND_MAGIC_LINK           = "Failed to send sign-in link"
+	FAILED_MAGIC_LINK_LOGIN          = "Sign-in link is invalid or expired"
+	FAILED_CREATE_API_KEY            = "Failed to create API key"
+	FAILED_GET_API_KEYS              = "Failed to get API keys"
+	FAILED_REVOKE_API_KEY            = "Failed to revoke API key"
+	FAILED_CHANGE_EMAIL              = "Failed to request email change"
+	FAILED_CONFIRM_EMAIL_CHANGE      = "Email change confirmation failed"
+	FAILED_GET_AUDIT_LOGS            = "Failed to get audit logs"
+	FAILED_RESTORE_USER              = "Failed to restore user"
 
 	SUCCESS_LOGIN                     = "Login successful"
 	SUCCESS_REGISTRATION              = "Registration successful, please verify your email"
@@ -59,4 +77,20 @@ const (
 	SUCCESS_UPDATE_USER               = "User updated successfully"
 	SUCCESS_REFRESH_TOKEN             = "Token refreshed successfully"
 	SUCCESS_VERIFY_EMAIL              = "Email verified successfully"
+	SUCCESS_ASSIGN_ROLES              = "Roles assigned successfully"
+	SUCCESS_MFA_REQUIRED              = "Two-factor authentication required"
+	SUCCESS_SETUP_TWO_FACTOR          = "Scan the QR code and confirm with a code to enable two-factor authentication"
+	SUCCESS_ENABLE_TWO_FACTOR         = "Two-factor authentication enabled, store your recovery codes safely"
+	SUCCESS_DISABLE_TWO_FACTOR        = "Two-factor authentication disabled"
+	SUCCESS_GET_SESSIONS              = "Sessions retrieved successfully"
+	SUCCESS_REVOKE_SESSION            = "Session revoked successfully"
+	SUCCESS_REVOKE_OTHER_SESSIONS     = "All other sessions revoked successfully"
+	SUCCESS_UNLOCK_USER               = "User unlocked successfully"
+	SUCCESS_SEND_MAGIC_LINK           = "If the account exists, a sign-in link has been sent"
+	SUCCESS_CREATE_API_KEY            = "API key created, copy it now as it will not be shown again"
+	SUCCESS_GET_API_KEYS              = "API keys retrieved successfully"
+	SUCCESS_REVOKE_API_KEY            = "API key revoked successfully"
+	SUCCESS_CHANGE_EMAIL              = "Confirmation link sent to the new email address"
+	SUCCESS_CONFIRM_EMAIL_CHANGE      = "Email changed successfully, please log in again"
+	SUCCESS_RESTORE_USER              = "User restored successfully"
 )
@@ -3,26 +3,40 @@ package http
 import (
 	"go-gin-clean/internal/adapters/primary/http/messages"
 	"go-gin-clean/internal/adapters/primary/http/response"
+	"go-gin-clean/internal/core/contracts"
+	"go-gin-clean/internal/core/domain/enums"
 	"go-gin-clean/internal/core/domain/errors"
 	"go-gin-clean/internal/core/ports"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
 type AuthMiddleware struct {
-	jwtService ports.JWTService
+	jwtService      ports.JWTService
+	apiKeyUseCase   ports.APIKeyUseCase
+	tokenRevocation ports.TokenRevocationUseCase
 }
 
-func NewAuthMiddleware(jwtService ports.JWTService) *AuthMiddleware {
+func NewAuthMiddleware(jwtService ports.JWTService, apiKeyUseCase ports.APIKeyUseCase, tokenRevocation ports.TokenRevocationUseCase) *AuthMiddleware {
 	return &AuthMiddleware{
-		jwtService: jwtService,
+		jwtService:      jwtService,
+		apiKeyUseCase:   apiKeyUseCase,
+		tokenRevocation: tokenRevocation,
 	}
 }
 
+// RequireAuth accepts a Bearer access token, or a personal API key sent as
+// "Authorization: ApiKey <key>" or in the X-API-Key header.
 func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if apiKey := extractAPIKey(c); apiKey != "" {
+			m.authenticateAPIKey(c, apiKey)
+			return
+		}
+
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
 			response.Error(c, messages.FAILED_AUTHENTICATION_REQUIRED, errors.ErrAuthHeaderMissing.Error(), http.StatusUnauthorized)
@@ -50,18 +64,123 @@ func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 			return
 		}
 
+		// Fail closed: a token cannot be trusted while the denylist is unreachable.
+		revoked, err := m.tokenRevocation.IsRevoked(c.Request.Context(), claims)
+		if err != nil || revoked {
+			response.Error(c, messages.FAILED_INVALID_TOKEN_FORMAT, errors.ErrTokenRevoked.Error(), http.StatusUnauthorized)
+			c.Abort()
+			return
+		}
+
+		setAuditActor(c, claims.UserID)
+
 		c.Set("user_id", claims.UserID)
 		c.Set("user_email", claims.Email)
+		c.Set("user_roles", claims.Roles)
+		c.Set("user_permissions", claims.Permissions)
+		c.Set("session_id", claims.SessionID)
+		c.Set("access_token_claims", claims)
+
+		c.Next()
+	}
+}
+
+func (m *AuthMiddleware) authenticateAPIKey(c *gin.Context, apiKey string) {
+	principal, err := m.apiKeyUseCase.Authenticate(c.Request.Context(), apiKey)
+	if err != nil {
+		response.Error(c, messages.FAILED_UNAUTHORIZED, errors.ErrAPIKeyInvalid.Error(), http.StatusUnauthorized)
+		c.Abort()
+		return
+	}
+
+	setAuditActor(c, principal.UserID)
+
+	c.Set("user_id", principal.UserID)
+	c.Set("user_email", principal.Email)
+	c.Set("user_roles", principal.Roles)
+	c.Set("user_permissions", principal.Permissions)
+	c.Set("session_id", "")
+	c.Set("api_key_id", principal.KeyID)
+
+	c.Next()
+}
+
+func extractAPIKey(c *gin.Context) string {
+	if key := c.GetHeader("X-API-Key"); key != "" {
+		return key
+	}
+
+	if authHeader := c.GetHeader("Authorization"); strings.HasPrefix(authHeader, "ApiKey ") {
+		return strings.TrimSpace(strings.TrimPrefix(authHeader, "ApiKey "))
+	}
+
+	return ""
+}
+
+// RequirePermission must run after RequireAuth. It rejects the request unless
+// the access token carries the given permission.
+func (m *AuthMiddleware) RequirePermission(permission enums.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, exists := c.Get("user_permissions")
+		if !exists {
+			response.Error(c, messages.FAILED_UNAUTHORIZED, errors.ErrPermissionDenied.Error(), http.StatusUnauthorized)
+			c.Abort()
+			return
+		}
+
+		permissions, ok := value.([]string)
+		if !ok || !slices.Contains(permissions, permission.String()) {
+			response.Error(c, messages.FAILED_FORBIDDEN, errors.ErrPermissionDenied.Error(), http.StatusForbidden)
+			c.Abort()
+			return
+		}
 
 		c.Next()
 	}
 }
 
+// RequireSession must run after RequireAuth. It rejects requests made with
+// an API key so keys cannot be used to mint further credentials.
+func (m *AuthMiddleware) RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, viaAPIKey := c.Get("api_key_id"); viaAPIKey {
+			response.Error(c, messages.FAILED_FORBIDDEN, errors.ErrAPIKeyNotAllowed.Error(), http.StatusForbidden)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// AuditContext stores the client IP and user agent in the request context so
+// use cases can attribute audit log entries. RequireAuth adds the user.
+func AuditContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := contracts.WithAuditActor(c.Request.Context(), contracts.AuditActor{
+			IPAddress: c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		})
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
+func setAuditActor(c *gin.Context, userID int64) {
+	ctx := contracts.WithAuditActor(c.Request.Context(), contracts.AuditActor{
+		UserID:    &userID,
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	})
+	c.Request = c.Request.WithContext(ctx)
+}
+
 func CORS() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Length, Content-Type, Authorization, X-Refresh-Token")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Length, Content-Type, Authorization, X-Refresh-Token, X-API-Key")
 		c.Header("Access-Control-Expose-Headers", "Content-Length")
 		c.Header("Access-Control-Allow-Credentials", "true")
 
@@ -9,25 +9,40 @@ type Response struct {
 	Message string `json:"message"`
 	Data    any    `json:"data,omitempty"`
 	Error   string `json:"error,omitempty"`
+	Details any    `json:"details,omitempty"`
 	Meta    *Meta  `json:"meta,omitempty"`
 }
 
+// Meta describes a page. Page-based lists fill Page to TotalPages; cursor
+// lists fill Limit and the cursors, and Total only when it was requested.
 type Meta struct {
-	Page       int `json:"page"`
-	PerPage    int `json:"per_page"`
-	Total      int `json:"total"`
-	TotalPages int `json:"total_pages"`
+	Page       int    `json:"page,omitempty"`
+	PerPage    int    `json:"per_page,omitempty"`
+	Limit      int    `json:"limit,omitempty"`
+	Total      *int   `json:"total,omitempty"`
+	TotalPages int    `json:"total_pages,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
 }
 
 func SetMeta(page, perPage, total, totalPages int) Meta {
 	return Meta{
 		Page:       page,
 		PerPage:    perPage,
-		Total:      total,
+		Total:      &total,
 		TotalPages: totalPages,
 	}
 }
 
+func SetCursorMeta(limit int, nextCursor, prevCursor string, total *int) Meta {
+	return Meta{
+		Limit:      limit,
+		Total:      total,
+		NextCursor: nextCursor,
+		PrevCursor: prevCursor,
+	}
+}
+
 func Success(c *gin.Context, message string, data any, code int) {
 	c.JSON(code, Response{
 		Status:  true,
@@ -44,6 +59,15 @@ func Error(c *gin.Context, message string, err string, code int) {
 	})
 }
 
+func ErrorWithDetails(c *gin.Context, message string, err string, details any, code int) {
+	c.JSON(code, Response{
+		Status:  false,
+		Message: message,
+		Error:   err,
+		Details: details,
+	})
+}
+
 func SuccessPagination(c *gin.Context, data any, meta Meta) {
 	c.JSON(200, Response{
 		Status:  true,
@@ -3,6 +3,7 @@ package http
 import (
 	"go-gin-clean/internal/adapters/primary/http/handlers"
 	"go-gin-clean/internal/adapters/primary/http/mappers"
+	"go-gin-clean/internal/core/domain/enums"
 	"go-gin-clean/internal/core/ports"
 
 	"github.com/gin-gonic/gin"
@@ -11,17 +12,29 @@ import (
 func SetupRoutes(
 	router *gin.Engine,
 	userUseCase ports.UserUseCase,
+	apiKeyUseCase ports.APIKeyUseCase,
+	auditLogUseCase ports.AuditLogUseCase,
+	tokenRevocation ports.TokenRevocationUseCase,
 	jwtService ports.JWTService,
+	maxPageSize int,
 ) {
 	// Setup mappers
 	userMapper := mappers.NewUserMapper()
+	queryMapper := mappers.NewQueryMapper()
+	keyMapper := mappers.NewKeyMapper()
+	apiKeyMapper := mappers.NewAPIKeyMapper()
+	auditLogMapper := mappers.NewAuditLogMapper()
 
 	// Setup handlers
-	userHandler := handlers.NewUserHandler(userUseCase, userMapper)
-	authMiddleware := NewAuthMiddleware(jwtService)
+	userHandler := handlers.NewUserHandler(userUseCase, userMapper, queryMapper, maxPageSize)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyUseCase, apiKeyMapper)
+	auditLogHandler := handlers.NewAuditLogHandler(auditLogUseCase, auditLogMapper, maxPageSize)
+	wellKnownHandler := handlers.NewWellKnownHandler(jwtService, keyMapper)
+	authMiddleware := NewAuthMiddleware(jwtService, apiKeyUseCase, tokenRevocation)
 
 	// Setup CORS
 	router.Use(CORS())
+	router.Use(AuditContext())
 
 	// API routes
 	api := router.Group("/api/v1")
@@ -30,9 +43,15 @@ func SetupRoutes(
 		auth := api.Group("/auth")
 		{
 			auth.POST("/login", userHandler.Login)
+			auth.POST("/login/2fa", userHandler.LoginTwoFactor)
+			auth.POST("/magic-link", userHandler.SendMagicLink)
+			auth.POST("/magic-link/consume", userHandler.LoginMagicLink)
+			auth.GET("/oauth/:provider/start", userHandler.OAuthStart)
+			auth.GET("/oauth/:provider/callback", userHandler.OAuthCallback)
 			auth.POST("/register", userHandler.Register)
 			auth.POST("/refresh-token", userHandler.RefreshToken)
 			auth.POST("/verify-email", userHandler.VerifyEmail)
+			auth.POST("/confirm-email-change", userHandler.ConfirmEmailChange)
 			auth.POST("/send-verify-email", userHandler.SendVerifyEmail)
 			auth.POST("/reset-password", userHandler.ResetPassword)
 			auth.POST("/send-reset-password", userHandler.SendResetPassword)
@@ -48,24 +67,44 @@ func SetupRoutes(
 			{
 				profile.GET("", userHandler.Profile)
 				profile.PUT("", userHandler.UpdateProfile)
-				profile.POST("/change-password", userHandler.ChangePassword)
-				profile.POST("/logout", userHandler.Logout)
+				profile.POST("/change-password", authMiddleware.RequireSession(), userHandler.ChangePassword)
+				profile.POST("/email", authMiddleware.RequireSession(), userHandler.ChangeEmail)
+				profile.POST("/logout", authMiddleware.RequireSession(), userHandler.Logout)
+				profile.GET("/sessions", userHandler.ListSessions)
+				profile.DELETE("/sessions/:id", authMiddleware.RequireSession(), userHandler.RevokeSession)
+				profile.POST("/sessions/revoke-others", authMiddleware.RequireSession(), userHandler.RevokeOtherSessions)
+				profile.POST("/2fa/setup", authMiddleware.RequireSession(), userHandler.SetupTwoFactor)
+				profile.POST("/2fa/enable", authMiddleware.RequireSession(), userHandler.EnableTwoFactor)
+				profile.POST("/2fa/disable", authMiddleware.RequireSession(), userHandler.DisableTwoFactor)
+				profile.GET("/api-keys", authMiddleware.RequireSession(), apiKeyHandler.ListAPIKeys)
+				profile.POST("/api-keys", authMiddleware.RequireSession(), apiKeyHandler.CreateAPIKey)
+				profile.DELETE("/api-keys/:id", authMiddleware.RequireSession(), apiKeyHandler.RevokeAPIKey)
 			}
 
-			// User management routes (protected)
+			// User management routes (protected, permission based)
 			users := protected.Group("/users")
 			{
-				users.GET("", userHandler.GetAllUsers)
-				users.GET("/:id", userHandler.GetUserByID)
-				users.POST("", userHandler.CreateUser)
-				users.PUT("/:id", userHandler.UpdateUser)
-				users.DELETE("/:id", userHandler.DeleteUser)
+				users.GET("", authMiddleware.RequirePermission(enums.PermissionUsersRead), userHandler.GetAllUsers)
+				users.GET("/deleted", authMiddleware.RequirePermission(enums.PermissionUsersRead), userHandler.GetDeletedUsers)
+				users.GET("/:id", authMiddleware.RequirePermission(enums.PermissionUsersRead), userHandler.GetUserByID)
+				users.POST("", authMiddleware.RequirePermission(enums.PermissionUsersCreate), userHandler.CreateUser)
+				users.PUT("/:id", authMiddleware.RequirePermission(enums.PermissionUsersUpdate), userHandler.UpdateUser)
+				users.POST("/:id/unlock", authMiddleware.RequirePermission(enums.PermissionUsersUpdate), userHandler.UnlockUser)
+				users.PUT("/:id/roles", authMiddleware.RequirePermission(enums.PermissionRolesAssign), userHandler.AssignRoles)
+				users.DELETE("/:id", authMiddleware.RequirePermission(enums.PermissionUsersDelete), userHandler.DeleteUser)
+				users.POST("/:id/restore", authMiddleware.RequirePermission(enums.PermissionUsersDelete), userHandler.RestoreUser)
 			}
+
+			// Audit log routes (protected, permission based)
+			protected.GET("/audit-logs", authMiddleware.RequirePermission(enums.PermissionAuditRead), auditLogHandler.ListAuditLogs)
 		}
 	}
 
 	router.Static("/assets", "./assets")
 
+	// Access token verification keys for other services
+	router.GET("/.well-known/jwks.json", wellKnownHandler.JWKS)
+
 	// Health check
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{
@@ -0,0 +1,67 @@
+package database
+
+import (
+	"context"
+	"go-gin-clean/internal/core/domain/entities"
+	"go-gin-clean/internal/core/ports"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type APIKeyRepository struct {
+	db       *gorm.DB
+	baseRepo ports.BaseRepository[entities.APIKey]
+}
+
+func NewAPIKeyRepository(db *gorm.DB) ports.APIKeyRepository {
+	baseRepo := NewBaseRepository[entities.APIKey](db)
+	return &APIKeyRepository{
+		db:       db,
+		baseRepo: baseRepo,
+	}
+}
+
+func (r *APIKeyRepository) Create(ctx context.Context, key *entities.APIKey) error {
+	return r.db.WithContext(ctx).Omit("User").Create(key).Error
+}
+
+func (r *APIKeyRepository) FindByHash(ctx context.Context, keyHash string) (*entities.APIKey, error) {
+	return r.baseRepo.FindFirst(ctx, "key_hash = ?", keyHash)
+}
+
+func (r *APIKeyRepository) FindActiveByUserID(ctx context.Context, userID int64) ([]*entities.APIKey, error) {
+	var keys []*entities.APIKey
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", userID, time.Now()).
+		Order("created_at desc").
+		Find(&keys).Error
+	return keys, err
+}
+
+func (r *APIKeyRepository) Revoke(ctx context.Context, userID, keyID int64) error {
+	result := r.db.WithContext(ctx).Model(&entities.APIKey{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", keyID, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r *APIKeyRepository) RevokeAllByUserID(ctx context.Context, userID int64) error {
+	return r.db.WithContext(ctx).Model(&entities.APIKey{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, keyID int64) error {
+	return r.db.WithContext(ctx).Model(&entities.APIKey{}).
+		Where("id = ?", keyID).
+		Update("last_used_at", time.Now()).Error
+}
@@ -0,0 +1,62 @@
+package database
+
+import (
+	"context"
+	"go-gin-clean/internal/core/contracts"
+	"go-gin-clean/internal/core/domain/entities"
+	"go-gin-clean/internal/core/ports"
+
+	"gorm.io/gorm"
+)
+
+type AuditLogRepository struct {
+	db       *gorm.DB
+	baseRepo ports.BaseRepository[entities.AuditLog]
+}
+
+func NewAuditLogRepository(db *gorm.DB) ports.AuditLogRepository {
+	baseRepo := NewBaseRepository[entities.AuditLog](db)
+	return &AuditLogRepository{
+		db:       db,
+		baseRepo: baseRepo,
+	}
+}
+
+func (r *AuditLogRepository) Create(ctx context.Context, log *entities.AuditLog) error {
+	_, err := r.baseRepo.Create(ctx, log)
+	return err
+}
+
+// FindAll returns matching entries, newest first.
+func (r *AuditLogRepository) FindAll(ctx context.Context, limit, offset int, filter *contracts.AuditLogFilter) ([]*entities.AuditLog, int64, error) {
+	var logs []*entities.AuditLog
+	var count int64
+
+	db := r.db.WithContext(ctx).Model(&entities.AuditLog{})
+
+	if filter.ActorID != nil {
+		db = db.Where("actor_id = ?", *filter.ActorID)
+	}
+	if filter.TargetID != nil {
+		db = db.Where("target_id = ?", *filter.TargetID)
+	}
+	if filter.Action != "" {
+		db = db.Where("action = ?", filter.Action)
+	}
+	if filter.From != nil {
+		db = db.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		db = db.Where("created_at < ?", *filter.To)
+	}
+
+	if err := db.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := db.Order("created_at desc, id desc").Limit(limit).Offset(offset).Find(&logs).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return logs, count, nil
+}
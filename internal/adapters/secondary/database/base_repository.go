@@ -2,19 +2,53 @@ package database
 
 import (
 	"context"
+	"fmt"
+	"go-gin-clean/internal/core/contracts"
 	"go-gin-clean/internal/core/ports"
+	"reflect"
+	"slices"
+	"time"
 
 	"gorm.io/gorm"
 )
 
+// softDeletable is implemented by entities embedding entities.Audit. Their
+// rows are soft-deleted by Delete and hidden from reads.
+type softDeletable interface {
+	MarkAsDeleted()
+	RestoreFromDeletion()
+}
+
 type BaseRepository[T any] struct {
-	db *gorm.DB
+	db          *gorm.DB
+	withDeleted bool
 }
 
 func NewBaseRepository[T any](db *gorm.DB) ports.BaseRepository[T] {
 	return &BaseRepository[T]{db: db}
 }
 
+// WithDeleted returns a view of the repository whose reads include
+// soft-deleted rows.
+func (r *BaseRepository[T]) WithDeleted() ports.BaseRepository[T] {
+	return &BaseRepository[T]{db: r.db, withDeleted: true}
+}
+
+func (r *BaseRepository[T]) softDeletes() bool {
+	_, ok := any(new(T)).(softDeletable)
+	return ok
+}
+
+// scoped starts a query that skips soft-deleted rows, unless the
+// repository was obtained through WithDeleted.
+func (r *BaseRepository[T]) scoped(ctx context.Context) *gorm.DB {
+	db := r.db.WithContext(ctx)
+	if r.softDeletes() && !r.withDeleted {
+		db = db.Where("deleted_at IS NULL")
+	}
+	return db
+}
+
 func (r *BaseRepository[T]) Raw(ctx context.Context, query string) ([]*T, error) {
 	var entities []*T
 	if err := r.db.WithContext(ctx).Raw(query).Scan(&entities).Error; err != nil {
@@ -25,29 +59,132 @@ func (r *BaseRepository[T]) Raw(ctx context.Context, query string) ([]*T, error)
 }
 
 func (r *BaseRepository[T]) FindAll(ctx context.Context, limit, offset int, query any, args ...any) ([]*T, int64, error) {
+	return r.FindAllByQuery(ctx, limit, offset, nil, nil, query, args...)
+}
+
+func (r *BaseRepository[T]) FindAllByQuery(ctx context.Context, limit, offset int, fields contracts.QueryFields, spec *contracts.QuerySpec, query any, args ...any) ([]*T, int64, error) {
+	if err := fields.Validate(spec); err != nil {
+		return nil, 0, err
+	}
+
+	db := r.scoped(ctx)
+
+	if query != nil {
+		db = db.Where(query, args...)
+	}
+
+	return findPage[T](db, limit, offset, fields, spec)
+}
+
+// FindAllByCursor reads one keyset page: up to page.Limit rows after or
+// before page.Cursor in the spec ordering. One extra row is fetched to tell
+// whether another page follows.
+func (r *BaseRepository[T]) FindAllByCursor(ctx context.Context, fields contracts.QueryFields, spec *contracts.QuerySpec, page *contracts.CursorRequest, query any, args ...any) ([]*T, *contracts.CursorPage, error) {
 	var entities []*T
-	var count int64
+	result := &contracts.CursorPage{}
 
-	db := r.db.WithContext(ctx)
+	if err := fields.ValidateKeyset(spec); err != nil {
+		return nil, nil, err
+	}
+
+	keys := keysetKeys(fields, spec)
+
+	var values []any
+	var backward bool
+	if page.Cursor != "" {
+		var err error
+		if values, backward, err = decodeCursor(page.Cursor, keys); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	db := r.scoped(ctx)
 
 	if query != nil {
 		db = db.Where(query, args...)
 	}
 
-	if err := db.Model(new(T)).Count(&count).Error; err != nil {
-		return nil, 0, err
+	db, err := applyFilters(db, fields, spec)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	if err := db.Order("id asc").Limit(limit).Offset(offset).Find(&entities).Error; err != nil {
-		return nil, 0, err
+	db = db.Session(&gorm.Session{})
+
+	if page.IncludeTotal {
+		var count int64
+		if err := db.Model(new(T)).Count(&count).Error; err != nil {
+			return nil, nil, err
+		}
+		result.Total = &count
+	}
+
+	if values != nil {
+		db = db.Where(keysetCondition(db, keys, values, backward))
+	}
+
+	if err := keysetOrder(db, keys, backward).Limit(page.Limit + 1).Find(&entities).Error; err != nil {
+		return nil, nil, err
+	}
+
+	hasMore := len(entities) > page.Limit
+	if hasMore {
+		entities = entities[:page.Limit]
 	}
 
-	return entities, count, nil
+	if backward {
+		slices.Reverse(entities)
+	}
+
+	if len(entities) == 0 {
+		return entities, result, nil
+	}
+
+	// A backward page was reached from the page after it, a forward page
+	// from the one before it unless it is the first.
+	hasNext, hasPrev := hasMore, page.Cursor != ""
+	if backward {
+		hasNext, hasPrev = true, hasMore
+	}
+
+	if hasNext {
+		if result.NextCursor, err = r.cursorAt(ctx, keys, entities[len(entities)-1], false); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if hasPrev {
+		if result.PrevCursor, err = r.cursorAt(ctx, keys, entities[0], true); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return entities, result, nil
+}
+
+// cursorAt encodes a cursor pointing at entity, reading its key values
+// through the gorm schema.
+func (r *BaseRepository[T]) cursorAt(ctx context.Context, keys []keysetKey, entity *T, backward bool) (string, error) {
+	stmt := &gorm.Statement{DB: r.db}
+	if err := stmt.Parse(entity); err != nil {
+		return "", err
+	}
+
+	values := make([]any, len(keys))
+	for i, key := range keys {
+		field := stmt.Schema.LookUpField(key.column)
+		if field == nil {
+			return "", fmt.Errorf("cursor column %s not found on %s", key.column, stmt.Schema.Name)
+		}
+		values[i], _ = field.ValueOf(ctx, reflect.ValueOf(entity))
+	}
+
+	return encodeCursor(keys, values, backward)
 }
 
 func (r *BaseRepository[T]) FindByID(ctx context.Context, id int64) (*T, error) {
 	var entity T
-	if err := r.db.WithContext(ctx).Where("id = ?", id).Take(&entity).Error; err != nil {
+	if err := r.scoped(ctx).Where("id = ?", id).Take(&entity).Error; err != nil {
 		return nil, err
 	}
 	return &entity, nil
@@ -55,7 +192,7 @@ func (r *BaseRepository[T]) FindByID(ctx context.Context, id int64) (*T, error)
 
 func (r *BaseRepository[T]) FindFirst(ctx context.Context, query any, args ...any) (*T, error) {
 	var entity T
-	if err := r.db.WithContext(ctx).Where(query, args...).First(&entity).Error; err != nil {
+	if err := r.scoped(ctx).Where(query, args...).First(&entity).Error; err != nil {
 		return nil, err
 	}
 	return &entity, nil
@@ -63,7 +200,7 @@ func (r *BaseRepository[T]) FindFirst(ctx context.Context, query any, args ...an
 
 func (r *BaseRepository[T]) Where(ctx context.Context, query any, args ...any) ([]*T, error) {
 	var entities []*T
-	if err := r.db.WithContext(ctx).Where(query, args...).Order("id asc").Find(&entities).Error; err != nil {
+	if err := r.scoped(ctx).Where(query, args...).Order("id asc").Find(&entities).Error; err != nil {
 		return nil, err
 	}
 	return entities, nil
@@ -71,7 +208,7 @@ func (r *BaseRepository[T]) Where(ctx context.Context, query any, args ...any) (
 
 func (r *BaseRepository[T]) WhereExisting(ctx context.Context, query any, args ...any) (bool, error) {
 	var entity T
-	err := r.db.WithContext(ctx).Where(query, args...).First(&entity).Error
+	err := r.scoped(ctx).Where(query, args...).First(&entity).Error
 	if err == gorm.ErrRecordNotFound {
 		return false, nil
 	}
@@ -101,10 +238,69 @@ func (r *BaseRepository[T]) Update(ctx context.Context, entity *T) (*T, error) {
 	return entity, nil
 }
 
+// Delete soft-deletes entities embedding entities.Audit and removes any
+// other entity.
 func (r *BaseRepository[T]) Delete(ctx context.Context, id int64) error {
+	if !r.softDeletes() {
+		return r.hardDelete(ctx, id)
+	}
+
+	result := r.db.WithContext(ctx).Model(new(T)).
+		Where("id = ? AND deleted_at IS NULL", id).
+		Updates(map[string]any{
+			"deleted_at": time.Now(),
+			"is_deleted": true,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r *BaseRepository[T]) hardDelete(ctx context.Context, id int64) error {
 	if err := r.db.WithContext(ctx).Delete(new(T), "id = ?", id).Error; err != nil {
 		return err
 	}
 
 	return nil
 }
+
+func (r *BaseRepository[T]) Restore(ctx context.Context, id int64) error {
+	if !r.softDeletes() {
+		return gorm.ErrRecordNotFound
+	}
+
+	result := r.db.WithContext(ctx).Model(new(T)).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]any{
+			"deleted_at": nil,
+			"is_deleted": false,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Purge permanently removes rows soft-deleted before the given time and
+// returns how many were removed.
+func (r *BaseRepository[T]) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	if !r.softDeletes() {
+		return 0, nil
+	}
+
+	result := r.db.WithContext(ctx).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).
+		Delete(new(T))
+	return result.RowsAffected, result.Error
+}
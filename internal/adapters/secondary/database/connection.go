@@ -0,0 +1,42 @@
+package database
+
+import (
+	"fmt"
+	"go-gin-clean/pkg/config"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// Open connects to the database selected by cfg.Driver and applies the
+// connection pool settings.
+func Open(cfg *config.DatabaseConfig, gormConfig *gorm.Config) (*gorm.DB, error) {
+	var dialector gorm.Dialector
+	switch cfg.Driver {
+	case config.DriverPostgres:
+		dialector = postgres.Open(cfg.DSN())
+	case config.DriverMySQL:
+		dialector = mysql.Open(cfg.DSN())
+	case config.DriverSQLite:
+		dialector = sqlite.Open(cfg.DSN())
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", cfg.Driver)
+	}
+
+	db, err := gorm.Open(dialector, gormConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+
+	return db, nil
+}
@@ -0,0 +1,167 @@
+package database
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"go-gin-clean/internal/core/contracts"
+	"go-gin-clean/internal/core/domain/errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// keysetKey is one column of a keyset ordering.
+type keysetKey struct {
+	column    string
+	fieldType contracts.FieldType
+	desc      bool
+}
+
+// cursorToken is the decoded form of an opaque cursor: the sort key values
+// of the row it points at and the direction to read in. Sort records the
+// ordering the cursor was issued for, so it cannot be replayed against a
+// different one.
+type cursorToken struct {
+	Sort     string            `json:"s"`
+	Backward bool              `json:"b,omitempty"`
+	Values   []json.RawMessage `json:"v"`
+}
+
+// keysetKeys mirrors applySort: the requested fields followed by id unless
+// id was already one of them. The spec must have been validated against
+// fields.
+func keysetKeys(fields contracts.QueryFields, spec *contracts.QuerySpec) []keysetKey {
+	var keys []keysetKey
+	sortedByID := false
+	if spec != nil {
+		for _, sort := range spec.Sort {
+			field := fields[sort.Field]
+			keys = append(keys, keysetKey{column: field.Column, fieldType: field.Type, desc: sort.Desc})
+			sortedByID = sortedByID || field.Column == "id"
+		}
+	}
+
+	if !sortedByID {
+		keys = append(keys, keysetKey{column: "id", fieldType: contracts.FieldInt})
+	}
+
+	return keys
+}
+
+func sortSignature(keys []keysetKey) string {
+	columns := make([]string, len(keys))
+	for i, key := range keys {
+		columns[i] = key.column
+		if key.desc {
+			columns[i] = "-" + key.column
+		}
+	}
+	return strings.Join(columns, ",")
+}
+
+func encodeCursor(keys []keysetKey, values []any, backward bool) (string, error) {
+	token := cursorToken{Sort: sortSignature(keys), Backward: backward}
+	for _, value := range values {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		token.Values = append(token.Values, raw)
+	}
+
+	payload, err := json.Marshal(token)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(payload), nil
+}
+
+// decodeCursor returns the key values and direction of a cursor, converted
+// to the key types so they can be bound as parameters.
+func decodeCursor(cursor string, keys []keysetKey) ([]any, bool, error) {
+	invalid := errors.NewQueryError("cursor", "is invalid or expired")
+
+	payload, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, false, invalid
+	}
+
+	var token cursorToken
+	if err := json.Unmarshal(payload, &token); err != nil {
+		return nil, false, invalid
+	}
+
+	if token.Sort != sortSignature(keys) {
+		return nil, false, errors.NewQueryError("cursor", "was issued for a different sort order")
+	}
+
+	if len(token.Values) != len(keys) {
+		return nil, false, invalid
+	}
+
+	values := make([]any, len(keys))
+	for i, key := range keys {
+		var err error
+		switch key.fieldType {
+		case contracts.FieldTime:
+			var value time.Time
+			err = json.Unmarshal(token.Values[i], &value)
+			values[i] = value
+		case contracts.FieldInt:
+			var value int64
+			err = json.Unmarshal(token.Values[i], &value)
+			values[i] = value
+		case contracts.FieldBool:
+			var value bool
+			err = json.Unmarshal(token.Values[i], &value)
+			values[i] = value
+		default:
+			var value string
+			err = json.Unmarshal(token.Values[i], &value)
+			values[i] = value
+		}
+		if err != nil {
+			return nil, false, invalid
+		}
+	}
+
+	return values, token.Backward, nil
+}
+
+// keysetCondition matches the rows strictly after values in the keys
+// ordering, or strictly before them when reading backward:
+// (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ...
+func keysetCondition(db *gorm.DB, keys []keysetKey, values []any, backward bool) clause.Expression {
+	var branches []clause.Expression
+	for i, key := range keys {
+		var conditions []clause.Expression
+		for j := 0; j < i; j++ {
+			column, value := operands(db, keys[j].column, keys[j].fieldType, values[j])
+			conditions = append(conditions, clause.Eq{Column: column, Value: value})
+		}
+
+		column, value := operands(db, key.column, key.fieldType, values[i])
+		if key.desc != backward {
+			conditions = append(conditions, clause.Lt{Column: column, Value: value})
+		} else {
+			conditions = append(conditions, clause.Gt{Column: column, Value: value})
+		}
+
+		branches = append(branches, clause.And(conditions...))
+	}
+
+	return clause.Or(branches...)
+}
+
+// keysetOrder orders by the keys, reversed when reading backward so the
+// rows closest to the cursor come first.
+func keysetOrder(db *gorm.DB, keys []keysetKey, backward bool) *gorm.DB {
+	for _, key := range keys {
+		column, _ := operands(db, key.column, key.fieldType, nil)
+		db = db.Order(clause.OrderByColumn{Column: column, Desc: key.desc != backward})
+	}
+	return db
+}
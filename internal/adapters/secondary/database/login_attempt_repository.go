@@ -0,0 +1,80 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"go-gin-clean/internal/core/domain/entities"
+	"go-gin-clean/internal/core/ports"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LoginAttemptRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginAttemptRepository(db *gorm.DB) ports.LoginAttemptRepository {
+	return &LoginAttemptRepository{db: db}
+}
+
+func (r *LoginAttemptRepository) FindByKey(ctx context.Context, key string) (*entities.LoginAttempt, error) {
+	var attempt entities.LoginAttempt
+	err := r.db.WithContext(ctx).Where("attempt_key = ?", key).Take(&attempt).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entities.NewLoginAttempt(key), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+// errAttemptInserted reports that another transaction created the row first.
+var errAttemptInserted = errors.New("login attempt inserted concurrently")
+
+// Update reads the row with FOR UPDATE so concurrent failures for the same
+// key queue up behind each other. When two requests both find no row, the
+// one whose insert loses starts over and locks the row the other created.
+func (r *LoginAttemptRepository) Update(ctx context.Context, key string, fn func(attempt *entities.LoginAttempt)) (*entities.LoginAttempt, error) {
+	attempt := entities.NewLoginAttempt(key)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("attempt_key = ?", key).
+			Take(attempt).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			fn(attempt)
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(attempt)
+			if result.Error == nil && result.RowsAffected == 0 {
+				return errAttemptInserted
+			}
+			return result.Error
+		}
+		if err != nil {
+			return err
+		}
+
+		fn(attempt)
+		return tx.Save(attempt).Error
+	})
+	if errors.Is(err, errAttemptInserted) {
+		return r.Update(ctx, key, fn)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return attempt, nil
+}
+
+func (r *LoginAttemptRepository) Delete(ctx context.Context, key string) error {
+	return r.db.WithContext(ctx).
+		Where("attempt_key = ?", key).
+		Delete(&entities.LoginAttempt{}).Error
+}
+
+func (r *LoginAttemptRepository) DeleteStale(ctx context.Context, lastFailureBefore time.Time) error {
+	return r.db.WithContext(ctx).
+		Where("last_failure_at < ? AND (locked_until IS NULL OR locked_until <= ?)", lastFailureBefore, time.Now()).
+		Delete(&entities.LoginAttempt{}).Error
+}
@@ -0,0 +1,17 @@
+package migrations
+
+import "embed"
+
+// Files holds the SQL migrations shipped with the binary, one directory per
+// database dialect.
+//
+//go:embed postgres/*.sql mysql/*.sql sqlite/*.sql
+var Files embed.FS
+
+// Dir is where "migrate create" writes new migration files, relative to the
+// project root.
+const Dir = "internal/adapters/secondary/database/migrations"
+
+// Dialects are the subdirectories of Dir. Every migration version exists in
+// each of them so the numbering stays the same whatever the database.
+var Dialects = []string{"postgres", "mysql", "sqlite"}
@@ -0,0 +1,479 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// lockKey identifies the session-level advisory lock held while migrating so
+// that two deploys never run migrations at the same time. MySQL named locks
+// take a string, lockName.
+const (
+	lockKey  int64 = 4_181_736_502
+	lockName       = "go-gin-clean.schema_migrations"
+)
+
+var (
+	fileNamePattern   = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+	nonWordCharacters = regexp.MustCompile(`\W+`)
+	sqlLineComments   = regexp.MustCompile(`(?m)^\s*--.*$`)
+)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	Dirty     bool
+	AppliedAt *time.Time
+}
+
+type schemaMigration struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"not null"`
+	Dirty     bool      `gorm:"not null;default:false"`
+	AppliedAt time.Time `gorm:"type:timestamp;not null"`
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// NewMigrator loads the embedded migrations for the dialect db is connected
+// with.
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	migrations, err := Load(Files, db.Dialector.Name())
+	if err != nil {
+		return nil, fmt.Errorf("loading %s migrations: %w", db.Dialector.Name(), err)
+	}
+
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+	}, nil
+}
+
+// Load reads NNNNNN_name.up.sql / NNNNNN_name.down.sql pairs from dir and
+// returns them ordered by version.
+func Load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	hasUp := make(map[int64]bool)
+	for _, entry := range entries {
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", entry.Name(), err)
+		}
+
+		content, err := fs.ReadFile(fsys, dir+"/"+entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names %q and %q", version, migration.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			hasUp[version] = true
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if !hasUp[migration.Version] {
+			return nil, fmt.Errorf("migration %d_%s has no up file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Up applies up to n pending migrations, or all of them when n <= 0, and
+// returns the ones that were applied.
+func (m *Migrator) Up(ctx context.Context, n int) ([]Migration, error) {
+	var done []Migration
+
+	err := m.withLock(ctx, func(conn *gorm.DB) error {
+		applied, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if n > 0 && len(done) == n {
+				break
+			}
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+
+			if err := m.apply(conn, migration); err != nil {
+				return err
+			}
+			done = append(done, migration)
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+// Down reverts up to n applied migrations, newest first, or all of them when
+// n <= 0, and returns the ones that were reverted.
+func (m *Migrator) Down(ctx context.Context, n int) ([]Migration, error) {
+	var done []Migration
+
+	err := m.withLock(ctx, func(conn *gorm.DB) error {
+		applied, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+
+		versions := make([]int64, 0, len(applied))
+		for version := range applied {
+			versions = append(versions, version)
+		}
+		sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+
+		for _, version := range versions {
+			if n > 0 && len(done) == n {
+				break
+			}
+
+			migration, ok := m.find(version)
+			if !ok {
+				return fmt.Errorf("migration %d is applied but its files are missing", version)
+			}
+
+			if err := m.revert(conn, migration); err != nil {
+				return err
+			}
+			done = append(done, migration)
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+// Status lists every known migration together with the versions recorded in
+// schema_migrations, including applied versions whose files are gone.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+
+	err := m.withLock(ctx, func(conn *gorm.DB) error {
+		applied, err := m.loadRecords(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+			if record, ok := applied[migration.Version]; ok {
+				status.Applied = true
+				status.Dirty = record.Dirty
+				status.AppliedAt = &record.AppliedAt
+				delete(applied, migration.Version)
+			}
+			statuses = append(statuses, status)
+		}
+
+		for _, record := range applied {
+			statuses = append(statuses, MigrationStatus{
+				Version:   record.Version,
+				Name:      record.Name,
+				Applied:   true,
+				Dirty:     record.Dirty,
+				AppliedAt: &record.AppliedAt,
+			})
+		}
+
+		return nil
+	})
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+
+	return statuses, err
+}
+
+// Force records the schema as being exactly at version, without running any
+// SQL, and clears the dirty flag. Use it after repairing a failed migration
+// by hand, or with 0 to forget every recorded version.
+func (m *Migrator) Force(ctx context.Context, version int64) error {
+	if _, ok := m.find(version); !ok && version != 0 {
+		return fmt.Errorf("unknown migration version %d", version)
+	}
+
+	return m.withLock(ctx, func(conn *gorm.DB) error {
+		return conn.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Where("version > ?", version).Delete(&schemaMigration{}).Error; err != nil {
+				return err
+			}
+
+			for _, migration := range m.migrations {
+				if migration.Version > version {
+					break
+				}
+
+				record := schemaMigration{
+					Version:   migration.Version,
+					Name:      migration.Name,
+					AppliedAt: time.Now(),
+				}
+				if err := tx.Where("version = ?", migration.Version).
+					Assign(map[string]any{"dirty": false}).
+					FirstOrCreate(&record).Error; err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	})
+}
+
+// Create writes an empty up/down pair into the directory of every dialect
+// under dir, numbered after the newest migration of any of them, and returns
+// the paths of the new files.
+func Create(dir, name string) ([]string, error) {
+	name = strings.Trim(nonWordCharacters.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return nil, fmt.Errorf("migration name must contain letters or digits")
+	}
+
+	var latest int64
+	for _, dialect := range Dialects {
+		entries, err := os.ReadDir(filepath.Join(dir, dialect))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			match := fileNamePattern.FindStringSubmatch(entry.Name())
+			if match == nil {
+				continue
+			}
+			if version, _ := strconv.ParseInt(match[1], 10, 64); version > latest {
+				latest = version
+			}
+		}
+	}
+
+	base := fmt.Sprintf("%06d_%s", latest+1, name)
+	var paths []string
+	for _, dialect := range Dialects {
+		paths = append(paths,
+			filepath.Join(dir, dialect, base+".up.sql"),
+			filepath.Join(dir, dialect, base+".down.sql"),
+		)
+	}
+
+	for _, path := range paths {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return nil, err
+		}
+		file.Close()
+	}
+
+	return paths, nil
+}
+
+func (m *Migrator) find(version int64) (Migration, bool) {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration, true
+		}
+	}
+	return Migration{}, false
+}
+
+// withLock runs fn on a single pinned connection while holding the migration
+// lock. A second migrator blocks until the first one finishes.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *gorm.DB) error) error {
+	return m.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		// Connection returns a single-use instance; a new session lets every
+		// statement below start from clean clauses.
+		conn = conn.Session(&gorm.Session{})
+
+		unlock, err := lock(conn)
+		if err != nil {
+			return fmt.Errorf("acquiring migration lock: %w", err)
+		}
+		defer unlock()
+
+		if err := conn.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    bigint PRIMARY KEY,
+			name       text NOT NULL,
+			dirty      boolean NOT NULL DEFAULT false,
+			applied_at timestamp NOT NULL
+		)`).Error; err != nil {
+			return err
+		}
+
+		return fn(conn)
+	})
+}
+
+// lock takes the session-level migration lock of the dialect and returns
+// the function releasing it. SQLite has no such lock; it is meant for a
+// single local process and serialises writers on its own.
+func lock(conn *gorm.DB) (func(), error) {
+	switch conn.Dialector.Name() {
+	case "postgres":
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", lockKey).Error; err != nil {
+			return nil, err
+		}
+		return func() { conn.Exec("SELECT pg_advisory_unlock(?)", lockKey) }, nil
+	case "mysql":
+		var acquired int
+		if err := conn.Raw("SELECT GET_LOCK(?, -1)", lockName).Scan(&acquired).Error; err != nil {
+			return nil, err
+		}
+		if acquired != 1 {
+			return nil, fmt.Errorf("GET_LOCK(%q) returned %d", lockName, acquired)
+		}
+		return func() { conn.Exec("DO RELEASE_LOCK(?)", lockName) }, nil
+	default:
+		return func() {}, nil
+	}
+}
+
+// isBlank reports whether a migration file holds no statements, only
+// whitespace and comments. Such files are recorded without running them.
+func isBlank(sql string) bool {
+	return strings.TrimSpace(sqlLineComments.ReplaceAllString(sql, "")) == ""
+}
+
+func (m *Migrator) loadRecords(conn *gorm.DB) (map[int64]schemaMigration, error) {
+	var records []schemaMigration
+	if err := conn.Order("version asc").Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int64]schemaMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+
+	return applied, nil
+}
+
+// applied returns the recorded versions and refuses to continue while any of
+// them is dirty.
+func (m *Migrator) applied(conn *gorm.DB) (map[int64]schemaMigration, error) {
+	applied, err := m.loadRecords(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range applied {
+		if record.Dirty {
+			return nil, fmt.Errorf("migration %d_%s is dirty; fix the schema by hand and run \"force <version>\"", record.Version, record.Name)
+		}
+	}
+
+	return applied, nil
+}
+
+// apply marks the version dirty before running it so that a failure which
+// leaves the schema half-changed is noticed by the next run.
+func (m *Migrator) apply(conn *gorm.DB, migration Migration) error {
+	record := schemaMigration{
+		Version:   migration.Version,
+		Name:      migration.Name,
+		Dirty:     true,
+		AppliedAt: time.Now(),
+	}
+	if err := conn.Create(&record).Error; err != nil {
+		return err
+	}
+
+	err := conn.Transaction(func(tx *gorm.DB) error {
+		if !isBlank(migration.Up) {
+			if err := tx.Exec(migration.Up).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&schemaMigration{}).
+			Where("version = ?", migration.Version).
+			Update("dirty", false).Error
+	})
+	if err != nil {
+		return fmt.Errorf("applying migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+
+	return nil
+}
+
+func (m *Migrator) revert(conn *gorm.DB, migration Migration) error {
+	if err := conn.Model(&schemaMigration{}).
+		Where("version = ?", migration.Version).
+		Update("dirty", true).Error; err != nil {
+		return err
+	}
+
+	err := conn.Transaction(func(tx *gorm.DB) error {
+		if !isBlank(migration.Down) {
+			if err := tx.Exec(migration.Down).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Where("version = ?", migration.Version).Delete(&schemaMigration{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("reverting migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+
+	return nil
+}
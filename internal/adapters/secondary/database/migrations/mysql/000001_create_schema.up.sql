@@ -0,0 +1,186 @@
+-- MySQL declares gender as an inline ENUM column. Indexed strings are
+-- varchar because TEXT columns cannot be indexed without a prefix length.
+-- The utf8mb4_0900_ai_ci collation makes comparisons, and so user search,
+-- case- and accent-insensitive.
+
+CREATE TABLE IF NOT EXISTS permissions (
+    id          bigint AUTO_INCREMENT PRIMARY KEY,
+    name        varchar(255) NOT NULL,
+    description varchar(255) DEFAULT '',
+    created_at  datetime(6) DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at  datetime(6) DEFAULT CURRENT_TIMESTAMP(6),
+    deleted_at  datetime(6) DEFAULT NULL,
+    is_deleted  boolean DEFAULT false,
+    UNIQUE KEY idx_permissions_name (name)
+) DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci;
+
+CREATE TABLE IF NOT EXISTS roles (
+    id          bigint AUTO_INCREMENT PRIMARY KEY,
+    name        varchar(255) NOT NULL,
+    description varchar(255) DEFAULT '',
+    created_at  datetime(6) DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at  datetime(6) DEFAULT CURRENT_TIMESTAMP(6),
+    deleted_at  datetime(6) DEFAULT NULL,
+    is_deleted  boolean DEFAULT false,
+    UNIQUE KEY idx_roles_name (name)
+) DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci;
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id       bigint NOT NULL,
+    permission_id bigint NOT NULL,
+    PRIMARY KEY (role_id, permission_id),
+    CONSTRAINT fk_role_permissions_role FOREIGN KEY (role_id) REFERENCES roles (id),
+    CONSTRAINT fk_role_permissions_permission FOREIGN KEY (permission_id) REFERENCES permissions (id)
+) DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci;
+
+CREATE TABLE IF NOT EXISTS users (
+    id                   bigint AUTO_INCREMENT PRIMARY KEY,
+    name                 varchar(255) NOT NULL,
+    email                varchar(255) NOT NULL,
+    password             varchar(255) NOT NULL,
+    avatar               varchar(1024) DEFAULT '',
+    gender               ENUM('Male', 'Female', 'Unknown') DEFAULT NULL,
+    is_active            boolean NOT NULL DEFAULT false,
+    pending_email        varchar(255) DEFAULT '',
+    two_factor_enabled   boolean NOT NULL DEFAULT false,
+    two_factor_secret    varchar(512) DEFAULT '',
+    two_factor_last_step bigint NOT NULL DEFAULT 0,
+    created_at           datetime(6) DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at           datetime(6) DEFAULT CURRENT_TIMESTAMP(6),
+    deleted_at           datetime(6) DEFAULT NULL,
+    is_deleted           boolean DEFAULT false,
+    UNIQUE KEY idx_users_email (email)
+) DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci;
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id bigint NOT NULL,
+    role_id bigint NOT NULL,
+    PRIMARY KEY (user_id, role_id),
+    CONSTRAINT fk_user_roles_user FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_user_roles_role FOREIGN KEY (role_id) REFERENCES roles (id)
+) DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci;
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id                 bigint AUTO_INCREMENT PRIMARY KEY,
+    user_id            bigint NOT NULL,
+    token              varchar(512) NOT NULL,
+    family_id          varchar(128) NOT NULL,
+    parent_id          bigint DEFAULT NULL,
+    expiry_at          datetime(6) NOT NULL,
+    is_revoked         boolean NOT NULL DEFAULT false,
+    rotated_at         datetime(6) DEFAULT NULL,
+    user_agent         varchar(512) DEFAULT '',
+    ip_address         varchar(45) DEFAULT '',
+    session_started_at datetime(6) DEFAULT CURRENT_TIMESTAMP(6),
+    last_used_at       datetime(6) DEFAULT CURRENT_TIMESTAMP(6),
+    created_at         datetime(6) DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at         datetime(6) DEFAULT CURRENT_TIMESTAMP(6),
+    deleted_at         datetime(6) DEFAULT NULL,
+    is_deleted         boolean DEFAULT false,
+    CONSTRAINT uni_refresh_tokens_token UNIQUE (token),
+    KEY idx_refresh_tokens_family_id (family_id),
+    CONSTRAINT fk_refresh_tokens_user FOREIGN KEY (user_id) REFERENCES users (id)
+) DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci;
+
+CREATE TABLE IF NOT EXISTS recovery_codes (
+    id         bigint AUTO_INCREMENT PRIMARY KEY,
+    user_id    bigint NOT NULL,
+    code_hash  varchar(255) NOT NULL,
+    used_at    datetime(6) DEFAULT NULL,
+    created_at datetime(6) DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at datetime(6) DEFAULT CURRENT_TIMESTAMP(6),
+    deleted_at datetime(6) DEFAULT NULL,
+    is_deleted boolean DEFAULT false,
+    KEY idx_recovery_codes_user_id (user_id),
+    CONSTRAINT fk_recovery_codes_user FOREIGN KEY (user_id) REFERENCES users (id)
+) DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci;
+
+CREATE TABLE IF NOT EXISTS one_time_tokens (
+    id          bigint AUTO_INCREMENT PRIMARY KEY,
+    user_id     bigint NOT NULL,
+    purpose     varchar(32) NOT NULL,
+    token_hash  varchar(64) NOT NULL,
+    expires_at  datetime(6) NOT NULL,
+    consumed_at datetime(6) DEFAULT NULL,
+    created_at  datetime(6) DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at  datetime(6) DEFAULT CURRENT_TIMESTAMP(6),
+    deleted_at  datetime(6) DEFAULT NULL,
+    is_deleted  boolean DEFAULT false,
+    KEY idx_one_time_tokens_user_purpose (user_id, purpose),
+    UNIQUE KEY idx_one_time_tokens_token_hash (token_hash),
+    CONSTRAINT fk_one_time_tokens_user FOREIGN KEY (user_id) REFERENCES users (id)
+) DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci;
+
+CREATE TABLE IF NOT EXISTS password_histories (
+    id            bigint AUTO_INCREMENT PRIMARY KEY,
+    user_id       bigint NOT NULL,
+    password_hash varchar(255) NOT NULL,
+    created_at    datetime(6) DEFAULT CURRENT_TIMESTAMP(6),
+    KEY idx_password_histories_user_id (user_id),
+    CONSTRAINT fk_password_histories_user FOREIGN KEY (user_id) REFERENCES users (id)
+) DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci;
+
+CREATE TABLE IF NOT EXISTS user_identities (
+    id         bigint AUTO_INCREMENT PRIMARY KEY,
+    user_id    bigint NOT NULL,
+    provider   varchar(64) NOT NULL,
+    subject    varchar(255) NOT NULL,
+    email      varchar(255) DEFAULT '',
+    created_at datetime(6) DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at datetime(6) DEFAULT CURRENT_TIMESTAMP(6),
+    deleted_at datetime(6) DEFAULT NULL,
+    is_deleted boolean DEFAULT false,
+    KEY idx_user_identities_user_id (user_id),
+    UNIQUE KEY idx_user_identities_provider_subject (provider, subject),
+    CONSTRAINT fk_user_identities_user FOREIGN KEY (user_id) REFERENCES users (id)
+) DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci;
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id           bigint AUTO_INCREMENT PRIMARY KEY,
+    user_id      bigint NOT NULL,
+    name         varchar(100) NOT NULL,
+    prefix       varchar(32) NOT NULL,
+    key_hash     varchar(64) NOT NULL,
+    scopes       varchar(1024) DEFAULT '',
+    expires_at   datetime(6) DEFAULT NULL,
+    last_used_at datetime(6) DEFAULT NULL,
+    revoked_at   datetime(6) DEFAULT NULL,
+    created_at   datetime(6) DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at   datetime(6) DEFAULT CURRENT_TIMESTAMP(6),
+    deleted_at   datetime(6) DEFAULT NULL,
+    is_deleted   boolean DEFAULT false,
+    KEY idx_api_keys_user_id (user_id),
+    UNIQUE KEY idx_api_keys_key_hash (key_hash),
+    CONSTRAINT fk_api_keys_user FOREIGN KEY (user_id) REFERENCES users (id)
+) DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci;
+
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id         bigint AUTO_INCREMENT PRIMARY KEY,
+    actor_id   bigint DEFAULT NULL,
+    target_id  bigint DEFAULT NULL,
+    action     varchar(255) NOT NULL,
+    ip_address varchar(45) DEFAULT '',
+    user_agent varchar(512) DEFAULT '',
+    changes    text,
+    details    text,
+    created_at datetime(6) DEFAULT CURRENT_TIMESTAMP(6),
+    KEY idx_audit_logs_actor_id (actor_id),
+    KEY idx_audit_logs_target_id (target_id),
+    KEY idx_audit_logs_action (action),
+    KEY idx_audit_logs_created_at (created_at)
+) DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci;
+
+CREATE TABLE IF NOT EXISTS login_attempts (
+    attempt_key     varchar(255) PRIMARY KEY,
+    failures        bigint NOT NULL DEFAULT 0,
+    lock_count      bigint NOT NULL DEFAULT 0,
+    locked_until    datetime(6) DEFAULT NULL,
+    last_failure_at datetime(6)
+) DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci;
+
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    revocation_key varchar(128) PRIMARY KEY,
+    issued_before  datetime(6) DEFAULT NULL,
+    expires_at     datetime(6) NOT NULL,
+    KEY idx_revoked_tokens_expires_at (expires_at)
+) DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci;
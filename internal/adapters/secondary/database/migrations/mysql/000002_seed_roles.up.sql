@@ -0,0 +1,17 @@
+INSERT IGNORE INTO permissions (name) VALUES
+    ('users:read'),
+    ('users:create'),
+    ('users:update'),
+    ('users:delete'),
+    ('roles:assign'),
+    ('audit_logs:read');
+
+INSERT IGNORE INTO roles (name) VALUES
+    ('admin'),
+    ('user');
+
+INSERT IGNORE INTO role_permissions (role_id, permission_id)
+SELECT roles.id, permissions.id
+FROM roles CROSS JOIN permissions
+WHERE roles.name = 'admin'
+  AND permissions.name IN ('users:read', 'users:create', 'users:update', 'users:delete', 'roles:assign', 'audit_logs:read');
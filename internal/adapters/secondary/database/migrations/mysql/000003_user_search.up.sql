@@ -0,0 +1,3 @@
+-- Nothing to create: user search uses LIKE, which the accent- and
+-- case-insensitive column collation already handles. The version exists
+-- so every dialect shares the same numbering.
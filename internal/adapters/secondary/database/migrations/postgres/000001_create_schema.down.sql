@@ -0,0 +1,15 @@
+DROP TABLE IF EXISTS revoked_tokens;
+DROP TABLE IF EXISTS login_attempts;
+DROP TABLE IF EXISTS audit_logs;
+DROP TABLE IF EXISTS api_keys;
+DROP TABLE IF EXISTS user_identities;
+DROP TABLE IF EXISTS password_histories;
+DROP TABLE IF EXISTS one_time_tokens;
+DROP TABLE IF EXISTS recovery_codes;
+DROP TABLE IF EXISTS refresh_tokens;
+DROP TABLE IF EXISTS user_roles;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS roles;
+DROP TABLE IF EXISTS permissions;
+DROP TYPE IF EXISTS gender;
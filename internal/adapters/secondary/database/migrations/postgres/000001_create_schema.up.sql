@@ -0,0 +1,190 @@
+-- Statements are idempotent so a database previously created by
+-- AutoMigrate can be brought under version control with "migrate up".
+
+DO $$ BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'gender') THEN
+        CREATE TYPE gender AS ENUM ('Male', 'Female', 'Unknown');
+    END IF;
+END $$;
+
+CREATE TABLE IF NOT EXISTS permissions (
+    id          bigserial PRIMARY KEY,
+    name        text NOT NULL,
+    description text DEFAULT '',
+    created_at  timestamp DEFAULT CURRENT_TIMESTAMP,
+    updated_at  timestamp DEFAULT CURRENT_TIMESTAMP,
+    deleted_at  timestamp DEFAULT NULL,
+    is_deleted  boolean DEFAULT false
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_permissions_name ON permissions (name);
+
+CREATE TABLE IF NOT EXISTS roles (
+    id          bigserial PRIMARY KEY,
+    name        text NOT NULL,
+    description text DEFAULT '',
+    created_at  timestamp DEFAULT CURRENT_TIMESTAMP,
+    updated_at  timestamp DEFAULT CURRENT_TIMESTAMP,
+    deleted_at  timestamp DEFAULT NULL,
+    is_deleted  boolean DEFAULT false
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_roles_name ON roles (name);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id       bigint NOT NULL,
+    permission_id bigint NOT NULL,
+    PRIMARY KEY (role_id, permission_id),
+    CONSTRAINT fk_role_permissions_role FOREIGN KEY (role_id) REFERENCES roles (id),
+    CONSTRAINT fk_role_permissions_permission FOREIGN KEY (permission_id) REFERENCES permissions (id)
+);
+
+CREATE TABLE IF NOT EXISTS users (
+    id                   bigserial PRIMARY KEY,
+    name                 text NOT NULL,
+    email                text NOT NULL,
+    password             text NOT NULL,
+    avatar               text DEFAULT '',
+    gender               gender DEFAULT NULL,
+    is_active            boolean NOT NULL DEFAULT false,
+    pending_email        text DEFAULT '',
+    two_factor_enabled   boolean NOT NULL DEFAULT false,
+    two_factor_secret    text DEFAULT '',
+    two_factor_last_step bigint NOT NULL DEFAULT 0,
+    created_at           timestamp DEFAULT CURRENT_TIMESTAMP,
+    updated_at           timestamp DEFAULT CURRENT_TIMESTAMP,
+    deleted_at           timestamp DEFAULT NULL,
+    is_deleted           boolean DEFAULT false
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id bigint NOT NULL,
+    role_id bigint NOT NULL,
+    PRIMARY KEY (user_id, role_id),
+    CONSTRAINT fk_user_roles_user FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_user_roles_role FOREIGN KEY (role_id) REFERENCES roles (id)
+);
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id                 bigserial PRIMARY KEY,
+    user_id            bigint NOT NULL,
+    token              text NOT NULL,
+    family_id          text NOT NULL,
+    parent_id          bigint DEFAULT NULL,
+    expiry_at          timestamp NOT NULL,
+    is_revoked         boolean NOT NULL DEFAULT false,
+    rotated_at         timestamp DEFAULT NULL,
+    user_agent         text DEFAULT '',
+    ip_address         text DEFAULT '',
+    session_started_at timestamp DEFAULT CURRENT_TIMESTAMP,
+    last_used_at       timestamp DEFAULT CURRENT_TIMESTAMP,
+    created_at         timestamp DEFAULT CURRENT_TIMESTAMP,
+    updated_at         timestamp DEFAULT CURRENT_TIMESTAMP,
+    deleted_at         timestamp DEFAULT NULL,
+    is_deleted         boolean DEFAULT false,
+    CONSTRAINT uni_refresh_tokens_token UNIQUE (token),
+    CONSTRAINT fk_refresh_tokens_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens (family_id);
+
+CREATE TABLE IF NOT EXISTS recovery_codes (
+    id         bigserial PRIMARY KEY,
+    user_id    bigint NOT NULL,
+    code_hash  text NOT NULL,
+    used_at    timestamp DEFAULT NULL,
+    created_at timestamp DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp DEFAULT CURRENT_TIMESTAMP,
+    deleted_at timestamp DEFAULT NULL,
+    is_deleted boolean DEFAULT false,
+    CONSTRAINT fk_recovery_codes_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_recovery_codes_user_id ON recovery_codes (user_id);
+
+CREATE TABLE IF NOT EXISTS one_time_tokens (
+    id          bigserial PRIMARY KEY,
+    user_id     bigint NOT NULL,
+    purpose     varchar(32) NOT NULL,
+    token_hash  varchar(64) NOT NULL,
+    expires_at  timestamp NOT NULL,
+    consumed_at timestamp DEFAULT NULL,
+    created_at  timestamp DEFAULT CURRENT_TIMESTAMP,
+    updated_at  timestamp DEFAULT CURRENT_TIMESTAMP,
+    deleted_at  timestamp DEFAULT NULL,
+    is_deleted  boolean DEFAULT false,
+    CONSTRAINT fk_one_time_tokens_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_one_time_tokens_user_purpose ON one_time_tokens (user_id, purpose);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_one_time_tokens_token_hash ON one_time_tokens (token_hash);
+
+CREATE TABLE IF NOT EXISTS password_histories (
+    id            bigserial PRIMARY KEY,
+    user_id       bigint NOT NULL,
+    password_hash text NOT NULL,
+    created_at    timestamp DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_password_histories_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_password_histories_user_id ON password_histories (user_id);
+
+CREATE TABLE IF NOT EXISTS user_identities (
+    id         bigserial PRIMARY KEY,
+    user_id    bigint NOT NULL,
+    provider   varchar(64) NOT NULL,
+    subject    varchar(255) NOT NULL,
+    email      text DEFAULT '',
+    created_at timestamp DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp DEFAULT CURRENT_TIMESTAMP,
+    deleted_at timestamp DEFAULT NULL,
+    is_deleted boolean DEFAULT false,
+    CONSTRAINT fk_user_identities_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_user_identities_user_id ON user_identities (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_identities_provider_subject ON user_identities (provider, subject);
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id           bigserial PRIMARY KEY,
+    user_id      bigint NOT NULL,
+    name         varchar(100) NOT NULL,
+    prefix       varchar(32) NOT NULL,
+    key_hash     varchar(64) NOT NULL,
+    scopes       text DEFAULT '',
+    expires_at   timestamp DEFAULT NULL,
+    last_used_at timestamp DEFAULT NULL,
+    revoked_at   timestamp DEFAULT NULL,
+    created_at   timestamp DEFAULT CURRENT_TIMESTAMP,
+    updated_at   timestamp DEFAULT CURRENT_TIMESTAMP,
+    deleted_at   timestamp DEFAULT NULL,
+    is_deleted   boolean DEFAULT false,
+    CONSTRAINT fk_api_keys_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_api_keys_user_id ON api_keys (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_keys_key_hash ON api_keys (key_hash);
+
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id         bigserial PRIMARY KEY,
+    actor_id   bigint DEFAULT NULL,
+    target_id  bigint DEFAULT NULL,
+    action     text NOT NULL,
+    ip_address varchar(45) DEFAULT '',
+    user_agent text DEFAULT '',
+    changes    text DEFAULT '',
+    details    text DEFAULT '',
+    created_at timestamp DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor_id ON audit_logs (actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_target_id ON audit_logs (target_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_action ON audit_logs (action);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON audit_logs (created_at);
+
+CREATE TABLE IF NOT EXISTS login_attempts (
+    attempt_key     text PRIMARY KEY,
+    failures        bigint NOT NULL DEFAULT 0,
+    lock_count      bigint NOT NULL DEFAULT 0,
+    locked_until    timestamp DEFAULT NULL,
+    last_failure_at timestamp
+);
+
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    revocation_key varchar(128) PRIMARY KEY,
+    issued_before  timestamp DEFAULT NULL,
+    expires_at     timestamp NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires_at ON revoked_tokens (expires_at);
@@ -0,0 +1,20 @@
+INSERT INTO permissions (name) VALUES
+    ('users:read'),
+    ('users:create'),
+    ('users:update'),
+    ('users:delete'),
+    ('roles:assign'),
+    ('audit_logs:read')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO roles (name) VALUES
+    ('admin'),
+    ('user')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT roles.id, permissions.id
+FROM roles CROSS JOIN permissions
+WHERE roles.name = 'admin'
+  AND permissions.name IN ('users:read', 'users:create', 'users:update', 'users:delete', 'roles:assign', 'audit_logs:read')
+ON CONFLICT DO NOTHING;
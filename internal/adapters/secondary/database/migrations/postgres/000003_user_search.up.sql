@@ -0,0 +1,15 @@
+-- Case- and accent-insensitive user search. pg_trgm and unaccent are
+-- trusted extensions, so the database owner can create them.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- unaccent() is only STABLE because its dictionary could change, which
+-- keeps it out of index expressions. Pinning the dictionary makes this
+-- wrapper safe to declare IMMUTABLE.
+CREATE OR REPLACE FUNCTION immutable_unaccent(text) RETURNS text
+    LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT
+    AS $$ SELECT public.unaccent('public.unaccent'::regdictionary, $1) $$;
+
+-- Trigram indexes serve ILIKE '%term%' on the unaccented columns.
+CREATE INDEX IF NOT EXISTS idx_users_name_trgm ON users USING gin (immutable_unaccent(name) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_users_email_trgm ON users USING gin (immutable_unaccent(email) gin_trgm_ops);
@@ -0,0 +1,3 @@
+-- Nothing to create: SQLite has no trigram or unaccent support, so user
+-- search falls back to LIKE, which ignores case for ASCII letters only. The
+-- version exists so every dialect shares the same numbering.
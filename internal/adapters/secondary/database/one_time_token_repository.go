@@ -0,0 +1,69 @@
+package database
+
+import (
+	"context"
+	"go-gin-clean/internal/core/domain/entities"
+	"go-gin-clean/internal/core/domain/enums"
+	"go-gin-clean/internal/core/ports"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type OneTimeTokenRepository struct {
+	db       *gorm.DB
+	baseRepo ports.BaseRepository[entities.OneTimeToken]
+}
+
+func NewOneTimeTokenRepository(db *gorm.DB) ports.OneTimeTokenRepository {
+	baseRepo := NewBaseRepository[entities.OneTimeToken](db)
+	return &OneTimeTokenRepository{
+		db:       db,
+		baseRepo: baseRepo,
+	}
+}
+
+func (r *OneTimeTokenRepository) Issue(ctx context.Context, token *entities.OneTimeToken) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Where("user_id = ? AND purpose = ? AND consumed_at IS NULL", token.UserID, token.Purpose).
+			Delete(&entities.OneTimeToken{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Omit("User").Create(token).Error
+	})
+}
+
+func (r *OneTimeTokenRepository) FindUnconsumed(ctx context.Context, tokenHash string, purpose enums.TokenPurpose) (*entities.OneTimeToken, error) {
+	return r.baseRepo.FindFirst(ctx, "token_hash = ? AND purpose = ? AND consumed_at IS NULL", tokenHash, purpose)
+}
+
+func (r *OneTimeTokenRepository) Consume(ctx context.Context, tokenHash string, purpose enums.TokenPurpose) (*entities.OneTimeToken, error) {
+	now := time.Now()
+
+	result := r.db.WithContext(ctx).Model(&entities.OneTimeToken{}).
+		Where("token_hash = ? AND purpose = ? AND consumed_at IS NULL", tokenHash, purpose).
+		Update("consumed_at", now)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return r.baseRepo.FindFirst(ctx, "token_hash = ?", tokenHash)
+}
+
+func (r *OneTimeTokenRepository) InvalidateByUserID(ctx context.Context, userID int64, purpose enums.TokenPurpose) error {
+	return r.db.WithContext(ctx).
+		Where("user_id = ? AND purpose = ? AND consumed_at IS NULL", userID, purpose).
+		Delete(&entities.OneTimeToken{}).Error
+}
+
+func (r *OneTimeTokenRepository) DeleteExpired(ctx context.Context) error {
+	return r.db.WithContext(ctx).
+		Where("expires_at < ?", time.Now()).
+		Delete(&entities.OneTimeToken{}).Error
+}
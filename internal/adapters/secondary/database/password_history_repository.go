@@ -0,0 +1,54 @@
+package database
+
+import (
+	"context"
+	"go-gin-clean/internal/core/domain/entities"
+	"go-gin-clean/internal/core/ports"
+
+	"gorm.io/gorm"
+)
+
+type PasswordHistoryRepository struct {
+	db       *gorm.DB
+	baseRepo ports.BaseRepository[entities.PasswordHistory]
+}
+
+func NewPasswordHistoryRepository(db *gorm.DB) ports.PasswordHistoryRepository {
+	baseRepo := NewBaseRepository[entities.PasswordHistory](db)
+	return &PasswordHistoryRepository{
+		db:       db,
+		baseRepo: baseRepo,
+	}
+}
+
+func (r *PasswordHistoryRepository) Create(ctx context.Context, history *entities.PasswordHistory) error {
+	return r.db.WithContext(ctx).Omit("User").Create(history).Error
+}
+
+func (r *PasswordHistoryRepository) FindRecentByUserID(ctx context.Context, userID int64, limit int) ([]*entities.PasswordHistory, error) {
+	var histories []*entities.PasswordHistory
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at desc, id desc").
+		Limit(limit).
+		Find(&histories).Error
+	return histories, err
+}
+
+func (r *PasswordHistoryRepository) Prune(ctx context.Context, userID int64, keep int) error {
+	recent, err := r.FindRecentByUserID(ctx, userID, keep)
+	if err != nil {
+		return err
+	}
+
+	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	if len(recent) > 0 {
+		ids := make([]int64, len(recent))
+		for i, history := range recent {
+			ids[i] = history.ID
+		}
+		query = query.Where("id NOT IN ?", ids)
+	}
+
+	return query.Delete(&entities.PasswordHistory{}).Error
+}
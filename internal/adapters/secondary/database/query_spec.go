@@ -0,0 +1,110 @@
+package database
+
+import (
+	"go-gin-clean/internal/core/contracts"
+	"go-gin-clean/pkg/config"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// applyFilters adds one condition per filter. Columns come from the
+// whitelist, never from the request, and values are bound as parameters.
+func applyFilters(db *gorm.DB, fields contracts.QueryFields, spec *contracts.QuerySpec) (*gorm.DB, error) {
+	if spec == nil {
+		return db, nil
+	}
+
+	for _, filter := range spec.Filters {
+		value, err := fields.FilterValue(filter)
+		if err != nil {
+			return nil, err
+		}
+
+		field := fields[filter.Field]
+		column, value := operands(db, field.Column, field.Type, value)
+
+		switch filter.Operator {
+		case contracts.FilterEq:
+			db = db.Where(clause.Eq{Column: column, Value: value})
+		case contracts.FilterNe:
+			db = db.Where(clause.Neq{Column: column, Value: value})
+		case contracts.FilterGt:
+			db = db.Where(clause.Gt{Column: column, Value: value})
+		case contracts.FilterGte:
+			db = db.Where(clause.Gte{Column: column, Value: value})
+		case contracts.FilterLt:
+			db = db.Where(clause.Lt{Column: column, Value: value})
+		case contracts.FilterLte:
+			db = db.Where(clause.Lte{Column: column, Value: value})
+		case contracts.FilterLike:
+			pattern := "%" + likeEscaper.Replace(strings.ToLower(value.(string))) + "%"
+			db = db.Where(clause.Expr{SQL: likeSQL(db, "LOWER(?)"), Vars: []any{column, pattern}})
+		case contracts.FilterIn:
+			db = db.Where(clause.IN{Column: column, Values: value.([]any)})
+		}
+	}
+
+	return db, nil
+}
+
+// operands returns column and value in the form they are compared in.
+// SQLite keeps timestamps as text in whichever format wrote them, and
+// CURRENT_TIMESTAMP defaults differ from bound parameters, so time values
+// are compared as julianday() numbers there.
+func operands(db *gorm.DB, column string, fieldType contracts.FieldType, value any) (clause.Column, any) {
+	if fieldType != contracts.FieldTime || db.Dialector.Name() != config.DriverSQLite {
+		return clause.Column{Name: column}, value
+	}
+
+	return clause.Column{Name: "julianday(" + db.Statement.Quote(column) + ")", Raw: true},
+		clause.Expr{SQL: "julianday(?)", Vars: []any{value}}
+}
+
+// applySort orders by the requested fields and then by id, so rows with
+// equal sort keys keep a stable order across pages. The spec must have been
+// validated against fields.
+func applySort(db *gorm.DB, fields contracts.QueryFields, spec *contracts.QuerySpec) *gorm.DB {
+	sortedByID := false
+	if spec != nil {
+		for _, sort := range spec.Sort {
+			column := fields[sort.Field].Column
+			db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: column}, Desc: sort.Desc})
+			sortedByID = sortedByID || column == "id"
+		}
+	}
+
+	if !sortedByID {
+		db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: "id"}})
+	}
+
+	return db
+}
+
+// findPage filters db by spec, counts the matches and returns one sorted
+// page of them. scopes only apply to the page query, ahead of the spec
+// sort keys.
+func findPage[T any](db *gorm.DB, limit, offset int, fields contracts.QueryFields, spec *contracts.QuerySpec, scopes ...func(*gorm.DB) *gorm.DB) ([]*T, int64, error) {
+	var entities []*T
+	var count int64
+
+	db, err := applyFilters(db, fields, spec)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := db.Model(new(T)).Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	for _, scope := range scopes {
+		db = scope(db)
+	}
+
+	if err := applySort(db, fields, spec).Limit(limit).Offset(offset).Find(&entities).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return entities, count, nil
+}
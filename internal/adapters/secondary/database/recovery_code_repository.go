@@ -0,0 +1,63 @@
+package database
+
+import (
+	"context"
+	"go-gin-clean/internal/core/domain/entities"
+	"go-gin-clean/internal/core/ports"
+
+	"gorm.io/gorm"
+)
+
+type RecoveryCodeRepository struct {
+	db       *gorm.DB
+	baseRepo ports.BaseRepository[entities.RecoveryCode]
+}
+
+func NewRecoveryCodeRepository(db *gorm.DB) ports.RecoveryCodeRepository {
+	baseRepo := NewBaseRepository[entities.RecoveryCode](db)
+	return &RecoveryCodeRepository{
+		db:       db,
+		baseRepo: baseRepo,
+	}
+}
+
+func (r *RecoveryCodeRepository) ReplaceAll(ctx context.Context, userID int64, codes []*entities.RecoveryCode) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&entities.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		if len(codes) == 0 {
+			return nil
+		}
+
+		return tx.Omit("User").Create(&codes).Error
+	})
+}
+
+func (r *RecoveryCodeRepository) FindUnusedByUserID(ctx context.Context, userID int64) ([]*entities.RecoveryCode, error) {
+	return r.baseRepo.Where(ctx, "user_id = ? AND used_at IS NULL", userID)
+}
+
+func (r *RecoveryCodeRepository) MarkAsUsed(ctx context.Context, code *entities.RecoveryCode) error {
+	code.MarkAsUsed()
+
+	result := r.db.WithContext(ctx).Model(&entities.RecoveryCode{}).
+		Where("id = ? AND used_at IS NULL", code.ID).
+		Update("used_at", code.UsedAt)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r *RecoveryCodeRepository) DeleteByUserID(ctx context.Context, userID int64) error {
+	return r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Delete(&entities.RecoveryCode{}).Error
+}
@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"go-gin-clean/internal/core/domain/entities"
+	"go-gin-clean/internal/core/domain/errors"
 	"go-gin-clean/internal/core/ports"
 	"time"
 
@@ -27,14 +28,26 @@ func (r *RefreshTokenRepository) Save(ctx context.Context, token *entities.Refre
 	return err
 }
 
+// FindByToken returns the token regardless of its state so callers can tell a
+// replayed (rotated) token apart from an unknown one.
 func (r *RefreshTokenRepository) FindByToken(ctx context.Context, token string) (*entities.RefreshToken, error) {
-	return r.baseRepo.FindFirst(ctx, "token = ? AND is_revoked = ? AND expiry_at > ?", token, false, time.Now())
+	return r.baseRepo.FindFirst(ctx, "token = ?", token)
 }
 
 func (r *RefreshTokenRepository) FindByUserID(ctx context.Context, userID int64) ([]*entities.RefreshToken, error) {
 	return r.baseRepo.Where(ctx, "user_id = ?", userID)
 }
 
+func (r *RefreshTokenRepository) FindActiveByUserID(ctx context.Context, userID int64) ([]*entities.RefreshToken, error) {
+	return r.baseRepo.Where(ctx, "user_id = ? AND is_revoked = ? AND expiry_at > ?", userID, false, time.Now())
+}
+
+func (r *RefreshTokenRepository) RevokeAllByUserIDExceptFamily(ctx context.Context, userID int64, familyID string) error {
+	return r.db.WithContext(ctx).Model(&entities.RefreshToken{}).
+		Where("user_id = ? AND family_id <> ?", userID, familyID).
+		Update("is_revoked", true).Error
+}
+
 func (r *RefreshTokenRepository) RevokeAllByUserID(ctx context.Context, userID int64) error {
 	return r.db.WithContext(ctx).Model(&entities.RefreshToken{}).
 		Where("user_id = ?", userID).
@@ -47,6 +60,34 @@ func (r *RefreshTokenRepository) RevokeByToken(ctx context.Context, token string
 		Update("is_revoked", true).Error
 }
 
+func (r *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	return r.db.WithContext(ctx).Model(&entities.RefreshToken{}).
+		Where("family_id = ?", familyID).
+		Update("is_revoked", true).Error
+}
+
+// Rotate marks the token as exchanged. It only succeeds once per token, so two
+// concurrent refreshes with the same token cannot both obtain a successor.
+func (r *RefreshTokenRepository) Rotate(ctx context.Context, token *entities.RefreshToken) error {
+	token.MarkAsRotated()
+
+	result := r.db.WithContext(ctx).Model(&entities.RefreshToken{}).
+		Where("id = ? AND is_revoked = ? AND rotated_at IS NULL", token.ID, false).
+		Updates(map[string]any{
+			"is_revoked": true,
+			"rotated_at": token.RotatedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.ErrTokenReused
+	}
+
+	return nil
+}
+
 func (r *RefreshTokenRepository) DeleteExpired(ctx context.Context) error {
 	return r.db.WithContext(ctx).
 		Where("expiry_at < ?", time.Now()).
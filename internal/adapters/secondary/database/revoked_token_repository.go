@@ -0,0 +1,39 @@
+package database
+
+import (
+	"context"
+	"go-gin-clean/internal/core/domain/entities"
+	"go-gin-clean/internal/core/ports"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RevokedTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewRevokedTokenRepository(db *gorm.DB) ports.RevokedTokenRepository {
+	return &RevokedTokenRepository{db: db}
+}
+
+func (r *RevokedTokenRepository) Save(ctx context.Context, token *entities.RevokedToken) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(token).Error
+}
+
+func (r *RevokedTokenRepository) FindByKeys(ctx context.Context, keys []string) ([]*entities.RevokedToken, error) {
+	var tokens []*entities.RevokedToken
+	err := r.db.WithContext(ctx).
+		Where("revocation_key IN ? AND expires_at > ?", keys, time.Now()).
+		Find(&tokens).Error
+	return tokens, err
+}
+
+func (r *RevokedTokenRepository) DeleteExpired(ctx context.Context) error {
+	return r.db.WithContext(ctx).
+		Where("expires_at <= ?", time.Now()).
+		Delete(&entities.RevokedToken{}).Error
+}
@@ -0,0 +1,64 @@
+package database
+
+import (
+	"context"
+	"go-gin-clean/internal/core/domain/entities"
+	"go-gin-clean/internal/core/ports"
+
+	"gorm.io/gorm"
+)
+
+type RoleRepository struct {
+	db       *gorm.DB
+	baseRepo ports.BaseRepository[entities.Role]
+}
+
+func NewRoleRepository(db *gorm.DB) ports.RoleRepository {
+	baseRepo := NewBaseRepository[entities.Role](db)
+	return &RoleRepository{
+		db:       db,
+		baseRepo: baseRepo,
+	}
+}
+
+func (r *RoleRepository) FindAll(ctx context.Context) ([]*entities.Role, error) {
+	var roles []*entities.Role
+	if err := r.db.WithContext(ctx).Preload("Permissions").Order("id asc").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (r *RoleRepository) FindByName(ctx context.Context, name string) (*entities.Role, error) {
+	var role entities.Role
+	if err := r.db.WithContext(ctx).Preload("Permissions").Where("name = ?", name).First(&role).Error; err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *RoleRepository) FindByNames(ctx context.Context, names []string) ([]*entities.Role, error) {
+	return r.baseRepo.Where(ctx, "name IN ?", names)
+}
+
+func (r *RoleRepository) AssignToUser(ctx context.Context, user *entities.User, roles []*entities.Role) error {
+	replacement := make([]entities.Role, len(roles))
+	for i, role := range roles {
+		replacement[i] = *role
+	}
+
+	return r.db.WithContext(ctx).Model(user).Association("Roles").Replace(replacement)
+}
+
+func (r *RoleRepository) Create(ctx context.Context, role *entities.Role) (*entities.Role, error) {
+	return r.baseRepo.Create(ctx, role)
+}
+
+func (r *RoleRepository) AssignPermissions(ctx context.Context, role *entities.Role, permissions []*entities.Permission) error {
+	replacement := make([]entities.Permission, len(permissions))
+	for i, permission := range permissions {
+		replacement[i] = *permission
+	}
+
+	return r.db.WithContext(ctx).Model(role).Association("Permissions").Replace(replacement)
+}
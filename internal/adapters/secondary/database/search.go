@@ -0,0 +1,71 @@
+package database
+
+import (
+	"go-gin-clean/pkg/config"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// likeEscaper escapes the LIKE wildcards so a search term only matches
+// literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// searchCondition matches rows where any of the columns contains term.
+// Postgres ignores case and accents through immutable_unaccent and the
+// trigram indexes created by migration 000003, MySQL through the column
+// collation, while SQLite's LIKE only ignores the case of ASCII letters.
+func searchCondition(db *gorm.DB, columns []string, term string) clause.Expression {
+	pattern := "%" + likeEscaper.Replace(term) + "%"
+
+	sql := likeSQL(db, "?")
+	if db.Dialector.Name() == config.DriverPostgres {
+		sql = "immutable_unaccent(?) ILIKE immutable_unaccent(?)"
+	}
+
+	conditions := make([]clause.Expression, len(columns))
+	for i, column := range columns {
+		conditions[i] = clause.Expr{
+			SQL:  sql,
+			Vars: []any{clause.Column{Name: column}, pattern},
+		}
+	}
+
+	return clause.Or(conditions...)
+}
+
+// likeSQL matches operand against a pattern escaped with likeEscaper.
+// Postgres and MySQL treat the backslash as escape character by default,
+// SQLite only with an ESCAPE clause.
+func likeSQL(db *gorm.DB, operand string) string {
+	switch db.Dialector.Name() {
+	case config.DriverPostgres, config.DriverMySQL:
+		return operand + " LIKE ?"
+	default:
+		return operand + ` LIKE ? ESCAPE '\'`
+	}
+}
+
+// searchRank is a scope ordering rows by how closely their best matching
+// column resembles term, using trigram word similarity. The rank is
+// selected as search_rank so later sort keys can follow it. It is nil on
+// databases without pg_trgm.
+func searchRank(db *gorm.DB, columns []string, term string) func(*gorm.DB) *gorm.DB {
+	if db.Dialector.Name() != config.DriverPostgres {
+		return nil
+	}
+
+	similarities := make([]string, len(columns))
+	var vars []any
+	for i, column := range columns {
+		similarities[i] = "word_similarity(immutable_unaccent(?), immutable_unaccent(?))"
+		vars = append(vars, term, clause.Column{Name: column})
+	}
+
+	return func(db *gorm.DB) *gorm.DB {
+		return db.
+			Select("*, GREATEST("+strings.Join(similarities, ", ")+") AS search_rank", vars...).
+			Order(clause.OrderByColumn{Column: clause.Column{Name: "search_rank"}, Desc: true})
+	}
+}
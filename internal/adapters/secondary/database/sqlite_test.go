@@ -0,0 +1,278 @@
+//go:build cgo
+
+package database_test
+
+import (
+	"context"
+	"go-gin-clean/internal/adapters/secondary/database"
+	"go-gin-clean/internal/adapters/secondary/database/migrations"
+	"go-gin-clean/internal/core/contracts"
+	"go-gin-clean/internal/core/domain/entities"
+	"go-gin-clean/internal/core/domain/enums"
+	"go-gin-clean/internal/core/domain/errors"
+	"go-gin-clean/internal/core/ports"
+	"go-gin-clean/pkg/config"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openSQLite migrates a fresh SQLite database file. The sqlite driver wraps
+// mattn/go-sqlite3, so this test only builds with CGO_ENABLED=1.
+func openSQLite(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := database.Open(&config.DatabaseConfig{
+		Driver:       config.DriverSQLite,
+		DBName:       filepath.Join(t.TempDir(), "test.db"),
+		MaxOpenConns: 10,
+		MaxIdleConns: 5,
+	}, &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+
+	if _, err := migrator.Up(context.Background(), 0); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+
+	return db
+}
+
+func TestSQLiteMigrations(t *testing.T) {
+	db := openSQLite(t)
+	ctx := context.Background()
+
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+
+	for _, status := range statuses {
+		if !status.Applied || status.Dirty {
+			t.Fatalf("migration %d_%s: applied=%v dirty=%v", status.Version, status.Name, status.Applied, status.Dirty)
+		}
+	}
+
+	// Every migration must also revert cleanly
+	if _, err := migrator.Down(ctx, 0); err != nil {
+		t.Fatalf("migrate down: %v", err)
+	}
+
+	if _, err := migrator.Up(ctx, 0); err != nil {
+		t.Fatalf("migrate up again: %v", err)
+	}
+}
+
+func TestSQLiteUserRepository(t *testing.T) {
+	db := openSQLite(t)
+	ctx := context.Background()
+
+	users := database.NewUserRepository(db)
+	roles := database.NewRoleRepository(db)
+
+	role, err := roles.FindByName(ctx, enums.RoleUser)
+	if err != nil {
+		t.Fatalf("seeded role: %v", err)
+	}
+
+	for _, email := range []string{"ann@example.com", "bob@example.com", "cid@example.com"} {
+		user, err := entities.NewUser(email[:3], email, "hash", "", enums.Unknown)
+		if err != nil {
+			t.Fatalf("new user: %v", err)
+		}
+		user.Roles = []entities.Role{*role}
+
+		if _, err := users.Create(ctx, user); err != nil {
+			t.Fatalf("create %s: %v", email, err)
+		}
+	}
+
+	found, err := users.FindByEmail(ctx, "bob@example.com")
+	if err != nil {
+		t.Fatalf("find by email: %v", err)
+	}
+
+	if !found.HasRole(enums.RoleUser) {
+		t.Fatalf("roles not loaded: %+v", found.Roles)
+	}
+
+	if err := users.Delete(ctx, found.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	if !users.ExistsByEmail(ctx, "bob@example.com") {
+		t.Fatal("soft-deleted email should stay reserved")
+	}
+
+	spec := &contracts.QuerySpec{Search: "example", Sort: []contracts.Sort{{Field: "email"}}}
+	first, page, err := users.FindAllByCursor(ctx, &contracts.CursorRequest{Limit: 1}, spec)
+	if err != nil {
+		t.Fatalf("first page: %v", err)
+	}
+
+	if len(first) != 1 || first[0].Email != "ann@example.com" || page.NextCursor == "" {
+		t.Fatalf("unexpected first page %v %+v", emails(first), page)
+	}
+
+	second, page, err := users.FindAllByCursor(ctx, &contracts.CursorRequest{Cursor: page.NextCursor, Limit: 1}, spec)
+	if err != nil {
+		t.Fatalf("second page: %v", err)
+	}
+
+	if len(second) != 1 || second[0].Email != "cid@example.com" || page.NextCursor != "" {
+		t.Fatalf("unexpected second page %v %+v", emails(second), page)
+	}
+
+	back, _, err := users.FindAllByCursor(ctx, &contracts.CursorRequest{Cursor: page.PrevCursor, Limit: 1}, spec)
+	if err != nil {
+		t.Fatalf("previous page: %v", err)
+	}
+
+	if len(back) != 1 || back[0].Email != "ann@example.com" {
+		t.Fatalf("unexpected previous page %v", emails(back))
+	}
+}
+
+func TestSQLiteRefreshTokenRotateOnce(t *testing.T) {
+	db := openSQLite(t)
+	ctx := context.Background()
+
+	user, err := entities.NewUser("Ann", "ann@example.com", "hash", "", enums.Unknown)
+	if err != nil {
+		t.Fatalf("new user: %v", err)
+	}
+	if _, err := database.NewUserRepository(db).Create(ctx, user); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	tokens := database.NewRefreshTokenRepository(db)
+	token := entities.NewRefreshToken(user.ID, "token-1", "family-1", nil, time.Now().Add(time.Hour), false, *user)
+	if err := tokens.Save(ctx, token); err != nil {
+		t.Fatalf("save token: %v", err)
+	}
+
+	stale := *token
+	if err := tokens.Rotate(ctx, token); err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+
+	if err := tokens.Rotate(ctx, &stale); err != errors.ErrTokenReused {
+		t.Fatalf("second rotate: err = %v, want %v", err, errors.ErrTokenReused)
+	}
+}
+
+func TestSQLiteLoginAttemptConcurrentUpdates(t *testing.T) {
+	db := openSQLite(t)
+	ctx := context.Background()
+
+	var attempts ports.LoginAttemptRepository = database.NewLoginAttemptRepository(db)
+
+	const workers = 20
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := attempts.Update(ctx, "email:ann@example.com", func(attempt *entities.LoginAttempt) {
+				attempt.RegisterFailure(1000, time.Hour, time.Minute, time.Hour)
+			})
+			if err != nil {
+				t.Errorf("update: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	attempt, err := attempts.FindByKey(ctx, "email:ann@example.com")
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+
+	if attempt.Failures != workers {
+		t.Fatalf("failures = %d, want %d", attempt.Failures, workers)
+	}
+}
+
+func emails(users []*entities.User) []string {
+	result := make([]string, 0, len(users))
+	for _, user := range users {
+		result = append(result, user.Email)
+	}
+	return result
+}
+
+func TestSQLiteLoginAttemptDeleteStale(t *testing.T) {
+	db := openSQLite(t)
+	ctx := context.Background()
+
+	attempts := database.NewLoginAttemptRepository(db)
+	for key, maxAttempts := range map[string]int{"ip:idle": 100, "ip:locked": 1} {
+		_, err := attempts.Update(ctx, key, func(attempt *entities.LoginAttempt) {
+			attempt.RegisterFailure(maxAttempts, time.Minute, time.Hour, time.Hour)
+		})
+		if err != nil {
+			t.Fatalf("update %s: %v", key, err)
+		}
+	}
+
+	if err := attempts.DeleteStale(ctx, time.Now().Add(time.Second)); err != nil {
+		t.Fatalf("DeleteStale: %v", err)
+	}
+
+	if idle, _ := attempts.FindByKey(ctx, "ip:idle"); idle.Failures != 0 {
+		t.Fatal("idle counter was kept")
+	}
+
+	if locked, _ := attempts.FindByKey(ctx, "ip:locked"); !locked.IsLocked() {
+		t.Fatal("locked counter was deleted")
+	}
+}
+
+func TestSQLiteLikeFilterMatchesWildcardsLiterally(t *testing.T) {
+	db := openSQLite(t)
+	ctx := context.Background()
+
+	users := database.NewUserRepository(db)
+	for _, email := range []string{"a_b@example.com", "axb@example.com", "100%@example.com"} {
+		user, err := entities.NewUser("User", email, "hash", "", enums.Unknown)
+		if err != nil {
+			t.Fatalf("new user: %v", err)
+		}
+		if _, err := users.Create(ctx, user); err != nil {
+			t.Fatalf("create %s: %v", email, err)
+		}
+	}
+
+	for term, want := range map[string]string{"a_b": "a_b@example.com", "0%": "100%@example.com"} {
+		spec := &contracts.QuerySpec{Filters: []contracts.Filter{{Field: "email", Operator: contracts.FilterLike, Value: term}}}
+		found, _, err := users.FindAllByCursor(ctx, &contracts.CursorRequest{Limit: 10}, spec)
+		if err != nil {
+			t.Fatalf("filter %q: %v", term, err)
+		}
+
+		if len(found) != 1 || found[0].Email != want {
+			t.Fatalf("filter %q matched %v, want [%s]", term, emails(found), want)
+		}
+	}
+}
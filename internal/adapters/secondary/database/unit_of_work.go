@@ -0,0 +1,62 @@
+package database
+
+import (
+	"context"
+	"go-gin-clean/internal/core/ports"
+
+	"gorm.io/gorm"
+)
+
+type UnitOfWork struct {
+	db *gorm.DB
+}
+
+func NewUnitOfWork(db *gorm.DB) ports.UnitOfWork {
+	return &UnitOfWork{
+		db: db,
+	}
+}
+
+// Do runs fn inside a GORM transaction. Repositories that open their own
+// transaction nest into it through savepoints.
+func (u *UnitOfWork) Do(ctx context.Context, fn func(repos ports.Repositories) error) error {
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&txRepositories{tx: tx})
+	})
+}
+
+type txRepositories struct {
+	tx *gorm.DB
+}
+
+func (r *txRepositories) Users() ports.UserRepository {
+	return NewUserRepository(r.tx)
+}
+
+func (r *txRepositories) RefreshTokens() ports.RefreshTokenRepository {
+	return NewRefreshTokenRepository(r.tx)
+}
+
+func (r *txRepositories) Roles() ports.RoleRepository {
+	return NewRoleRepository(r.tx)
+}
+
+func (r *txRepositories) RecoveryCodes() ports.RecoveryCodeRepository {
+	return NewRecoveryCodeRepository(r.tx)
+}
+
+func (r *txRepositories) OneTimeTokens() ports.OneTimeTokenRepository {
+	return NewOneTimeTokenRepository(r.tx)
+}
+
+func (r *txRepositories) UserIdentities() ports.UserIdentityRepository {
+	return NewUserIdentityRepository(r.tx)
+}
+
+func (r *txRepositories) PasswordHistories() ports.PasswordHistoryRepository {
+	return NewPasswordHistoryRepository(r.tx)
+}
+
+func (r *txRepositories) APIKeys() ports.APIKeyRepository {
+	return NewAPIKeyRepository(r.tx)
+}
@@ -0,0 +1,34 @@
+package database
+
+import (
+	"context"
+	"go-gin-clean/internal/core/domain/entities"
+	"go-gin-clean/internal/core/ports"
+
+	"gorm.io/gorm"
+)
+
+type UserIdentityRepository struct {
+	db       *gorm.DB
+	baseRepo ports.BaseRepository[entities.UserIdentity]
+}
+
+func NewUserIdentityRepository(db *gorm.DB) ports.UserIdentityRepository {
+	baseRepo := NewBaseRepository[entities.UserIdentity](db)
+	return &UserIdentityRepository{
+		db:       db,
+		baseRepo: baseRepo,
+	}
+}
+
+func (r *UserIdentityRepository) Create(ctx context.Context, identity *entities.UserIdentity) error {
+	return r.db.WithContext(ctx).Omit("User").Create(identity).Error
+}
+
+func (r *UserIdentityRepository) FindByProviderSubject(ctx context.Context, provider, subject string) (*entities.UserIdentity, error) {
+	return r.baseRepo.FindFirst(ctx, "provider = ? AND subject = ?", provider, subject)
+}
+
+func (r *UserIdentityRepository) FindByUserID(ctx context.Context, userID int64) ([]*entities.UserIdentity, error) {
+	return r.baseRepo.Where(ctx, "user_id = ?", userID)
+}
@@ -2,10 +2,15 @@ package database
 
 import (
 	"context"
+	"go-gin-clean/internal/core/contracts"
 	"go-gin-clean/internal/core/domain/entities"
+	"go-gin-clean/internal/core/domain/errors"
 	"go-gin-clean/internal/core/ports"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepository struct {
@@ -21,12 +26,129 @@ func NewUserRepository(db *gorm.DB) ports.UserRepository {
 	}
 }
 
-func (r *UserRepository) FindAll(ctx context.Context, limit, offset int, search string) ([]*entities.User, int64, error) {
-	return r.baseRepo.FindAll(ctx, limit, offset, "name LIKE ? OR email LIKE ?", "%"+search+"%", "%"+search+"%")
+// userSearchColumns are the columns a search term is matched against.
+var userSearchColumns = []string{"name", "email"}
+
+func searchTerm(spec *contracts.QuerySpec) string {
+	if spec == nil {
+		return ""
+	}
+	return strings.TrimSpace(spec.Search)
+}
+
+func (r *UserRepository) FindAll(ctx context.Context, limit, offset int, spec *contracts.QuerySpec) ([]*entities.User, int64, error) {
+	var users []*entities.User
+	var total int64
+	var err error
+
+	if term := searchTerm(spec); term != "" {
+		users, total, err = r.search(ctx, limit, offset, term, spec)
+	} else {
+		users, total, err = r.baseRepo.FindAllByQuery(ctx, limit, offset, contracts.UserQueryFields, spec, nil)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := r.loadRoles(ctx, users); err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+// search lists the users matching term. Unless the client asked for a
+// sort order, the closest matches come first.
+func (r *UserRepository) search(ctx context.Context, limit, offset int, term string, spec *contracts.QuerySpec) ([]*entities.User, int64, error) {
+	if err := contracts.UserQueryFields.Validate(spec); err != nil {
+		return nil, 0, err
+	}
+
+	db := r.db.WithContext(ctx).
+		Where("deleted_at IS NULL").
+		Where(searchCondition(r.db, userSearchColumns, term))
+
+	rank := searchRank(r.db, userSearchColumns, term)
+	if len(spec.Sort) > 0 || rank == nil {
+		return findPage[entities.User](db, limit, offset, contracts.UserQueryFields, spec)
+	}
+
+	return findPage[entities.User](db, limit, offset, contracts.UserQueryFields, spec, rank)
+}
+
+// FindAllByCursor keeps the keyset order when searching, since a relevance
+// rank cannot be encoded in a cursor.
+func (r *UserRepository) FindAllByCursor(ctx context.Context, page *contracts.CursorRequest, spec *contracts.QuerySpec) ([]*entities.User, *contracts.CursorPage, error) {
+	var query any
+	if term := searchTerm(spec); term != "" {
+		query = searchCondition(r.db, userSearchColumns, term)
+	}
+
+	users, result, err := r.baseRepo.FindAllByCursor(ctx, contracts.UserQueryFields, spec, page, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := r.loadRoles(ctx, users); err != nil {
+		return nil, nil, err
+	}
+
+	return users, result, nil
+}
+
+func (r *UserRepository) FindAllDeleted(ctx context.Context, limit, offset int, search string) ([]*entities.User, int64, error) {
+	var query clause.Expression = clause.Expr{SQL: "deleted_at IS NOT NULL"}
+	if search = strings.TrimSpace(search); search != "" {
+		query = clause.And(query, searchCondition(r.db, userSearchColumns, search))
+	}
+
+	users, total, err := r.baseRepo.WithDeleted().FindAll(ctx, limit, offset, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := r.loadRoles(ctx, users); err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+// loadRoles attaches roles to an already fetched page of users without
+// disturbing the order the page was returned in.
+func (r *UserRepository) loadRoles(ctx context.Context, users []*entities.User) error {
+	if len(users) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(users))
+	for i, user := range users {
+		ids[i] = user.ID
+	}
+
+	var withRoles []*entities.User
+	if err := r.db.WithContext(ctx).Preload("Roles").Where("id IN ?", ids).Find(&withRoles).Error; err != nil {
+		return err
+	}
+
+	rolesByID := make(map[int64][]entities.Role, len(withRoles))
+	for _, user := range withRoles {
+		rolesByID[user.ID] = user.Roles
+	}
+
+	for _, user := range users {
+		user.Roles = rolesByID[user.ID]
+	}
+
+	return nil
 }
 
 func (r *UserRepository) FindByID(ctx context.Context, id int64) (*entities.User, error) {
-	return r.baseRepo.FindByID(ctx, id)
+	var user entities.User
+	if err := r.db.WithContext(ctx).Preload("Roles.Permissions").Where("id = ? AND deleted_at IS NULL", id).Take(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
 }
 
 func (r *UserRepository) Create(ctx context.Context, user *entities.User) (*entities.User, error) {
@@ -41,11 +163,109 @@ func (r *UserRepository) Delete(ctx context.Context, id int64) error {
 	return r.baseRepo.Delete(ctx, id)
 }
 
+// Restore returns ErrUserNotFound when no soft-deleted user has id.
+func (r *UserRepository) Restore(ctx context.Context, id int64) error {
+	err := r.baseRepo.Restore(ctx, id)
+	if err == gorm.ErrRecordNotFound {
+		return errors.ErrUserNotFound
+	}
+	return err
+}
+
+func (r *UserRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	var purged int64
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ids []int64
+		if err := tx.Model(&entities.User{}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).
+			Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+
+		if len(ids) == 0 {
+			return nil
+		}
+
+		// The foreign keys to users do not cascade, so dependent rows go first.
+		dependents := []any{
+			&entities.RefreshToken{},
+			&entities.RecoveryCode{},
+			&entities.OneTimeToken{},
+			&entities.PasswordHistory{},
+			&entities.UserIdentity{},
+			&entities.APIKey{},
+		}
+		for _, model := range dependents {
+			if err := tx.Where("user_id IN ?", ids).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Exec("DELETE FROM user_roles WHERE user_id IN ?", ids).Error; err != nil {
+			return err
+		}
+
+		result := tx.Where("id IN ?", ids).Delete(&entities.User{})
+		purged = result.RowsAffected
+		return result.Error
+	})
+
+	return purged, err
+}
+
 func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*entities.User, error) {
-	return r.baseRepo.FindFirst(ctx, "email = ?", email)
+	var user entities.User
+	if err := r.db.WithContext(ctx).Preload("Roles.Permissions").Where("email = ? AND deleted_at IS NULL", email).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
 }
 
 func (r *UserRepository) ExistsByEmail(ctx context.Context, email string) bool {
-	isExist, _ := r.baseRepo.WhereExisting(ctx, "email = ?", email)
+	isExist, _ := r.baseRepo.WithDeleted().WhereExisting(ctx, "email = ?", email)
 	return isExist
 }
+
+func (r *UserRepository) UpdateTwoFactor(ctx context.Context, user *entities.User) error {
+	return r.db.WithContext(ctx).Model(&entities.User{}).
+		Where("id = ?", user.ID).
+		Updates(map[string]any{
+			"two_factor_enabled":   user.TwoFactorEnabled,
+			"two_factor_secret":    user.TwoFactorSecret,
+			"two_factor_last_step": user.TwoFactorLastStep,
+		}).Error
+}
+
+func (r *UserRepository) UpdateTwoFactorStep(ctx context.Context, user *entities.User) error {
+	result := r.db.WithContext(ctx).Model(&entities.User{}).
+		Where("id = ? AND two_factor_last_step < ?", user.ID, user.TwoFactorLastStep).
+		Updates(map[string]any{
+			"two_factor_secret":    user.TwoFactorSecret,
+			"two_factor_last_step": user.TwoFactorLastStep,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.ErrInvalidTwoFactorCode
+	}
+
+	return nil
+}
+
+func (r *UserRepository) UpdateEmail(ctx context.Context, user *entities.User) error {
+	return r.db.WithContext(ctx).Model(&entities.User{}).
+		Where("id = ?", user.ID).
+		Updates(map[string]any{
+			"email":         user.Email,
+			"pending_email": user.PendingEmail,
+		}).Error
+}
+
+func (r *UserRepository) UpdatePassword(ctx context.Context, user *entities.User) error {
+	return r.db.WithContext(ctx).Model(&entities.User{}).
+		Where("id = ?", user.ID).
+		Update("password", user.Password).Error
+}
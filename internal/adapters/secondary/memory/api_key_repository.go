@@ -0,0 +1,92 @@
+package memory
+
+import (
+	"context"
+	"go-gin-clean/internal/core/domain/entities"
+	"go-gin-clean/internal/core/ports"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type APIKeyRepository struct {
+	db       *DB
+	baseRepo ports.BaseRepository[entities.APIKey]
+}
+
+func NewAPIKeyRepository(db *DB) ports.APIKeyRepository {
+	baseRepo := NewBaseRepository[entities.APIKey](db)
+	return &APIKeyRepository{
+		db:       db,
+		baseRepo: baseRepo,
+	}
+}
+
+func (r *APIKeyRepository) Create(ctx context.Context, key *entities.APIKey) error {
+	_, err := r.baseRepo.Create(ctx, key)
+	return err
+}
+
+func (r *APIKeyRepository) FindByHash(ctx context.Context, keyHash string) (*entities.APIKey, error) {
+	return r.baseRepo.FindFirst(ctx, func(row *entities.APIKey) bool {
+		return row.KeyHash == keyHash
+	})
+}
+
+// FindActiveByUserID returns the unrevoked, unexpired keys of the user,
+// newest first.
+func (r *APIKeyRepository) FindActiveByUserID(ctx context.Context, userID int64) ([]*entities.APIKey, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	keys := tableOf[entities.APIKey](r.db).find(func(row *entities.APIKey) bool {
+		return row.UserID == userID && row.IsValid()
+	})
+	slices.SortStableFunc(keys, func(a, b *entities.APIKey) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return keys, nil
+}
+
+func (r *APIKeyRepository) Revoke(ctx context.Context, userID, keyID int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	now := time.Now()
+	revoked := tableOf[entities.APIKey](r.db).modify(
+		func(row *entities.APIKey) bool {
+			return row.ID == keyID && row.UserID == userID && row.RevokedAt == nil
+		},
+		func(row *entities.APIKey) { row.RevokedAt = &now },
+	)
+	if revoked == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r *APIKeyRepository) RevokeAllByUserID(ctx context.Context, userID int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	now := time.Now()
+	tableOf[entities.APIKey](r.db).modify(
+		func(row *entities.APIKey) bool { return row.UserID == userID && row.RevokedAt == nil },
+		func(row *entities.APIKey) { row.RevokedAt = &now },
+	)
+	return nil
+}
+
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, keyID int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	now := time.Now()
+	tableOf[entities.APIKey](r.db).modify(
+		func(row *entities.APIKey) bool { return row.ID == keyID },
+		func(row *entities.APIKey) { row.LastUsedAt = &now },
+	)
+	return nil
+}
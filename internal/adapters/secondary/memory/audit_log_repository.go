@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"go-gin-clean/internal/core/contracts"
+	"go-gin-clean/internal/core/domain/entities"
+	"go-gin-clean/internal/core/ports"
+	"slices"
+)
+
+type AuditLogRepository struct {
+	db       *DB
+	baseRepo ports.BaseRepository[entities.AuditLog]
+}
+
+func NewAuditLogRepository(db *DB) ports.AuditLogRepository {
+	baseRepo := NewBaseRepository[entities.AuditLog](db)
+	return &AuditLogRepository{
+		db:       db,
+		baseRepo: baseRepo,
+	}
+}
+
+func (r *AuditLogRepository) Create(ctx context.Context, log *entities.AuditLog) error {
+	_, err := r.baseRepo.Create(ctx, log)
+	return err
+}
+
+// FindAll returns matching entries, newest first.
+func (r *AuditLogRepository) FindAll(ctx context.Context, limit, offset int, filter *contracts.AuditLogFilter) ([]*entities.AuditLog, int64, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	logs := tableOf[entities.AuditLog](r.db).find(func(row *entities.AuditLog) bool {
+		switch {
+		case filter.ActorID != nil && (row.ActorID == nil || *row.ActorID != *filter.ActorID):
+			return false
+		case filter.TargetID != nil && (row.TargetID == nil || *row.TargetID != *filter.TargetID):
+			return false
+		case filter.Action != "" && row.Action != filter.Action:
+			return false
+		case filter.From != nil && row.CreatedAt.Before(*filter.From):
+			return false
+		case filter.To != nil && !row.CreatedAt.Before(*filter.To):
+			return false
+		}
+		return true
+	})
+
+	slices.SortFunc(logs, func(a, b *entities.AuditLog) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+	})
+
+	return paginate(logs, limit, offset), int64(len(logs)), nil
+}
@@ -0,0 +1,343 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"go-gin-clean/internal/core/contracts"
+	"go-gin-clean/internal/core/ports"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// softDeletable is implemented by entities embedding entities.Audit. Their
+// rows are soft-deleted by Delete and hidden from reads.
+type softDeletable interface {
+	MarkAsDeleted()
+	RestoreFromDeletion()
+}
+
+// BaseRepository keeps the rows of T in a DB. Where the database repository
+// takes a SQL condition, it takes a func(*T) bool predicate, or nil for
+// every row, and ignores the arguments. Errors match the database
+// repository, gorm.ErrRecordNotFound included.
+type BaseRepository[T any] struct {
+	db          *DB
+	withDeleted bool
+}
+
+func NewBaseRepository[T any](db *DB) ports.BaseRepository[T] {
+	return &BaseRepository[T]{db: db}
+}
+
+// WithDeleted returns a view of the repository whose reads include
+// soft-deleted rows.
+func (r *BaseRepository[T]) WithDeleted() ports.BaseRepository[T] {
+	return &BaseRepository[T]{db: r.db, withDeleted: true}
+}
+
+func (r *BaseRepository[T]) softDeletes() bool {
+	_, ok := any(new(T)).(softDeletable)
+	return ok
+}
+
+// scoped returns the predicate for query that also skips soft-deleted rows,
+// unless the repository was obtained through WithDeleted.
+func (r *BaseRepository[T]) scoped(t *table[T], query any) (func(*T) bool, error) {
+	var keep func(*T) bool
+	switch query := query.(type) {
+	case nil:
+	case func(*T) bool:
+		keep = query
+	default:
+		return nil, fmt.Errorf("unsupported query %T, expected func(*%s) bool", query, t.schema.Name)
+	}
+
+	hideDeleted := r.softDeletes() && !r.withDeleted
+	return func(row *T) bool {
+		if hideDeleted && t.value(row, "deleted_at") != nil {
+			return false
+		}
+		return keep == nil || keep(row)
+	}, nil
+}
+
+// filter returns the rows matching query and the filters of spec.
+func (r *BaseRepository[T]) filter(t *table[T], fields contracts.QueryFields, spec *contracts.QuerySpec, query any) ([]*T, error) {
+	keep, err := r.scoped(t, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []*T
+	for _, row := range t.find(keep) {
+		matched, err := t.matchesFilters(row, fields, spec)
+		if err != nil {
+			return nil, err
+		}
+		if matched {
+			rows = append(rows, row)
+		}
+	}
+
+	return rows, nil
+}
+
+func (r *BaseRepository[T]) FindAll(ctx context.Context, limit, offset int, query any, args ...any) ([]*T, int64, error) {
+	return r.FindAllByQuery(ctx, limit, offset, nil, nil, query, args...)
+}
+
+func (r *BaseRepository[T]) FindAllByQuery(ctx context.Context, limit, offset int, fields contracts.QueryFields, spec *contracts.QuerySpec, query any, args ...any) ([]*T, int64, error) {
+	if err := fields.Validate(spec); err != nil {
+		return nil, 0, err
+	}
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	t := tableOf[T](r.db)
+	rows, err := r.filter(t, fields, spec, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	keys := sortKeys(fields, spec)
+	slices.SortStableFunc(rows, func(a, b *T) int {
+		return t.compareRows(a, b, keys)
+	})
+
+	return paginate(rows, limit, offset), int64(len(rows)), nil
+}
+
+// paginate applies LIMIT and OFFSET. A negative limit means no limit.
+func paginate[T any](rows []*T, limit, offset int) []*T {
+	rows = rows[min(max(offset, 0), len(rows)):]
+	if limit >= 0 && limit < len(rows) {
+		rows = rows[:limit]
+	}
+	return rows
+}
+
+// FindAllByCursor reads one keyset page: up to page.Limit rows after or
+// before page.Cursor in the spec ordering.
+func (r *BaseRepository[T]) FindAllByCursor(ctx context.Context, fields contracts.QueryFields, spec *contracts.QuerySpec, page *contracts.CursorRequest, query any, args ...any) ([]*T, *contracts.CursorPage, error) {
+	result := &contracts.CursorPage{}
+
+	if err := fields.ValidateKeyset(spec); err != nil {
+		return nil, nil, err
+	}
+
+	keys := sortKeys(fields, spec)
+
+	var values []any
+	var backward bool
+	if page.Cursor != "" {
+		var err error
+		if values, backward, err = decodeCursor(page.Cursor, keys); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	t := tableOf[T](r.db)
+	rows, err := r.filter(t, fields, spec, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if page.IncludeTotal {
+		total := int64(len(rows))
+		result.Total = &total
+	}
+
+	if values != nil {
+		rows = slices.DeleteFunc(rows, func(row *T) bool {
+			order := t.compareTo(row, values, keys)
+			return backward && order >= 0 || !backward && order <= 0
+		})
+	}
+
+	// Reading backward, the rows closest to the cursor come first.
+	slices.SortStableFunc(rows, func(a, b *T) int {
+		if backward {
+			return t.compareRows(b, a, keys)
+		}
+		return t.compareRows(a, b, keys)
+	})
+
+	hasMore := len(rows) > page.Limit
+	rows = paginate(rows, page.Limit, 0)
+
+	if backward {
+		slices.Reverse(rows)
+	}
+
+	if len(rows) == 0 {
+		return rows, result, nil
+	}
+
+	// A backward page was reached from the page after it, a forward page
+	// from the one before it unless it is the first.
+	hasNext, hasPrev := hasMore, page.Cursor != ""
+	if backward {
+		hasNext, hasPrev = true, hasMore
+	}
+
+	if hasNext {
+		if result.NextCursor, err = t.encodeCursor(rows[len(rows)-1], keys, false); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if hasPrev {
+		if result.PrevCursor, err = t.encodeCursor(rows[0], keys, true); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return rows, result, nil
+}
+
+func (r *BaseRepository[T]) FindByID(ctx context.Context, id int64) (*T, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	t := tableOf[T](r.db)
+	return r.first(t, func(row *T) bool { return t.id(row) == id })
+}
+
+func (r *BaseRepository[T]) FindFirst(ctx context.Context, query any, args ...any) (*T, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	return r.first(tableOf[T](r.db), query)
+}
+
+// first returns the matching row with the lowest primary key.
+func (r *BaseRepository[T]) first(t *table[T], query any) (*T, error) {
+	keep, err := r.scoped(t, query)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := t.find(keep)
+	if len(rows) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return rows[0], nil
+}
+
+func (r *BaseRepository[T]) Where(ctx context.Context, query any, args ...any) ([]*T, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	t := tableOf[T](r.db)
+	keep, err := r.scoped(t, query)
+	if err != nil {
+		return nil, err
+	}
+	return t.find(keep), nil
+}
+
+func (r *BaseRepository[T]) WhereExisting(ctx context.Context, query any, args ...any) (bool, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	_, err := r.first(tableOf[T](r.db), query)
+	if err == gorm.ErrRecordNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Create stores entity without its associations and assigns its id.
+func (r *BaseRepository[T]) Create(ctx context.Context, entity *T) (*T, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	tableOf[T](r.db).insert(entity)
+	return entity, nil
+}
+
+// Update writes the non-zero fields of entity, like the database
+// repository, and reloads it.
+func (r *BaseRepository[T]) Update(ctx context.Context, entity *T) (*T, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if !tableOf[T](r.db).update(entity) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return entity, nil
+}
+
+// Delete soft-deletes entities embedding entities.Audit and removes any
+// other entity.
+func (r *BaseRepository[T]) Delete(ctx context.Context, id int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	t := tableOf[T](r.db)
+	if !r.softDeletes() {
+		t.remove(func(row *T) bool { return t.id(row) == id })
+		return nil
+	}
+
+	deleted := t.modify(
+		func(row *T) bool { return t.id(row) == id && t.value(row, "deleted_at") == nil },
+		func(row *T) { any(row).(softDeletable).MarkAsDeleted() },
+	)
+	if deleted == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r *BaseRepository[T]) Restore(ctx context.Context, id int64) error {
+	if !r.softDeletes() {
+		return gorm.ErrRecordNotFound
+	}
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	t := tableOf[T](r.db)
+	restored := t.modify(
+		func(row *T) bool { return t.id(row) == id && t.value(row, "deleted_at") != nil },
+		func(row *T) { any(row).(softDeletable).RestoreFromDeletion() },
+	)
+	if restored == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Purge permanently removes rows soft-deleted before the given time and
+// returns how many were removed.
+func (r *BaseRepository[T]) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	if !r.softDeletes() {
+		return 0, nil
+	}
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	t := tableOf[T](r.db)
+	return int64(len(t.remove(softDeletedBefore(t, deletedBefore)))), nil
+}
+
+// softDeletedBefore matches rows soft-deleted before the given time.
+func softDeletedBefore[T any](t *table[T], before time.Time) func(*T) bool {
+	return func(row *T) bool {
+		deletedAt, ok := t.value(row, "deleted_at").(time.Time)
+		return ok && deletedAt.Before(before)
+	}
+}
@@ -0,0 +1,366 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"go-gin-clean/internal/core/domain/entities"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm/schema"
+)
+
+// schemas caches the parsed gorm schemas of the stored entities. The
+// repositories read and write columns through them, so column names mean
+// the same here as in the database repositories.
+var schemas sync.Map
+
+// DB is the in-memory database the repositories of this package share, the
+// way the database repositories share a *gorm.DB. It is safe for concurrent
+// use. Rows are stored as copies, so changes to a returned entity only
+// persist through a repository call.
+type DB struct {
+	mu              sync.Mutex
+	tables          map[reflect.Type]rowSet
+	userRoles       map[int64][]int64
+	rolePermissions map[int64][]entities.Permission
+
+	// tx serializes units of work, see UnitOfWork.
+	tx sync.Mutex
+}
+
+func NewDB() *DB {
+	return &DB{
+		tables:          make(map[reflect.Type]rowSet),
+		userRoles:       make(map[int64][]int64),
+		rolePermissions: make(map[int64][]entities.Permission),
+	}
+}
+
+// snapshot copies every table and join table. The caller must hold db.mu.
+func (db *DB) snapshot() *DB {
+	saved := NewDB()
+	for key, rows := range db.tables {
+		saved.tables[key] = rows.clone()
+	}
+	for userID, roleIDs := range db.userRoles {
+		saved.userRoles[userID] = slices.Clone(roleIDs)
+	}
+	for roleID, permissions := range db.rolePermissions {
+		saved.rolePermissions[roleID] = slices.Clone(permissions)
+	}
+	return saved
+}
+
+// commit writes to db the rows and join table entries tx changed since base,
+// the snapshot tx was copied from. Rows changed only in db are kept. The
+// caller must hold db.mu.
+func (db *DB) commit(base, tx *DB) {
+	for key, rows := range tx.tables {
+		rows.commit(db, base.tables[key])
+	}
+	commitEntries(db.userRoles, base.userRoles, tx.userRoles)
+	commitEntries(db.rolePermissions, base.rolePermissions, tx.rolePermissions)
+}
+
+func commitEntries[V any](target, base, changed map[int64]V) {
+	for key, value := range changed {
+		if old, ok := base[key]; !ok || !reflect.DeepEqual(old, value) {
+			target[key] = value
+		}
+	}
+	for key := range base {
+		if _, ok := changed[key]; !ok {
+			delete(target, key)
+		}
+	}
+}
+
+type rowSet interface {
+	clone() rowSet
+	commit(db *DB, base rowSet)
+}
+
+// table holds the rows of one entity type keyed by primary key. Its
+// methods must be called with db.mu held.
+type table[T any] struct {
+	schema *schema.Schema
+	rows   map[int64]T
+
+	// lastID is shared with the clones of the table so a unit of work and
+	// the writes outside it never assign the same key. Like a database
+	// sequence it is not rolled back.
+	lastID *atomic.Int64
+}
+
+func (t *table[T]) clone() rowSet {
+	return &table[T]{schema: t.schema, rows: maps.Clone(t.rows), lastID: t.lastID}
+}
+
+// commit writes to db the rows t changed since base, which is nil when the
+// table was first used in the unit of work.
+func (t *table[T]) commit(db *DB, base rowSet) {
+	target := tableOf[T](db)
+	target.reserve(t.lastID.Load())
+
+	var before map[int64]T
+	if base != nil {
+		before = base.(*table[T]).rows
+	}
+	commitEntries(target.rows, before, t.rows)
+}
+
+// tableOf returns the table of T, creating it on first use. The caller must
+// hold db.mu.
+func tableOf[T any](db *DB) *table[T] {
+	key := reflect.TypeOf((*T)(nil)).Elem()
+	if rows, ok := db.tables[key]; ok {
+		return rows.(*table[T])
+	}
+
+	parsed, err := schema.Parse(new(T), &schemas, schema.NamingStrategy{})
+	if err != nil {
+		panic(err)
+	}
+
+	t := &table[T]{schema: parsed, rows: make(map[int64]T), lastID: new(atomic.Int64)}
+	db.tables[key] = t
+	return t
+}
+
+func (t *table[T]) id(entity *T) int64 {
+	value, _ := t.schema.PrioritizedPrimaryField.ValueOf(context.Background(), reflect.ValueOf(entity))
+	return value.(int64)
+}
+
+// value returns the column of entity as a string, int64, bool, time.Time or
+// nil for NULL, so values of different Go types compare alike. Zero values
+// of columns defaulting to NULL are NULL, as gorm leaves them out on insert.
+func (t *table[T]) value(entity *T, column string) any {
+	field := t.schema.LookUpField(column)
+	if field == nil {
+		return nil
+	}
+
+	value, zero := field.ValueOf(context.Background(), reflect.ValueOf(entity))
+	if zero && strings.EqualFold(field.DefaultValue, "null") {
+		return nil
+	}
+
+	return normalize(value)
+}
+
+func normalize(value any) any {
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String()
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(rv.Uint())
+	}
+
+	if value, ok := rv.Interface().(time.Time); ok {
+		return value
+	}
+
+	return rv.Interface()
+}
+
+// compare orders two normalized values, NULL after everything else as
+// Postgres does. Values of different types compare as equal.
+func compare(a, b any) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+
+	switch a := a.(type) {
+	case string:
+		if b, ok := b.(string); ok {
+			return strings.Compare(a, b)
+		}
+	case int64:
+		if b, ok := b.(int64); ok {
+			return cmp.Compare(a, b)
+		}
+	case bool:
+		if b, ok := b.(bool); ok && a != b {
+			if a {
+				return 1
+			}
+			return -1
+		}
+	case time.Time:
+		if b, ok := b.(time.Time); ok {
+			return a.Compare(b)
+		}
+	}
+
+	return 0
+}
+
+// find returns copies of the rows keep accepts, ordered by primary key.
+func (t *table[T]) find(keep func(*T) bool) []*T {
+	ids := slices.Sorted(maps.Keys(t.rows))
+
+	var found []*T
+	for _, id := range ids {
+		row := t.rows[id]
+		if keep == nil || keep(&row) {
+			found = append(found, &row)
+		}
+	}
+	return found
+}
+
+func (t *table[T]) get(id int64) (*T, bool) {
+	row, ok := t.rows[id]
+	if !ok {
+		return nil, false
+	}
+	return &row, true
+}
+
+// insert stores a copy of entity without its associations, assigning the
+// next primary key and filling timestamps the database would default.
+func (t *table[T]) insert(entity *T) {
+	ctx := context.Background()
+	rv := reflect.ValueOf(entity)
+
+	id := t.id(entity)
+	if id == 0 {
+		id = t.lastID.Add(1)
+		t.schema.PrioritizedPrimaryField.Set(ctx, rv, id)
+	} else {
+		t.reserve(id)
+	}
+
+	now := time.Now()
+	for _, field := range t.schema.Fields {
+		defaultsToNow := field.AutoCreateTime > 0 || field.AutoUpdateTime > 0 ||
+			strings.EqualFold(field.DefaultValue, "CURRENT_TIMESTAMP")
+		if _, zero := field.ValueOf(ctx, rv); zero && defaultsToNow {
+			field.Set(ctx, rv, now)
+		}
+	}
+
+	t.rows[id] = t.withoutAssociations(entity)
+}
+
+// reserve moves the sequence past id so it is not assigned again.
+func (t *table[T]) reserve(id int64) {
+	for {
+		last := t.lastID.Load()
+		if id <= last || t.lastID.CompareAndSwap(last, id) {
+			return
+		}
+	}
+}
+
+// update writes the non-zero columns of entity over its row, as gorm's
+// Updates does with a struct, and refreshes entity from the row.
+func (t *table[T]) update(entity *T) bool {
+	ctx := context.Background()
+
+	row, ok := t.get(t.id(entity))
+	if !ok {
+		return false
+	}
+
+	source, target := reflect.ValueOf(entity), reflect.ValueOf(row)
+	for _, field := range t.schema.Fields {
+		if field.DBName == "" || field.PrimaryKey {
+			continue
+		}
+		if value, zero := field.ValueOf(ctx, source); !zero {
+			field.Set(ctx, target, value)
+		}
+	}
+	t.touch(row)
+	t.rows[t.id(row)] = *row
+
+	t.refresh(entity)
+	return true
+}
+
+// modify applies fn to the rows keep accepts and returns how many it
+// changed, as an UPDATE ... WHERE would.
+func (t *table[T]) modify(keep func(*T) bool, fn func(*T)) int {
+	changed := 0
+	for _, row := range t.find(keep) {
+		fn(row)
+		t.touch(row)
+		t.rows[t.id(row)] = *row
+		changed++
+	}
+	return changed
+}
+
+// remove deletes the rows keep accepts and returns their primary keys.
+func (t *table[T]) remove(keep func(*T) bool) []int64 {
+	var ids []int64
+	for _, row := range t.find(keep) {
+		id := t.id(row)
+		delete(t.rows, id)
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// refresh overwrites the columns of entity with its stored row and keeps
+// its associations, like reloading it with First.
+func (t *table[T]) refresh(entity *T) {
+	row, ok := t.get(t.id(entity))
+	if !ok {
+		return
+	}
+
+	ctx := context.Background()
+	source, target := reflect.ValueOf(row), reflect.ValueOf(entity)
+	for _, field := range t.schema.Fields {
+		if field.DBName != "" {
+			field.ReflectValueOf(ctx, target).Set(field.ReflectValueOf(ctx, source))
+		}
+	}
+}
+
+// touch sets the auto-update timestamps of row to now.
+func (t *table[T]) touch(row *T) {
+	ctx := context.Background()
+	now := time.Now()
+	for _, field := range t.schema.Fields {
+		if field.AutoUpdateTime > 0 {
+			field.Set(ctx, reflect.ValueOf(row), now)
+		}
+	}
+}
+
+func (t *table[T]) withoutAssociations(entity *T) T {
+	row := *entity
+	ctx := context.Background()
+	for _, relationship := range t.schema.Relationships.Relations {
+		field := relationship.Field.ReflectValueOf(ctx, reflect.ValueOf(&row))
+		field.Set(reflect.Zero(field.Type()))
+	}
+	return row
+}
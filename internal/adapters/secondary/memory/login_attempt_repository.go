@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"context"
+	"go-gin-clean/internal/core/domain/entities"
+	"go-gin-clean/internal/core/ports"
+	"sync"
+	"time"
+)
+
+// LoginAttemptRepository keeps attempt counters in process memory. Counters
+// are lost on restart and are not shared between instances. Keys come from
+// clients, so counters are dropped retention after their last failure once
+// they are unlocked.
+type LoginAttemptRepository struct {
+	mu        sync.Mutex
+	attempts  map[string]entities.LoginAttempt
+	retention time.Duration
+	lastSweep time.Time
+}
+
+func NewLoginAttemptRepository(retention time.Duration) ports.LoginAttemptRepository {
+	return &LoginAttemptRepository{
+		attempts:  make(map[string]entities.LoginAttempt),
+		retention: retention,
+	}
+}
+
+func (r *LoginAttemptRepository) FindByKey(ctx context.Context, key string) (*entities.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempt, ok := r.attempts[key]
+	if !ok {
+		return entities.NewLoginAttempt(key), nil
+	}
+	return &attempt, nil
+}
+
+func (r *LoginAttemptRepository) Update(ctx context.Context, key string, fn func(attempt *entities.LoginAttempt)) (*entities.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.lastSweep) >= sweepInterval {
+		r.deleteStale(time.Now().Add(-r.retention))
+		r.lastSweep = time.Now()
+	}
+
+	attempt, ok := r.attempts[key]
+	if !ok {
+		attempt = *entities.NewLoginAttempt(key)
+	}
+
+	fn(&attempt)
+	r.attempts[key] = attempt
+	return &attempt, nil
+}
+
+func (r *LoginAttemptRepository) Delete(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.attempts, key)
+	return nil
+}
+
+func (r *LoginAttemptRepository) DeleteStale(ctx context.Context, lastFailureBefore time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.deleteStale(lastFailureBefore)
+	return nil
+}
+
+func (r *LoginAttemptRepository) deleteStale(lastFailureBefore time.Time) {
+	for key, attempt := range r.attempts {
+		if attempt.IsStale(lastFailureBefore) {
+			delete(r.attempts, key)
+		}
+	}
+}
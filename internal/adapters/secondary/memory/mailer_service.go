@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// SentEmail is an email passed to MailerService.SendEmail.
+type SentEmail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// RenderedTemplate is a call to MailerService.LoadTemplate.
+type RenderedTemplate struct {
+	Name string
+	Data any
+}
+
+// MailerService records the emails it is asked to send instead of sending
+// them. LoadTemplate formats the template name and data with fmt rather
+// than reading the template files, so links passed in the data can be read
+// back from the body.
+type MailerService struct {
+	mu        sync.Mutex
+	emails    []SentEmail
+	templates []RenderedTemplate
+}
+
+func NewMailerService() *MailerService {
+	return &MailerService{}
+}
+
+func (s *MailerService) SendEmail(to, subject, body string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.emails = append(s.emails, SentEmail{To: to, Subject: subject, Body: body})
+	return nil
+}
+
+func (s *MailerService) LoadTemplate(templateName string, data any) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.templates = append(s.templates, RenderedTemplate{Name: templateName, Data: data})
+	return fmt.Sprintf("%s %v", templateName, data), nil
+}
+
+// Emails returns the emails sent so far, oldest first.
+func (s *MailerService) Emails() []SentEmail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.emails)
+}
+
+// Templates returns the templates loaded so far, oldest first.
+func (s *MailerService) Templates() []RenderedTemplate {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.templates)
+}
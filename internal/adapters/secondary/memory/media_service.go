@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"go-gin-clean/internal/core/domain/errors"
+	"io"
+	"path"
+	"slices"
+	"sync"
+)
+
+// UploadedFile is a file passed to MediaService.UploadFile.
+type UploadedFile struct {
+	Filename string
+	Size     int64
+	Path     string
+	URL      string
+	Content  []byte
+}
+
+// MediaService keeps uploaded files in memory and records the uploads and
+// deletions it is asked for. URLs are built like the local storage ones.
+type MediaService struct {
+	mu      sync.Mutex
+	files   map[string][]byte
+	uploads []UploadedFile
+	deleted []string
+}
+
+func NewMediaService() *MediaService {
+	return &MediaService{
+		files: make(map[string][]byte),
+	}
+}
+
+func (s *MediaService) UploadFile(filename string, size int64, content io.Reader, filePath string) (*string, error) {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return nil, errors.ErrUploadFile
+	}
+
+	publicURL := path.Join("/assets", filePath, filename)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.files[publicURL] = data
+	s.uploads = append(s.uploads, UploadedFile{
+		Filename: filename,
+		Size:     size,
+		Path:     filePath,
+		URL:      publicURL,
+		Content:  data,
+	})
+
+	return &publicURL, nil
+}
+
+// DeleteFile removes a file by the URL UploadFile returned for it.
+func (s *MediaService) DeleteFile(filePath string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deleted = append(s.deleted, filePath)
+
+	if _, ok := s.files[filePath]; !ok {
+		return errors.ErrDeleteFile
+	}
+	delete(s.files, filePath)
+
+	return nil
+}
+
+// Uploads returns the files uploaded so far, oldest first.
+func (s *MediaService) Uploads() []UploadedFile {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.uploads)
+}
+
+// Deleted returns the paths passed to DeleteFile so far, oldest first.
+func (s *MediaService) Deleted() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.deleted)
+}
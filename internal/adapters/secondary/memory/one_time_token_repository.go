@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"context"
+	"go-gin-clean/internal/core/domain/entities"
+	"go-gin-clean/internal/core/domain/enums"
+	"go-gin-clean/internal/core/ports"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type OneTimeTokenRepository struct {
+	db       *DB
+	baseRepo *BaseRepository[entities.OneTimeToken]
+}
+
+func NewOneTimeTokenRepository(db *DB) ports.OneTimeTokenRepository {
+	return &OneTimeTokenRepository{
+		db:       db,
+		baseRepo: &BaseRepository[entities.OneTimeToken]{db: db},
+	}
+}
+
+func (r *OneTimeTokenRepository) Issue(ctx context.Context, token *entities.OneTimeToken) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	t := tableOf[entities.OneTimeToken](r.db)
+	t.remove(func(row *entities.OneTimeToken) bool {
+		return row.UserID == token.UserID && row.Purpose == token.Purpose && row.ConsumedAt == nil
+	})
+	t.insert(token)
+	return nil
+}
+
+func (r *OneTimeTokenRepository) FindUnconsumed(ctx context.Context, tokenHash string, purpose enums.TokenPurpose) (*entities.OneTimeToken, error) {
+	return r.baseRepo.FindFirst(ctx, func(row *entities.OneTimeToken) bool {
+		return row.TokenHash == tokenHash && row.Purpose == purpose && row.ConsumedAt == nil
+	})
+}
+
+func (r *OneTimeTokenRepository) Consume(ctx context.Context, tokenHash string, purpose enums.TokenPurpose) (*entities.OneTimeToken, error) {
+	now := time.Now()
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	t := tableOf[entities.OneTimeToken](r.db)
+	consumed := t.modify(
+		func(row *entities.OneTimeToken) bool {
+			return row.TokenHash == tokenHash && row.Purpose == purpose && row.ConsumedAt == nil
+		},
+		func(row *entities.OneTimeToken) { row.ConsumedAt = &now },
+	)
+	if consumed == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return r.baseRepo.first(t, func(row *entities.OneTimeToken) bool {
+		return row.TokenHash == tokenHash
+	})
+}
+
+func (r *OneTimeTokenRepository) InvalidateByUserID(ctx context.Context, userID int64, purpose enums.TokenPurpose) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	tableOf[entities.OneTimeToken](r.db).remove(func(row *entities.OneTimeToken) bool {
+		return row.UserID == userID && row.Purpose == purpose && row.ConsumedAt == nil
+	})
+	return nil
+}
+
+func (r *OneTimeTokenRepository) DeleteExpired(ctx context.Context) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	now := time.Now()
+	tableOf[entities.OneTimeToken](r.db).remove(func(row *entities.OneTimeToken) bool {
+		return row.ExpiresAt.Before(now)
+	})
+	return nil
+}
@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"go-gin-clean/internal/core/domain/entities"
+	"go-gin-clean/internal/core/ports"
+	"slices"
+)
+
+type PasswordHistoryRepository struct {
+	db *DB
+}
+
+func NewPasswordHistoryRepository(db *DB) ports.PasswordHistoryRepository {
+	return &PasswordHistoryRepository{
+		db: db,
+	}
+}
+
+func (r *PasswordHistoryRepository) Create(ctx context.Context, history *entities.PasswordHistory) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	tableOf[entities.PasswordHistory](r.db).insert(history)
+	return nil
+}
+
+func (r *PasswordHistoryRepository) FindRecentByUserID(ctx context.Context, userID int64, limit int) ([]*entities.PasswordHistory, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	return r.recent(userID, limit), nil
+}
+
+func (r *PasswordHistoryRepository) Prune(ctx context.Context, userID int64, keep int) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	kept := r.recent(userID, keep)
+	tableOf[entities.PasswordHistory](r.db).remove(func(row *entities.PasswordHistory) bool {
+		return row.UserID == userID && !slices.ContainsFunc(kept, func(history *entities.PasswordHistory) bool {
+			return history.ID == row.ID
+		})
+	})
+	return nil
+}
+
+// recent returns the newest entries of the user. The caller must hold
+// db.mu.
+func (r *PasswordHistoryRepository) recent(userID int64, limit int) []*entities.PasswordHistory {
+	histories := tableOf[entities.PasswordHistory](r.db).find(func(row *entities.PasswordHistory) bool {
+		return row.UserID == userID
+	})
+	slices.SortFunc(histories, func(a, b *entities.PasswordHistory) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+	})
+	return paginate(histories, limit, 0)
+}
@@ -0,0 +1,199 @@
+package memory
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"go-gin-clean/internal/core/contracts"
+	"go-gin-clean/internal/core/domain/errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+// sortKey is one column of the ordering a spec asks for.
+type sortKey struct {
+	column    string
+	fieldType contracts.FieldType
+	desc      bool
+}
+
+// sortKeys mirrors the database ordering: the requested fields followed by
+// id unless id was already one of them. The spec must have been validated
+// against fields.
+func sortKeys(fields contracts.QueryFields, spec *contracts.QuerySpec) []sortKey {
+	var keys []sortKey
+	sortedByID := false
+	if spec != nil {
+		for _, sort := range spec.Sort {
+			field := fields[sort.Field]
+			keys = append(keys, sortKey{column: field.Column, fieldType: field.Type, desc: sort.Desc})
+			sortedByID = sortedByID || field.Column == "id"
+		}
+	}
+
+	if !sortedByID {
+		keys = append(keys, sortKey{column: "id", fieldType: contracts.FieldInt})
+	}
+
+	return keys
+}
+
+// matchesFilters reports whether row passes every filter of spec. As in SQL,
+// a NULL column matches no operator.
+func (t *table[T]) matchesFilters(row *T, fields contracts.QueryFields, spec *contracts.QuerySpec) (bool, error) {
+	if spec == nil {
+		return true, nil
+	}
+
+	for _, filter := range spec.Filters {
+		value, err := fields.FilterValue(filter)
+		if err != nil {
+			return false, err
+		}
+
+		column := t.value(row, fields[filter.Field].Column)
+		if column == nil {
+			return false, nil
+		}
+
+		var matched bool
+		switch filter.Operator {
+		case contracts.FilterEq:
+			matched = compare(column, value) == 0
+		case contracts.FilterNe:
+			matched = compare(column, value) != 0
+		case contracts.FilterGt:
+			matched = compare(column, value) > 0
+		case contracts.FilterGte:
+			matched = compare(column, value) >= 0
+		case contracts.FilterLt:
+			matched = compare(column, value) < 0
+		case contracts.FilterLte:
+			matched = compare(column, value) <= 0
+		case contracts.FilterLike:
+			text, _ := column.(string)
+			matched = strings.Contains(strings.ToLower(text), strings.ToLower(value.(string)))
+		case contracts.FilterIn:
+			matched = slices.ContainsFunc(value.([]any), func(v any) bool { return compare(column, v) == 0 })
+		}
+
+		if !matched {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// compareRows orders a and b by keys.
+func (t *table[T]) compareRows(a, b *T, keys []sortKey) int {
+	values := make([]any, len(keys))
+	for i, key := range keys {
+		values[i] = t.value(b, key.column)
+	}
+	return t.compareTo(a, values, keys)
+}
+
+// compareTo orders row against the key values of another row.
+func (t *table[T]) compareTo(row *T, values []any, keys []sortKey) int {
+	for i, key := range keys {
+		order := compare(t.value(row, key.column), values[i])
+		if key.desc {
+			order = -order
+		}
+		if order != 0 {
+			return order
+		}
+	}
+	return 0
+}
+
+// cursorToken is the decoded form of an opaque cursor: the key values of
+// the row it points at and the direction to read in. Sort records the
+// ordering it was issued for, so it cannot be replayed against another.
+type cursorToken struct {
+	Sort     string            `json:"s"`
+	Backward bool              `json:"b,omitempty"`
+	Values   []json.RawMessage `json:"v"`
+}
+
+func sortSignature(keys []sortKey) string {
+	columns := make([]string, len(keys))
+	for i, key := range keys {
+		columns[i] = key.column
+		if key.desc {
+			columns[i] = "-" + key.column
+		}
+	}
+	return strings.Join(columns, ",")
+}
+
+func (t *table[T]) encodeCursor(row *T, keys []sortKey, backward bool) (string, error) {
+	token := cursorToken{Sort: sortSignature(keys), Backward: backward}
+	for _, key := range keys {
+		raw, err := json.Marshal(t.value(row, key.column))
+		if err != nil {
+			return "", err
+		}
+		token.Values = append(token.Values, raw)
+	}
+
+	payload, err := json.Marshal(token)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(payload), nil
+}
+
+// decodeCursor returns the key values and direction of a cursor, with the
+// same errors as the database repositories.
+func decodeCursor(cursor string, keys []sortKey) ([]any, bool, error) {
+	invalid := errors.NewQueryError("cursor", "is invalid or expired")
+
+	payload, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, false, invalid
+	}
+
+	var token cursorToken
+	if err := json.Unmarshal(payload, &token); err != nil {
+		return nil, false, invalid
+	}
+
+	if token.Sort != sortSignature(keys) {
+		return nil, false, errors.NewQueryError("cursor", "was issued for a different sort order")
+	}
+
+	if len(token.Values) != len(keys) {
+		return nil, false, invalid
+	}
+
+	values := make([]any, len(keys))
+	for i, key := range keys {
+		var err error
+		switch key.fieldType {
+		case contracts.FieldTime:
+			var value time.Time
+			err = json.Unmarshal(token.Values[i], &value)
+			values[i] = value
+		case contracts.FieldInt:
+			var value int64
+			err = json.Unmarshal(token.Values[i], &value)
+			values[i] = value
+		case contracts.FieldBool:
+			var value bool
+			err = json.Unmarshal(token.Values[i], &value)
+			values[i] = value
+		default:
+			var value string
+			err = json.Unmarshal(token.Values[i], &value)
+			values[i] = value
+		}
+		if err != nil {
+			return nil, false, invalid
+		}
+	}
+
+	return values, token.Backward, nil
+}
@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"context"
+	"go-gin-clean/internal/core/domain/entities"
+	"go-gin-clean/internal/core/ports"
+
+	"gorm.io/gorm"
+)
+
+type RecoveryCodeRepository struct {
+	db       *DB
+	baseRepo ports.BaseRepository[entities.RecoveryCode]
+}
+
+func NewRecoveryCodeRepository(db *DB) ports.RecoveryCodeRepository {
+	baseRepo := NewBaseRepository[entities.RecoveryCode](db)
+	return &RecoveryCodeRepository{
+		db:       db,
+		baseRepo: baseRepo,
+	}
+}
+
+func (r *RecoveryCodeRepository) ReplaceAll(ctx context.Context, userID int64, codes []*entities.RecoveryCode) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	t := tableOf[entities.RecoveryCode](r.db)
+	t.remove(func(row *entities.RecoveryCode) bool { return row.UserID == userID })
+	for _, code := range codes {
+		t.insert(code)
+	}
+	return nil
+}
+
+func (r *RecoveryCodeRepository) FindUnusedByUserID(ctx context.Context, userID int64) ([]*entities.RecoveryCode, error) {
+	return r.baseRepo.Where(ctx, func(row *entities.RecoveryCode) bool {
+		return row.UserID == userID && row.UsedAt == nil
+	})
+}
+
+func (r *RecoveryCodeRepository) MarkAsUsed(ctx context.Context, code *entities.RecoveryCode) error {
+	code.MarkAsUsed()
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	used := tableOf[entities.RecoveryCode](r.db).modify(
+		func(row *entities.RecoveryCode) bool { return row.ID == code.ID && row.UsedAt == nil },
+		func(row *entities.RecoveryCode) { row.UsedAt = code.UsedAt },
+	)
+	if used == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r *RecoveryCodeRepository) DeleteByUserID(ctx context.Context, userID int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	tableOf[entities.RecoveryCode](r.db).remove(func(row *entities.RecoveryCode) bool {
+		return row.UserID == userID
+	})
+	return nil
+}
@@ -0,0 +1,125 @@
+package memory
+
+import (
+	"context"
+	"go-gin-clean/internal/core/domain/entities"
+	"go-gin-clean/internal/core/domain/errors"
+	"go-gin-clean/internal/core/ports"
+	"time"
+)
+
+type RefreshTokenRepository struct {
+	db       *DB
+	baseRepo ports.BaseRepository[entities.RefreshToken]
+}
+
+func NewRefreshTokenRepository(db *DB) ports.RefreshTokenRepository {
+	baseRepo := NewBaseRepository[entities.RefreshToken](db)
+	return &RefreshTokenRepository{
+		db:       db,
+		baseRepo: baseRepo,
+	}
+}
+
+func (r *RefreshTokenRepository) Save(ctx context.Context, token *entities.RefreshToken) error {
+	_, err := r.baseRepo.Create(ctx, token)
+	return err
+}
+
+// FindByToken returns the token regardless of its state so callers can tell a
+// replayed (rotated) token apart from an unknown one.
+func (r *RefreshTokenRepository) FindByToken(ctx context.Context, token string) (*entities.RefreshToken, error) {
+	return r.baseRepo.FindFirst(ctx, func(row *entities.RefreshToken) bool {
+		return row.Token == token
+	})
+}
+
+func (r *RefreshTokenRepository) FindByUserID(ctx context.Context, userID int64) ([]*entities.RefreshToken, error) {
+	return r.baseRepo.Where(ctx, func(row *entities.RefreshToken) bool {
+		return row.UserID == userID
+	})
+}
+
+func (r *RefreshTokenRepository) FindActiveByUserID(ctx context.Context, userID int64) ([]*entities.RefreshToken, error) {
+	now := time.Now()
+	return r.baseRepo.Where(ctx, func(row *entities.RefreshToken) bool {
+		return row.UserID == userID && !row.IsRevoked && row.ExpiryAt.After(now)
+	})
+}
+
+func (r *RefreshTokenRepository) RevokeAllByUserIDExceptFamily(ctx context.Context, userID int64, familyID string) error {
+	r.revoke(func(row *entities.RefreshToken) bool {
+		return row.UserID == userID && row.FamilyID != familyID
+	})
+	return nil
+}
+
+func (r *RefreshTokenRepository) RevokeAllByUserID(ctx context.Context, userID int64) error {
+	r.revoke(func(row *entities.RefreshToken) bool { return row.UserID == userID })
+	return nil
+}
+
+func (r *RefreshTokenRepository) RevokeByToken(ctx context.Context, token string) error {
+	r.revoke(func(row *entities.RefreshToken) bool { return row.Token == token })
+	return nil
+}
+
+func (r *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	r.revoke(func(row *entities.RefreshToken) bool { return row.FamilyID == familyID })
+	return nil
+}
+
+func (r *RefreshTokenRepository) revoke(keep func(*entities.RefreshToken) bool) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	tableOf[entities.RefreshToken](r.db).modify(keep, func(row *entities.RefreshToken) {
+		row.IsRevoked = true
+	})
+}
+
+// Rotate marks the token as exchanged. It only succeeds once per token, so two
+// concurrent refreshes with the same token cannot both obtain a successor.
+func (r *RefreshTokenRepository) Rotate(ctx context.Context, token *entities.RefreshToken) error {
+	token.MarkAsRotated()
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	rotated := tableOf[entities.RefreshToken](r.db).modify(
+		func(row *entities.RefreshToken) bool {
+			return row.ID == token.ID && !row.IsRevoked && row.RotatedAt == nil
+		},
+		func(row *entities.RefreshToken) {
+			row.IsRevoked = true
+			row.RotatedAt = token.RotatedAt
+		},
+	)
+	if rotated == 0 {
+		return errors.ErrTokenReused
+	}
+
+	return nil
+}
+
+func (r *RefreshTokenRepository) DeleteExpired(ctx context.Context) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	now := time.Now()
+	tableOf[entities.RefreshToken](r.db).remove(func(row *entities.RefreshToken) bool {
+		return row.ExpiryAt.Before(now)
+	})
+	return nil
+}
+
+func (r *RefreshTokenRepository) IsTokenValid(ctx context.Context, token string) bool {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	now := time.Now()
+	valid := tableOf[entities.RefreshToken](r.db).find(func(row *entities.RefreshToken) bool {
+		return row.Token == token && !row.IsRevoked && row.ExpiryAt.After(now)
+	})
+	return len(valid) > 0
+}
@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"context"
+	"go-gin-clean/internal/core/domain/entities"
+	"go-gin-clean/internal/core/ports"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often the in-memory stores drop stale entries while
+// saving, so keys that are never looked up again do not pile up.
+const sweepInterval = time.Minute
+
+// RevokedTokenRepository keeps the access token denylist in process memory.
+// Entries are dropped once they expire. Like the in-memory lockout store it
+// is lost on restart and not shared between instances.
+type RevokedTokenRepository struct {
+	mu        sync.Mutex
+	entries   map[string]entities.RevokedToken
+	lastSweep time.Time
+}
+
+func NewRevokedTokenRepository() ports.RevokedTokenRepository {
+	return &RevokedTokenRepository{
+		entries: make(map[string]entities.RevokedToken),
+	}
+}
+
+func (r *RevokedTokenRepository) Save(ctx context.Context, token *entities.RevokedToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.lastSweep) >= sweepInterval {
+		r.deleteExpired()
+		r.lastSweep = time.Now()
+	}
+
+	r.entries[token.Key] = *token
+	return nil
+}
+
+func (r *RevokedTokenRepository) FindByKeys(ctx context.Context, keys []string) ([]*entities.RevokedToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var tokens []*entities.RevokedToken
+	for _, key := range keys {
+		entry, ok := r.entries[key]
+		if !ok {
+			continue
+		}
+
+		if entry.IsExpired() {
+			delete(r.entries, key)
+			continue
+		}
+
+		tokens = append(tokens, &entry)
+	}
+	return tokens, nil
+}
+
+func (r *RevokedTokenRepository) DeleteExpired(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.deleteExpired()
+	return nil
+}
+
+func (r *RevokedTokenRepository) deleteExpired() {
+	for key, entry := range r.entries {
+		if entry.IsExpired() {
+			delete(r.entries, key)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package memory
+
+import (
+	"context"
+	"go-gin-clean/internal/core/domain/entities"
+	"go-gin-clean/internal/core/ports"
+	"slices"
+
+	"gorm.io/gorm"
+)
+
+type RoleRepository struct {
+	db       *DB
+	baseRepo ports.BaseRepository[entities.Role]
+}
+
+func NewRoleRepository(db *DB) ports.RoleRepository {
+	baseRepo := NewBaseRepository[entities.Role](db)
+	return &RoleRepository{
+		db:       db,
+		baseRepo: baseRepo,
+	}
+}
+
+func (r *RoleRepository) FindAll(ctx context.Context) ([]*entities.Role, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	roles := tableOf[entities.Role](r.db).find(nil)
+	for _, role := range roles {
+		role.Permissions = slices.Clone(r.db.rolePermissions[role.ID])
+	}
+	return roles, nil
+}
+
+func (r *RoleRepository) FindByName(ctx context.Context, name string) (*entities.Role, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	roles := tableOf[entities.Role](r.db).find(func(role *entities.Role) bool {
+		return role.Name == name
+	})
+	if len(roles) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	role := roles[0]
+	role.Permissions = slices.Clone(r.db.rolePermissions[role.ID])
+	return role, nil
+}
+
+func (r *RoleRepository) FindByNames(ctx context.Context, names []string) ([]*entities.Role, error) {
+	return r.baseRepo.Where(ctx, func(role *entities.Role) bool {
+		return slices.Contains(names, role.Name)
+	})
+}
+
+func (r *RoleRepository) AssignToUser(ctx context.Context, user *entities.User, roles []*entities.Role) error {
+	replacement := make([]entities.Role, len(roles))
+	for i, role := range roles {
+		replacement[i] = *role
+	}
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	r.db.userRoles[user.ID] = nil
+	r.db.linkRoles(user.ID, replacement)
+	user.Roles = replacement
+	return nil
+}
+
+func (r *RoleRepository) Create(ctx context.Context, role *entities.Role) (*entities.Role, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	tableOf[entities.Role](r.db).insert(role)
+	if len(role.Permissions) > 0 {
+		r.db.rolePermissions[role.ID] = slices.Clone(role.Permissions)
+	}
+	return role, nil
+}
+
+func (r *RoleRepository) AssignPermissions(ctx context.Context, role *entities.Role, permissions []*entities.Permission) error {
+	replacement := make([]entities.Permission, len(permissions))
+	for i, permission := range permissions {
+		replacement[i] = *permission
+	}
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	r.db.rolePermissions[role.ID] = slices.Clone(replacement)
+	role.Permissions = replacement
+	return nil
+}
+
+// linkRoles adds the missing user_roles links from the user to roles,
+// storing roles that have no id yet, as gorm does when saving the Roles
+// association. The caller must hold db.mu.
+func (db *DB) linkRoles(userID int64, roles []entities.Role) {
+	t := tableOf[entities.Role](db)
+	for i := range roles {
+		if roles[i].ID == 0 {
+			t.insert(&roles[i])
+		}
+		if !slices.Contains(db.userRoles[userID], roles[i].ID) {
+			db.userRoles[userID] = append(db.userRoles[userID], roles[i].ID)
+		}
+	}
+}
+
+// rolesOf returns the current roles of the user, with their permissions
+// when withPermissions is set. The caller must hold db.mu.
+func (db *DB) rolesOf(userID int64, withPermissions bool) []entities.Role {
+	ids := slices.Sorted(slices.Values(db.userRoles[userID]))
+
+	var roles []entities.Role
+	for _, id := range ids {
+		role, ok := tableOf[entities.Role](db).get(id)
+		if !ok {
+			continue
+		}
+		if withPermissions {
+			role.Permissions = slices.Clone(db.rolePermissions[id])
+		}
+		roles = append(roles, *role)
+	}
+	return roles
+}
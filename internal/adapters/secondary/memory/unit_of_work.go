@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"context"
+	"go-gin-clean/internal/core/ports"
+)
+
+type UnitOfWork struct {
+	db *DB
+}
+
+func NewUnitOfWork(db *DB) ports.UnitOfWork {
+	return &UnitOfWork{
+		db: db,
+	}
+}
+
+// Do runs fn on a copy of the DB and writes the rows it changed back when
+// fn returns nil, so an error or panic discards them without touching
+// writes made outside the unit of work in the meantime. Units of work run
+// one at a time and do not see writes made outside them after they start.
+func (u *UnitOfWork) Do(ctx context.Context, fn func(repos ports.Repositories) error) error {
+	u.db.tx.Lock()
+	defer u.db.tx.Unlock()
+
+	u.db.mu.Lock()
+	base := u.db.snapshot()
+	u.db.mu.Unlock()
+
+	tx := base.snapshot()
+	if err := fn(&repositories{db: tx}); err != nil {
+		return err
+	}
+
+	u.db.mu.Lock()
+	defer u.db.mu.Unlock()
+	u.db.commit(base, tx)
+	return nil
+}
+
+type repositories struct {
+	db *DB
+}
+
+func (r *repositories) Users() ports.UserRepository {
+	return NewUserRepository(r.db)
+}
+
+func (r *repositories) RefreshTokens() ports.RefreshTokenRepository {
+	return NewRefreshTokenRepository(r.db)
+}
+
+func (r *repositories) Roles() ports.RoleRepository {
+	return NewRoleRepository(r.db)
+}
+
+func (r *repositories) RecoveryCodes() ports.RecoveryCodeRepository {
+	return NewRecoveryCodeRepository(r.db)
+}
+
+func (r *repositories) OneTimeTokens() ports.OneTimeTokenRepository {
+	return NewOneTimeTokenRepository(r.db)
+}
+
+func (r *repositories) UserIdentities() ports.UserIdentityRepository {
+	return NewUserIdentityRepository(r.db)
+}
+
+func (r *repositories) PasswordHistories() ports.PasswordHistoryRepository {
+	return NewPasswordHistoryRepository(r.db)
+}
+
+func (r *repositories) APIKeys() ports.APIKeyRepository {
+	return NewAPIKeyRepository(r.db)
+}
@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"go-gin-clean/internal/core/domain/entities"
+	"go-gin-clean/internal/core/domain/enums"
+	"go-gin-clean/internal/core/ports"
+	"testing"
+)
+
+func TestUnitOfWorkRollbackKeepsOutsideWrites(t *testing.T) {
+	db := NewDB()
+	ctx := context.Background()
+	users := NewUserRepository(db)
+
+	newUser := func(email string) *entities.User {
+		user, err := entities.NewUser(email[:3], email, "hash", "", enums.Unknown)
+		if err != nil {
+			t.Fatalf("new user: %v", err)
+		}
+		return user
+	}
+
+	failed := errors.New("failed")
+	err := NewUnitOfWork(db).Do(ctx, func(repos ports.Repositories) error {
+		if _, err := repos.Users().Create(ctx, newUser("ann@example.com")); err != nil {
+			t.Fatalf("create in unit of work: %v", err)
+		}
+
+		// Written while the unit of work runs, as a background audit log would be
+		if _, err := users.Create(ctx, newUser("bob@example.com")); err != nil {
+			t.Fatalf("create outside unit of work: %v", err)
+		}
+		return failed
+	})
+	if err != failed {
+		t.Fatalf("Do: err = %v, want %v", err, failed)
+	}
+
+	if users.ExistsByEmail(ctx, "ann@example.com") {
+		t.Fatal("write of the failed unit of work was kept")
+	}
+
+	if !users.ExistsByEmail(ctx, "bob@example.com") {
+		t.Fatal("write outside the unit of work was rolled back")
+	}
+
+	err = NewUnitOfWork(db).Do(ctx, func(repos ports.Repositories) error {
+		_, err := repos.Users().Create(ctx, newUser("cid@example.com"))
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+
+	cid, err := users.FindByEmail(ctx, "cid@example.com")
+	if err != nil {
+		t.Fatalf("committed write: %v", err)
+	}
+
+	bob, _ := users.FindByEmail(ctx, "bob@example.com")
+	if cid.ID == bob.ID {
+		t.Fatalf("unit of work reused key %d", cid.ID)
+	}
+}
@@ -0,0 +1,37 @@
+package memory
+
+import (
+	"context"
+	"go-gin-clean/internal/core/domain/entities"
+	"go-gin-clean/internal/core/ports"
+)
+
+type UserIdentityRepository struct {
+	db       *DB
+	baseRepo ports.BaseRepository[entities.UserIdentity]
+}
+
+func NewUserIdentityRepository(db *DB) ports.UserIdentityRepository {
+	baseRepo := NewBaseRepository[entities.UserIdentity](db)
+	return &UserIdentityRepository{
+		db:       db,
+		baseRepo: baseRepo,
+	}
+}
+
+func (r *UserIdentityRepository) Create(ctx context.Context, identity *entities.UserIdentity) error {
+	_, err := r.baseRepo.Create(ctx, identity)
+	return err
+}
+
+func (r *UserIdentityRepository) FindByProviderSubject(ctx context.Context, provider, subject string) (*entities.UserIdentity, error) {
+	return r.baseRepo.FindFirst(ctx, func(row *entities.UserIdentity) bool {
+		return row.Provider == provider && row.Subject == subject
+	})
+}
+
+func (r *UserIdentityRepository) FindByUserID(ctx context.Context, userID int64) ([]*entities.UserIdentity, error) {
+	return r.baseRepo.Where(ctx, func(row *entities.UserIdentity) bool {
+		return row.UserID == userID
+	})
+}
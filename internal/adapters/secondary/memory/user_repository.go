@@ -0,0 +1,249 @@
+package memory
+
+import (
+	"context"
+	"go-gin-clean/internal/core/contracts"
+	"go-gin-clean/internal/core/domain/entities"
+	"go-gin-clean/internal/core/domain/errors"
+	"go-gin-clean/internal/core/ports"
+	"go-gin-clean/pkg/utils"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type UserRepository struct {
+	db       *DB
+	baseRepo *BaseRepository[entities.User]
+}
+
+func NewUserRepository(db *DB) ports.UserRepository {
+	return &UserRepository{
+		db:       db,
+		baseRepo: &BaseRepository[entities.User]{db: db},
+	}
+}
+
+func searchTerm(spec *contracts.QuerySpec) string {
+	if spec == nil {
+		return ""
+	}
+	return strings.TrimSpace(spec.Search)
+}
+
+// matchesSearch matches users whose name or email contains term, ignoring
+// case and accents like the Postgres search. Results are not ranked, as on
+// MySQL and SQLite.
+func matchesSearch(term string) func(*entities.User) bool {
+	return func(user *entities.User) bool {
+		_, inName := utils.Highlight(user.Name, term)
+		_, inEmail := utils.Highlight(user.Email, term)
+		return inName || inEmail
+	}
+}
+
+func (r *UserRepository) FindAll(ctx context.Context, limit, offset int, spec *contracts.QuerySpec) ([]*entities.User, int64, error) {
+	var query any
+	if term := searchTerm(spec); term != "" {
+		query = matchesSearch(term)
+	}
+
+	users, total, err := r.baseRepo.FindAllByQuery(ctx, limit, offset, contracts.UserQueryFields, spec, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	r.loadRoles(users, false)
+	return users, total, nil
+}
+
+func (r *UserRepository) FindAllByCursor(ctx context.Context, page *contracts.CursorRequest, spec *contracts.QuerySpec) ([]*entities.User, *contracts.CursorPage, error) {
+	var query any
+	if term := searchTerm(spec); term != "" {
+		query = matchesSearch(term)
+	}
+
+	users, result, err := r.baseRepo.FindAllByCursor(ctx, contracts.UserQueryFields, spec, page, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r.loadRoles(users, false)
+	return users, result, nil
+}
+
+func (r *UserRepository) FindAllDeleted(ctx context.Context, limit, offset int, search string) ([]*entities.User, int64, error) {
+	search = strings.TrimSpace(search)
+	query := func(user *entities.User) bool {
+		return user.DeletedAt != nil && (search == "" || matchesSearch(search)(user))
+	}
+
+	users, total, err := r.baseRepo.WithDeleted().FindAll(ctx, limit, offset, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	r.loadRoles(users, false)
+	return users, total, nil
+}
+
+// loadRoles attaches roles to already fetched users, with their permissions
+// when withPermissions is set.
+func (r *UserRepository) loadRoles(users []*entities.User, withPermissions bool) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	for _, user := range users {
+		user.Roles = r.db.rolesOf(user.ID, withPermissions)
+	}
+}
+
+func (r *UserRepository) FindByID(ctx context.Context, id int64) (*entities.User, error) {
+	return r.findFirst(func(user *entities.User) bool { return user.ID == id })
+}
+
+func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*entities.User, error) {
+	return r.findFirst(func(user *entities.User) bool { return user.Email == email })
+}
+
+// findFirst returns the first user that is not soft-deleted and matches
+// query, with its roles and their permissions.
+func (r *UserRepository) findFirst(query func(*entities.User) bool) (*entities.User, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	user, err := r.baseRepo.first(tableOf[entities.User](r.db), query)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Roles = r.db.rolesOf(user.ID, true)
+	return user, nil
+}
+
+// Create stores the user and links the roles set on it.
+func (r *UserRepository) Create(ctx context.Context, user *entities.User) (*entities.User, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	tableOf[entities.User](r.db).insert(user)
+	r.db.linkRoles(user.ID, user.Roles)
+	return user, nil
+}
+
+// Update writes the non-zero fields of the user and links any roles set on
+// it that it did not have yet. Roles are only removed by AssignToUser.
+func (r *UserRepository) Update(ctx context.Context, user *entities.User) (*entities.User, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if !tableOf[entities.User](r.db).update(user) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	r.db.linkRoles(user.ID, user.Roles)
+	return user, nil
+}
+
+func (r *UserRepository) Delete(ctx context.Context, id int64) error {
+	return r.baseRepo.Delete(ctx, id)
+}
+
+// Restore returns ErrUserNotFound when no soft-deleted user has id.
+func (r *UserRepository) Restore(ctx context.Context, id int64) error {
+	err := r.baseRepo.Restore(ctx, id)
+	if err == gorm.ErrRecordNotFound {
+		return errors.ErrUserNotFound
+	}
+	return err
+}
+
+// Purge removes the users soft-deleted before deletedBefore together with
+// the rows that reference them.
+func (r *UserRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	t := tableOf[entities.User](r.db)
+	ids := t.remove(softDeletedBefore(t, deletedBefore))
+
+	removeByUser[entities.RefreshToken](r.db, ids, func(row *entities.RefreshToken) int64 { return row.UserID })
+	removeByUser[entities.RecoveryCode](r.db, ids, func(row *entities.RecoveryCode) int64 { return row.UserID })
+	removeByUser[entities.OneTimeToken](r.db, ids, func(row *entities.OneTimeToken) int64 { return row.UserID })
+	removeByUser[entities.PasswordHistory](r.db, ids, func(row *entities.PasswordHistory) int64 { return row.UserID })
+	removeByUser[entities.UserIdentity](r.db, ids, func(row *entities.UserIdentity) int64 { return row.UserID })
+	removeByUser[entities.APIKey](r.db, ids, func(row *entities.APIKey) int64 { return row.UserID })
+
+	for _, id := range ids {
+		delete(r.db.userRoles, id)
+	}
+
+	return int64(len(ids)), nil
+}
+
+// removeByUser deletes the rows of T belonging to any of the users. The
+// caller must hold db.mu.
+func removeByUser[T any](db *DB, userIDs []int64, userID func(*T) int64) {
+	tableOf[T](db).remove(func(row *T) bool {
+		return slices.Contains(userIDs, userID(row))
+	})
+}
+
+// ExistsByEmail also matches soft-deleted users.
+func (r *UserRepository) ExistsByEmail(ctx context.Context, email string) bool {
+	isExist, _ := r.baseRepo.WithDeleted().WhereExisting(ctx, func(user *entities.User) bool {
+		return user.Email == email
+	})
+	return isExist
+}
+
+func (r *UserRepository) UpdateTwoFactor(ctx context.Context, user *entities.User) error {
+	return r.modify(user.ID, func(row *entities.User) {
+		row.TwoFactorEnabled = user.TwoFactorEnabled
+		row.TwoFactorSecret = user.TwoFactorSecret
+		row.TwoFactorLastStep = user.TwoFactorLastStep
+	})
+}
+
+func (r *UserRepository) UpdateTwoFactorStep(ctx context.Context, user *entities.User) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	updated := tableOf[entities.User](r.db).modify(
+		func(row *entities.User) bool {
+			return row.ID == user.ID && row.TwoFactorLastStep < user.TwoFactorLastStep
+		},
+		func(row *entities.User) {
+			row.TwoFactorSecret = user.TwoFactorSecret
+			row.TwoFactorLastStep = user.TwoFactorLastStep
+		},
+	)
+	if updated == 0 {
+		return errors.ErrInvalidTwoFactorCode
+	}
+
+	return nil
+}
+
+func (r *UserRepository) UpdateEmail(ctx context.Context, user *entities.User) error {
+	return r.modify(user.ID, func(row *entities.User) {
+		row.Email = user.Email
+		row.PendingEmail = user.PendingEmail
+	})
+}
+
+func (r *UserRepository) UpdatePassword(ctx context.Context, user *entities.User) error {
+	return r.modify(user.ID, func(row *entities.User) {
+		row.Password = user.Password
+	})
+}
+
+// modify applies fn to the user row, whether or not it is soft-deleted.
+func (r *UserRepository) modify(id int64, fn func(*entities.User)) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	tableOf[entities.User](r.db).modify(func(row *entities.User) bool { return row.ID == id }, fn)
+	return nil
+}
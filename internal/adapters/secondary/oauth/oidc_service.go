@@ -0,0 +1,336 @@
+package oauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"go-gin-clean/internal/core/contracts"
+	"go-gin-clean/internal/core/domain/errors"
+	"go-gin-clean/internal/core/ports"
+	"go-gin-clean/pkg/config"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// jwksRefreshInterval limits how often an unknown kid may trigger a new
+// JWKS download, so forged tokens cannot make us hammer the provider.
+const jwksRefreshInterval = time.Minute
+
+// OIDCService is an OpenID Connect relying party using the authorization
+// code flow with PKCE. Provider metadata and signing keys are discovered from
+// the issuer and cached.
+type OIDCService struct {
+	providers  map[string]config.OAuthProviderConfig
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	discovery map[string]*providerMetadata
+	keys      map[string]*jwkSet
+	fetchedAt map[string]time.Time
+}
+
+type providerMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserInfoEndpoint      string `json:"userinfo_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	IDToken     string `json:"id_token"`
+	TokenType   string `json:"token_type"`
+}
+
+func NewOIDCService(cfg *config.OAuthConfig, httpClient *http.Client) ports.OAuthService {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &OIDCService{
+		providers:  cfg.Providers,
+		httpClient: httpClient,
+		discovery:  make(map[string]*providerMetadata),
+		keys:       make(map[string]*jwkSet),
+		fetchedAt:  make(map[string]time.Time),
+	}
+}
+
+func (s *OIDCService) AuthCodeURL(ctx context.Context, provider, state, nonce, codeVerifier string) (string, error) {
+	cfg, ok := s.providers[provider]
+	if !ok {
+		return "", errors.ErrOAuthProviderNotFound
+	}
+
+	metadata, err := s.metadata(ctx, cfg)
+	if err != nil {
+		return "", err
+	}
+
+	challenge := sha256.Sum256([]byte(codeVerifier))
+
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", cfg.ClientID)
+	params.Set("redirect_uri", cfg.RedirectURL)
+	params.Set("scope", strings.Join(cfg.Scopes, " "))
+	params.Set("state", state)
+	params.Set("nonce", nonce)
+	params.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	params.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(metadata.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return metadata.AuthorizationEndpoint + separator + params.Encode(), nil
+}
+
+func (s *OIDCService) Exchange(ctx context.Context, provider, code, codeVerifier, nonce string) (*contracts.ExternalIdentity, error) {
+	cfg, ok := s.providers[provider]
+	if !ok {
+		return nil, errors.ErrOAuthProviderNotFound
+	}
+
+	metadata, err := s.metadata(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.exchangeCode(ctx, cfg, metadata, code, codeVerifier)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := s.verifyIDToken(ctx, cfg, metadata, tokens.IDToken, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	identity := &contracts.ExternalIdentity{
+		Provider:      provider,
+		Subject:       stringClaim(claims["sub"]),
+		Email:         stringClaim(claims["email"]),
+		EmailVerified: boolClaim(claims["email_verified"]),
+		Name:          stringClaim(claims["name"]),
+	}
+
+	if identity.Subject == "" {
+		return nil, errors.ErrOAuthExchangeFailed
+	}
+
+	// Some providers only return profile claims from the userinfo endpoint
+	if identity.Email == "" && metadata.UserInfoEndpoint != "" && tokens.AccessToken != "" {
+		if err := s.loadUserInfo(ctx, metadata.UserInfoEndpoint, tokens.AccessToken, identity); err != nil {
+			return nil, err
+		}
+	}
+
+	return identity, nil
+}
+
+func (s *OIDCService) metadata(ctx context.Context, cfg config.OAuthProviderConfig) (*providerMetadata, error) {
+	s.mu.Lock()
+	cached, ok := s.discovery[cfg.Name]
+	s.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	var metadata providerMetadata
+	if err := s.getJSON(ctx, cfg.IssuerURL+"/.well-known/openid-configuration", "", &metadata); err != nil {
+		return nil, err
+	}
+
+	if metadata.Issuer != cfg.IssuerURL {
+		return nil, fmt.Errorf("%w: issuer mismatch in discovery document", errors.ErrOAuthExchangeFailed)
+	}
+
+	s.mu.Lock()
+	s.discovery[cfg.Name] = &metadata
+	s.mu.Unlock()
+
+	return &metadata, nil
+}
+
+func (s *OIDCService) exchangeCode(ctx context.Context, cfg config.OAuthProviderConfig, metadata *providerMetadata, code, codeVerifier string) (*tokenResponse, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", cfg.RedirectURL)
+	form.Set("code_verifier", codeVerifier)
+	if cfg.ClientSecret == "" {
+		form.Set("client_id", cfg.ClientID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if cfg.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(cfg.ClientID), url.QueryEscape(cfg.ClientSecret))
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("%w: token endpoint returned %d: %s", errors.ErrOAuthExchangeFailed, resp.StatusCode, body)
+	}
+
+	var tokens tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tokens); err != nil {
+		return nil, err
+	}
+
+	if tokens.IDToken == "" {
+		return nil, fmt.Errorf("%w: response has no id_token", errors.ErrOAuthExchangeFailed)
+	}
+
+	return &tokens, nil
+}
+
+func (s *OIDCService) verifyIDToken(ctx context.Context, cfg config.OAuthProviderConfig, metadata *providerMetadata, idToken, nonce string) (jwt.MapClaims, error) {
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512", "EdDSA"}))
+
+	claims := jwt.MapClaims{}
+	_, err := parser.ParseWithClaims(idToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return s.verificationKey(ctx, cfg.Name, metadata.JWKSURI, kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrOAuthExchangeFailed, err)
+	}
+
+	if !claims.VerifyIssuer(metadata.Issuer, true) || !claims.VerifyAudience(cfg.ClientID, true) {
+		return nil, fmt.Errorf("%w: id_token issuer or audience mismatch", errors.ErrOAuthExchangeFailed)
+	}
+
+	if _, ok := claims["exp"]; !ok {
+		return nil, fmt.Errorf("%w: id_token has no expiry", errors.ErrOAuthExchangeFailed)
+	}
+
+	if stringClaim(claims["nonce"]) != nonce {
+		return nil, fmt.Errorf("%w: id_token nonce mismatch", errors.ErrOAuthExchangeFailed)
+	}
+
+	return claims, nil
+}
+
+// verificationKey returns the provider key for kid, refreshing the cached
+// key set when the kid is unknown (provider key rotation). Refreshes happen
+// at most once per jwksRefreshInterval for each provider.
+func (s *OIDCService) verificationKey(ctx context.Context, provider, jwksURI, kid string) (interface{}, error) {
+	s.mu.Lock()
+	keys, ok := s.keys[provider]
+	if ok {
+		if key, found := keys.find(kid); found {
+			s.mu.Unlock()
+			return key, nil
+		}
+
+		if time.Since(s.fetchedAt[provider]) < jwksRefreshInterval {
+			s.mu.Unlock()
+			return nil, fmt.Errorf("signing key %q not found", kid)
+		}
+	}
+	// Claim the refresh slot before fetching so concurrent misses share it
+	s.fetchedAt[provider] = time.Now()
+	s.mu.Unlock()
+
+	var raw rawJWKSet
+	if err := s.getJSON(ctx, jwksURI, "", &raw); err != nil {
+		return nil, err
+	}
+
+	keys, err := parseJWKSet(&raw)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.keys[provider] = keys
+	s.mu.Unlock()
+
+	if key, found := keys.find(kid); found {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("signing key %q not found", kid)
+}
+
+func (s *OIDCService) loadUserInfo(ctx context.Context, endpoint, accessToken string, identity *contracts.ExternalIdentity) error {
+	var claims map[string]any
+	if err := s.getJSON(ctx, endpoint, accessToken, &claims); err != nil {
+		return err
+	}
+
+	// The userinfo response must describe the same subject as the ID token
+	if stringClaim(claims["sub"]) != identity.Subject {
+		return fmt.Errorf("%w: userinfo subject mismatch", errors.ErrOAuthExchangeFailed)
+	}
+
+	identity.Email = stringClaim(claims["email"])
+	identity.EmailVerified = boolClaim(claims["email_verified"])
+	if identity.Name == "" {
+		identity.Name = stringClaim(claims["name"])
+	}
+
+	return nil
+}
+
+func (s *OIDCService) getJSON(ctx context.Context, endpoint, accessToken string, target any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s returned %d", errors.ErrOAuthExchangeFailed, endpoint, resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+func stringClaim(value any) string {
+	str, _ := value.(string)
+	return str
+}
+
+// boolClaim accepts both JSON booleans and the "true" string some providers send.
+func boolClaim(value any) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	default:
+		return false
+	}
+}
@@ -0,0 +1,245 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"go-gin-clean/pkg/config"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	testProvider = "test"
+	testClientID = "client-id"
+	testKeyID    = "key-1"
+	testCode     = "auth-code"
+)
+
+// fakeProvider is a minimal OpenID provider serving discovery, JWKS and
+// token endpoints. The token endpoint enforces PKCE against the challenge
+// captured from the authorization URL.
+type fakeProvider struct {
+	t      *testing.T
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	// signingKey and kid are used for the next id_token
+	signingKey *rsa.PrivateKey
+	kid        string
+	nonce      string
+	challenge  string
+
+	jwksHits atomic.Int32
+}
+
+func newFakeProvider(t *testing.T) *fakeProvider {
+	t.Helper()
+
+	p := &fakeProvider{t: t, key: generateKey(t), kid: testKeyID}
+	p.signingKey = p.key
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", p.discovery)
+	mux.HandleFunc("/jwks", p.jwks)
+	mux.HandleFunc("/token", p.token)
+
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+
+	return p
+}
+
+func (p *fakeProvider) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]string{
+		"issuer":                 p.server.URL,
+		"authorization_endpoint": p.server.URL + "/authorize",
+		"token_endpoint":         p.server.URL + "/token",
+		"jwks_uri":               p.server.URL + "/jwks",
+	})
+}
+
+func (p *fakeProvider) jwks(w http.ResponseWriter, r *http.Request) {
+	p.jwksHits.Add(1)
+
+	writeJSON(w, map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": testKeyID,
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(p.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(p.key.E)).Bytes()),
+		}},
+	})
+}
+
+func (p *fakeProvider) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	verifier := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if r.PostForm.Get("code") != testCode ||
+		base64.RawURLEncoding.EncodeToString(verifier[:]) != p.challenge {
+		http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+		return
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":            p.server.URL,
+		"aud":            testClientID,
+		"sub":            "subject-1",
+		"email":          "user@example.com",
+		"email_verified": true,
+		"nonce":          p.nonce,
+		"exp":            time.Now().Add(time.Minute).Unix(),
+	})
+	token.Header["kid"] = p.kid
+
+	idToken, err := token.SignedString(p.signingKey)
+	if err != nil {
+		p.t.Errorf("sign id_token: %v", err)
+	}
+
+	writeJSON(w, map[string]string{"access_token": "access", "id_token": idToken, "token_type": "Bearer"})
+}
+
+func (p *fakeProvider) service() *OIDCService {
+	return NewOIDCService(&config.OAuthConfig{
+		Providers: map[string]config.OAuthProviderConfig{
+			testProvider: {
+				Name:        testProvider,
+				IssuerURL:   p.server.URL,
+				ClientID:    testClientID,
+				RedirectURL: "http://localhost/callback",
+				Scopes:      []string{"openid", "email"},
+			},
+		},
+	}, p.server.Client()).(*OIDCService)
+}
+
+// authorize runs the start of the flow and records what the provider would
+// remember from the authorization request.
+func (p *fakeProvider) authorize(s *OIDCService, state, nonce, codeVerifier string) {
+	p.t.Helper()
+
+	authURL, err := s.AuthCodeURL(context.Background(), testProvider, state, nonce, codeVerifier)
+	if err != nil {
+		p.t.Fatalf("AuthCodeURL: %v", err)
+	}
+
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		p.t.Fatalf("parse auth URL: %v", err)
+	}
+
+	query := parsed.Query()
+	if query.Get("state") != state || query.Get("code_challenge_method") != "S256" {
+		p.t.Fatalf("unexpected auth URL %s", authURL)
+	}
+
+	p.nonce = query.Get("nonce")
+	p.challenge = query.Get("code_challenge")
+}
+
+func TestExchangeVerifiesPKCE(t *testing.T) {
+	provider := newFakeProvider(t)
+	s := provider.service()
+	provider.authorize(s, "state", "nonce", "verifier")
+
+	if _, err := s.Exchange(context.Background(), testProvider, testCode, "other-verifier", "nonce"); err == nil {
+		t.Fatal("Exchange accepted a code verifier that does not match the challenge")
+	}
+
+	identity, err := s.Exchange(context.Background(), testProvider, testCode, "verifier", "nonce")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+
+	if identity.Subject != "subject-1" || identity.Email != "user@example.com" || !identity.EmailVerified {
+		t.Fatalf("unexpected identity %+v", identity)
+	}
+}
+
+func TestExchangeRejectsNonceMismatch(t *testing.T) {
+	provider := newFakeProvider(t)
+	s := provider.service()
+	provider.authorize(s, "state", "nonce", "verifier")
+
+	if _, err := s.Exchange(context.Background(), testProvider, testCode, "verifier", "other-nonce"); err == nil {
+		t.Fatal("Exchange accepted an id_token issued for another nonce")
+	}
+}
+
+func TestExchangeRejectsBadSignature(t *testing.T) {
+	provider := newFakeProvider(t)
+	s := provider.service()
+	provider.authorize(s, "state", "nonce", "verifier")
+	provider.signingKey = generateKey(t)
+
+	if _, err := s.Exchange(context.Background(), testProvider, testCode, "verifier", "nonce"); err == nil {
+		t.Fatal("Exchange accepted an id_token signed with an unknown key")
+	}
+}
+
+func TestUnknownKeyIDRefetchIsRateLimited(t *testing.T) {
+	provider := newFakeProvider(t)
+	s := provider.service()
+	provider.authorize(s, "state", "nonce", "verifier")
+
+	if _, err := s.Exchange(context.Background(), testProvider, testCode, "verifier", "nonce"); err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+
+	// Unknown kids within the interval of the last download are refused
+	// without another request to the provider
+	provider.kid = "unknown"
+	for i := 0; i < 3; i++ {
+		if _, err := s.Exchange(context.Background(), testProvider, testCode, "verifier", "nonce"); err == nil {
+			t.Fatal("Exchange accepted an id_token with an unknown kid")
+		}
+	}
+
+	if hits := provider.jwksHits.Load(); hits != 1 {
+		t.Fatalf("JWKS fetched %d times, want 1", hits)
+	}
+
+	// Once the interval has passed a single refresh is allowed again
+	s.mu.Lock()
+	s.fetchedAt[testProvider] = time.Now().Add(-jwksRefreshInterval)
+	s.mu.Unlock()
+
+	for i := 0; i < 3; i++ {
+		s.Exchange(context.Background(), testProvider, testCode, "verifier", "nonce")
+	}
+
+	if hits := provider.jwksHits.Load(); hits != 2 {
+		t.Fatalf("JWKS fetched %d times, want 2", hits)
+	}
+}
+
+func generateKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	return key
+}
+
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(value)
+}
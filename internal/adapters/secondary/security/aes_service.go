@@ -4,89 +4,144 @@ import (
 	"bytes"
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/rand"
 	"encoding/base64"
 	"fmt"
 	"go-gin-clean/internal/core/ports"
 	"go-gin-clean/pkg/config"
+	"strconv"
+	"strings"
 )
 
+// AESService encrypts with AES-GCM using a random nonce per message. Output is
+// prefixed with the key version ("v2.<base64>") so several keys can be active
+// while data is being rotated to a new key. Stored values without a version
+// prefix were produced by the former AES-CBC implementation and are still
+// decrypted by DecryptInternal with the legacy key and IV. DecryptURLSafe only
+// handles values that round-trip through clients, so it never falls back to
+// the unauthenticated CBC mode.
 type AESService struct {
-	Key []byte
-	IV  []byte
+	keys          map[int][]byte
+	activeVersion int
+	legacyKey     []byte
+	legacyIV      []byte
 }
 
-func NewAESService(cfg *config.AESConfig) ports.EncryptionService {
-	return &AESService{
-		Key: []byte(cfg.Key),
-		IV:  []byte(cfg.IV),
+// NewAESService requires AES_KEYS. The legacy CBC key is never used for GCM,
+// so a key that once protected data with a static IV does not carry over.
+func NewAESService(cfg *config.AESConfig) (ports.EncryptionService, error) {
+	if len(cfg.Keys) == 0 {
+		return nil, fmt.Errorf("no AES-GCM keys configured, set AES_KEYS")
+	}
+
+	keys := make(map[int][]byte, len(cfg.Keys))
+	for version, key := range cfg.Keys {
+		if _, err := aes.NewCipher([]byte(key)); err != nil {
+			return nil, fmt.Errorf("AES key version %d: %w", version, err)
+		}
+		keys[version] = []byte(key)
+	}
+
+	activeVersion := cfg.ActiveKeyVersion()
+	if _, ok := keys[activeVersion]; !ok {
+		return nil, fmt.Errorf("active AES key version %d is not configured", activeVersion)
 	}
+
+	return &AESService{
+		keys:          keys,
+		activeVersion: activeVersion,
+		legacyKey:     []byte(cfg.Key),
+		legacyIV:      []byte(cfg.IV),
+	}, nil
 }
 
-func pkcs7Pad(data []byte, blockSize int) []byte {
-	padding := blockSize - len(data)%blockSize
-	padtext := bytes.Repeat([]byte{byte(padding)}, padding)
-	return append(data, padtext...)
+func (a *AESService) EncryptInternal(plaintext string) (string, error) {
+	return a.encrypt(plaintext, base64.StdEncoding)
 }
 
-func pkcs7Unpad(data []byte) ([]byte, error) {
-	length := len(data)
-	if length == 0 {
-		return nil, fmt.Errorf("ciphertext is empty")
-	}
-	unpadding := int(data[length-1])
-	if unpadding > aes.BlockSize || unpadding == 0 || unpadding > length {
-		return nil, fmt.Errorf("invalid padding")
-	}
-	for i := length - unpadding; i < length; i++ {
-		if data[i] != byte(unpadding) {
-			return nil, fmt.Errorf("invalid padding")
-		}
+func (a *AESService) DecryptInternal(ciphertext string) (string, error) {
+	return a.decrypt(ciphertext, base64.StdEncoding, base64.StdEncoding)
+}
+
+func (a *AESService) EncryptURLSafe(plaintext string) (string, error) {
+	return a.encrypt(plaintext, base64.RawURLEncoding)
+}
+
+func (a *AESService) DecryptURLSafe(ciphertext string) (string, error) {
+	return a.decrypt(ciphertext, base64.RawURLEncoding, nil)
+}
+
+func (a *AESService) NeedsRotation(ciphertext string) bool {
+	if ciphertext == "" {
+		return false
 	}
-	return data[:(length - unpadding)], nil
+
+	version, _, ok := splitVersion(ciphertext)
+	return !ok || version != a.activeVersion
 }
 
-func (a *AESService) EncryptInternal(plaintext string) (string, error) {
+func (a *AESService) encrypt(plaintext string, encoding *base64.Encoding) (string, error) {
 	if plaintext == "" {
 		return "", nil
 	}
 
-	block, err := aes.NewCipher(a.Key)
+	key, ok := a.keys[a.activeVersion]
+	if !ok {
+		return "", fmt.Errorf("encryption key version %d is not configured", a.activeVersion)
+	}
+
+	gcm, err := newGCM(key)
 	if err != nil {
 		return "", err
 	}
 
-	padded := pkcs7Pad([]byte(plaintext), aes.BlockSize)
-	ciphertext := make([]byte, len(padded))
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
 
-	mode := cipher.NewCBCEncrypter(block, a.IV)
-	mode.CryptBlocks(ciphertext, padded)
+	prefix := versionPrefix(a.activeVersion)
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), []byte(prefix))
 
-	return base64.StdEncoding.EncodeToString(ciphertext), nil
+	return prefix + encoding.EncodeToString(sealed), nil
 }
 
-func (a *AESService) DecryptInternal(ciphertext string) (string, error) {
+// decrypt opens a versioned GCM value. Unversioned values are read as legacy
+// CBC only when legacyEncoding is given.
+func (a *AESService) decrypt(ciphertext string, encoding, legacyEncoding *base64.Encoding) (string, error) {
 	if ciphertext == "" {
 		return "", nil
 	}
 
-	ciphertextBytes, err := base64.StdEncoding.DecodeString(ciphertext)
+	version, payload, ok := splitVersion(ciphertext)
+	if !ok {
+		if legacyEncoding == nil {
+			return "", fmt.Errorf("ciphertext has no key version")
+		}
+		return a.decryptLegacy(ciphertext, legacyEncoding)
+	}
+
+	key, ok := a.keys[version]
+	if !ok {
+		return "", fmt.Errorf("encryption key version %d is not configured", version)
+	}
+
+	sealed, err := encoding.DecodeString(payload)
 	if err != nil {
 		return "", err
 	}
 
-	block, err := aes.NewCipher(a.Key)
+	gcm, err := newGCM(key)
 	if err != nil {
 		return "", err
 	}
 
-	if len(ciphertextBytes)%aes.BlockSize != 0 {
-		return "", fmt.Errorf("ciphertext is not a multiple of the block size")
+	if len(sealed) < gcm.NonceSize() {
+		return "", fmt.Errorf("ciphertext is too short")
 	}
 
-	mode := cipher.NewCBCDecrypter(block, a.IV)
-	mode.CryptBlocks(ciphertextBytes, ciphertextBytes)
-
-	plaintext, err := pkcs7Unpad(ciphertextBytes)
+	nonce, data := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, data, []byte(versionPrefix(version)))
 	if err != nil {
 		return "", err
 	}
@@ -94,51 +149,81 @@ func (a *AESService) DecryptInternal(ciphertext string) (string, error) {
 	return string(plaintext), nil
 }
 
-func (a *AESService) EncryptURLSafe(plaintext string) (string, error) {
-	if plaintext == "" {
-		return "", nil
+// decryptLegacy reads values written by the former AES-CBC implementation
+// with a static IV. It is kept only to migrate existing data.
+func (a *AESService) decryptLegacy(ciphertext string, encoding *base64.Encoding) (string, error) {
+	ciphertextBytes, err := encoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", err
 	}
 
-	block, err := aes.NewCipher(a.Key)
+	block, err := aes.NewCipher(a.legacyKey)
 	if err != nil {
 		return "", err
 	}
 
-	padded := pkcs7Pad([]byte(plaintext), aes.BlockSize)
-	ciphertext := make([]byte, len(padded))
-
-	mode := cipher.NewCBCEncrypter(block, a.IV)
-	mode.CryptBlocks(ciphertext, padded)
-
-	return base64.URLEncoding.EncodeToString(ciphertext), nil
-}
+	if len(a.legacyIV) != aes.BlockSize {
+		return "", fmt.Errorf("legacy IV must be %d bytes", aes.BlockSize)
+	}
 
-func (a *AESService) DecryptURLSafe(ciphertext string) (string, error) {
-	if ciphertext == "" {
-		return "", nil
+	if len(ciphertextBytes) == 0 || len(ciphertextBytes)%aes.BlockSize != 0 {
+		return "", fmt.Errorf("ciphertext is not a multiple of the block size")
 	}
 
-	ciphertextBytes, err := base64.URLEncoding.DecodeString(ciphertext)
+	mode := cipher.NewCBCDecrypter(block, a.legacyIV)
+	mode.CryptBlocks(ciphertextBytes, ciphertextBytes)
+
+	plaintext, err := pkcs7Unpad(ciphertextBytes)
 	if err != nil {
 		return "", err
 	}
 
-	block, err := aes.NewCipher(a.Key)
+	return string(plaintext), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
+	return cipher.NewGCM(block)
+}
 
-	if len(ciphertextBytes)%aes.BlockSize != 0 {
-		return "", fmt.Errorf("ciphertext is not a multiple of the block size")
+func versionPrefix(version int) string {
+	return "v" + strconv.Itoa(version) + "."
+}
+
+// splitVersion parses the "v<version>." prefix. The dot never occurs in
+// base64 output, so legacy values cannot be mistaken for versioned ones.
+func splitVersion(ciphertext string) (int, string, bool) {
+	if !strings.HasPrefix(ciphertext, "v") {
+		return 0, "", false
 	}
 
-	mode := cipher.NewCBCDecrypter(block, a.IV)
-	mode.CryptBlocks(ciphertextBytes, ciphertextBytes)
+	prefix, payload, found := strings.Cut(ciphertext[1:], ".")
+	if !found {
+		return 0, "", false
+	}
 
-	plaintext, err := pkcs7Unpad(ciphertextBytes)
+	version, err := strconv.Atoi(prefix)
 	if err != nil {
-		return "", err
+		return 0, "", false
 	}
 
-	return string(plaintext), nil
+	return version, payload, true
+}
+
+func pkcs7Unpad(data []byte) ([]byte, error) {
+	length := len(data)
+	if length == 0 {
+		return nil, fmt.Errorf("ciphertext is empty")
+	}
+	unpadding := int(data[length-1])
+	if unpadding > aes.BlockSize || unpadding == 0 || unpadding > length {
+		return nil, fmt.Errorf("invalid padding")
+	}
+	if !bytes.Equal(data[length-unpadding:], bytes.Repeat([]byte{byte(unpadding)}, unpadding)) {
+		return nil, fmt.Errorf("invalid padding")
+	}
+	return data[:(length - unpadding)], nil
 }
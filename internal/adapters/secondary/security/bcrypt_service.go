@@ -1,19 +1,22 @@
 package security
 
 import (
-	"go-gin-clean/internal/core/ports"
-
 	"golang.org/x/crypto/bcrypt"
 )
 
-type BcryptService struct{}
+type BcryptService struct {
+	cost int
+}
 
-func NewBcryptService() ports.BcryptService {
-	return &BcryptService{}
+func NewBcryptService(cost int) *BcryptService {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		cost = bcrypt.DefaultCost
+	}
+	return &BcryptService{cost: cost}
 }
 
 func (p *BcryptService) HashPassword(password string) (string, error) {
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), p.cost)
 	if err != nil {
 		return "", err
 	}
@@ -23,3 +26,8 @@ func (p *BcryptService) HashPassword(password string) (string, error) {
 func (p *BcryptService) ValidatePassword(password, hashedPassword string) error {
 	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
 }
+
+func (p *BcryptService) NeedsRehash(hashedPassword string) bool {
+	cost, err := bcrypt.Cost([]byte(hashedPassword))
+	return err != nil || cost != p.cost
+}
@@ -1,11 +1,14 @@
 package security
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"go-gin-clean/internal/core/contracts"
 	"go-gin-clean/internal/core/domain/entities"
 	"go-gin-clean/internal/core/domain/errors"
 	"go-gin-clean/internal/core/ports"
 	"go-gin-clean/pkg/config"
+	"math"
 	"strconv"
 	"time"
 
@@ -13,30 +16,100 @@ import (
 )
 
 type JWTService struct {
-	cfg *config.JWTConfig
+	cfg  *config.JWTConfig
+	keys *KeySet
+	// hs256AcceptUntil is when HS256 access tokens stop being accepted once
+	// asymmetric keys are in use.
+	hs256AcceptUntil time.Time
 }
 
-func NewJWTService(cfg *config.JWTConfig) ports.JWTService {
-	return &JWTService{cfg: cfg}
+// NewJWTService signs access tokens with the asymmetric key set found in
+// cfg.KeysDir, or with the HS256 access secret when no key directory is set.
+// Refresh and MFA tokens never leave this service and always use HS256.
+//
+// Once keys are configured, HS256 access tokens are only accepted until
+// cfg.HS256AcceptUntil, and never for longer than one access token lifetime
+// from now, since the access secret also signs MFA tokens.
+func NewJWTService(cfg *config.JWTConfig) (ports.JWTService, error) {
+	service := &JWTService{cfg: cfg}
+
+	if cfg.KeysDir != "" {
+		keys, err := LoadKeySet(cfg.KeysDir, cfg.ActiveKeyID)
+		if err != nil {
+			return nil, err
+		}
+		service.keys = keys
+
+		latest := time.Now().Add(cfg.AccessTokenExpiry)
+		if cfg.HS256AcceptUntil.After(latest) {
+			service.hs256AcceptUntil = latest
+		} else {
+			service.hs256AcceptUntil = cfg.HS256AcceptUntil
+		}
+	}
+
+	return service, nil
 }
 
-func (j *JWTService) GenerateAccessToken(user *entities.User) (string, time.Time, error) {
+func (j *JWTService) GenerateAccessToken(user *entities.User, sessionID string) (string, time.Time, error) {
 	now := time.Now()
 	expiryAt := now.Add(j.cfg.AccessTokenExpiry)
 
+	jti, err := generateJTI()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	claims := jwt.MapClaims{
+		"jti":         jti,
+		"user_id":     user.ID,
+		"email":       user.Email,
+		"roles":       user.RoleNames(),
+		"permissions": user.PermissionNames(),
+		"sid":         sessionID,
+		"token_type":  "access",
+		"exp":         expiryAt.Unix(),
+		"iat":         numericDate(now),
+		"nbf":         now.Unix(),
+		"iss":         "go-gin-clean",
+		"sub":         strconv.FormatInt(user.ID, 10),
+	}
+
+	var tokenString string
+	if j.keys != nil {
+		tokenString, err = j.keys.Sign(claims)
+	} else {
+		tokenString, err = jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(j.cfg.AccessTokenSecret))
+	}
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return tokenString, expiryAt, nil
+}
+
+func (j *JWTService) GenerateRefreshToken(userID int64) (string, time.Time, error) {
+	now := time.Now()
+	expiryAt := now.Add(j.cfg.RefreshTokenExpiry)
+
+	jti, err := generateJTI()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
 	claims := jwt.MapClaims{
-		"user_id":    user.ID,
-		"email":      user.Email,
-		"token_type": "access",
+		"jti":        jti,
+		"user_id":    userID,
+		"token_type": "refresh",
 		"exp":        expiryAt.Unix(),
 		"iat":        now.Unix(),
 		"nbf":        now.Unix(),
 		"iss":        "go-gin-clean",
-		"sub":        strconv.FormatInt(user.ID, 10),
+		"sub":        strconv.FormatInt(userID, 10),
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(j.cfg.AccessTokenSecret))
+	tokenString, err := token.SignedString([]byte(j.cfg.RefreshTokenSecret))
 	if err != nil {
 		return "", time.Time{}, err
 	}
@@ -44,13 +117,13 @@ func (j *JWTService) GenerateAccessToken(user *entities.User) (string, time.Time
 	return tokenString, expiryAt, nil
 }
 
-func (j *JWTService) GenerateRefreshToken(userID int64) (string, time.Time, error) {
+func (j *JWTService) GenerateMFAToken(userID int64) (string, time.Time, error) {
 	now := time.Now()
-	expiryAt := now.Add(j.cfg.RefreshTokenExpiry)
+	expiryAt := now.Add(j.cfg.MFATokenExpiry)
 
 	claims := jwt.MapClaims{
 		"user_id":    userID,
-		"token_type": "refresh",
+		"token_type": "mfa",
 		"exp":        expiryAt.Unix(),
 		"iat":        now.Unix(),
 		"nbf":        now.Unix(),
@@ -59,7 +132,7 @@ func (j *JWTService) GenerateRefreshToken(userID int64) (string, time.Time, erro
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(j.cfg.RefreshTokenSecret))
+	tokenString, err := token.SignedString([]byte(j.cfg.AccessTokenSecret))
 	if err != nil {
 		return "", time.Time{}, err
 	}
@@ -68,12 +141,7 @@ func (j *JWTService) GenerateRefreshToken(userID int64) (string, time.Time, erro
 }
 
 func (j *JWTService) ValidateAccessToken(tokenString string) (*contracts.AccessTokenClaims, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.ErrUnexpectedSigningMethod
-		}
-		return []byte(j.cfg.AccessTokenSecret), nil
-	})
+	token, err := jwt.Parse(tokenString, j.accessTokenKey)
 
 	if err != nil {
 		return nil, errors.ErrTokenInvalid
@@ -103,6 +171,19 @@ func (j *JWTService) ValidateAccessToken(tokenString string) (*contracts.AccessT
 		return nil, errors.ErrInvalidClaims
 	}
 
+	roles, ok := stringSliceClaim(claims["roles"])
+	if !ok {
+		return nil, errors.ErrInvalidClaims
+	}
+
+	permissions, ok := stringSliceClaim(claims["permissions"])
+	if !ok {
+		return nil, errors.ErrInvalidClaims
+	}
+
+	sessionID, _ := claims["sid"].(string)
+	tokenID, _ := claims["jti"].(string)
+
 	exp, ok := claims["exp"].(float64)
 	if !ok {
 		return nil, errors.ErrInvalidClaims
@@ -129,17 +210,58 @@ func (j *JWTService) ValidateAccessToken(tokenString string) (*contracts.AccessT
 	}
 
 	return &contracts.AccessTokenClaims{
-		UserID:    int64(userID),
-		Email:     email,
-		TokenType: tokenType,
-		ExpiresAt: time.Unix(int64(exp), 0),
-		IssuedAt:  time.Unix(int64(iat), 0),
-		NotBefore: time.Unix(int64(nbf), 0),
-		Issuer:    iss,
-		Subject:   sub,
+		TokenID:     tokenID,
+		UserID:      int64(userID),
+		Email:       email,
+		Roles:       roles,
+		Permissions: permissions,
+		SessionID:   sessionID,
+		TokenType:   tokenType,
+		ExpiresAt:   time.Unix(int64(exp), 0),
+		IssuedAt:    timeFromNumericDate(iat),
+		NotBefore:   time.Unix(int64(nbf), 0),
+		Issuer:      iss,
+		Subject:     sub,
 	}, nil
 }
 
+// numericDate encodes t with microseconds. Access tokens carry a fractional
+// iat so that revocation watermarks set within the same second as a token was
+// issued still tell which came first.
+func numericDate(t time.Time) float64 {
+	return float64(t.UnixMicro()) / 1e6
+}
+
+func timeFromNumericDate(value float64) time.Time {
+	return time.UnixMicro(int64(math.Round(value * 1e6)))
+}
+
+// accessTokenKey resolves the verification key of an access token. With
+// asymmetric keys configured, HS256 tokens issued before the switch are only
+// accepted until hs256AcceptUntil.
+func (j *JWTService) accessTokenKey(token *jwt.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		if j.keys != nil && !time.Now().Before(j.hs256AcceptUntil) {
+			return nil, errors.ErrUnexpectedSigningMethod
+		}
+		return []byte(j.cfg.AccessTokenSecret), nil
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodEd25519:
+		if j.keys == nil {
+			return nil, errors.ErrUnexpectedSigningMethod
+		}
+		return j.keys.VerificationKey(token)
+	}
+	return nil, errors.ErrUnexpectedSigningMethod
+}
+
+func (j *JWTService) PublicKeys() []contracts.JSONWebKey {
+	if j.keys == nil {
+		return []contracts.JSONWebKey{}
+	}
+	return j.keys.PublicKeys()
+}
+
 func (j *JWTService) ValidateRefreshToken(tokenString string) (*contracts.RefreshTokenClaims, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
@@ -206,3 +328,86 @@ func (j *JWTService) ValidateRefreshToken(tokenString string) (*contracts.Refres
 		Subject:   sub,
 	}, nil
 }
+
+// stringSliceClaim converts a decoded JSON array claim into a string slice.
+// A missing claim is treated as an empty slice.
+func stringSliceClaim(value any) ([]string, bool) {
+	if value == nil {
+		return []string{}, true
+	}
+
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	result := make([]string, len(items))
+	for i, item := range items {
+		str, ok := item.(string)
+		if !ok {
+			return nil, false
+		}
+		result[i] = str
+	}
+
+	return result, true
+}
+
+func (j *JWTService) ValidateMFAToken(tokenString string) (*contracts.MFATokenClaims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.ErrUnexpectedSigningMethod
+		}
+		return []byte(j.cfg.AccessTokenSecret), nil
+	})
+
+	if err != nil {
+		return nil, errors.ErrTokenInvalid
+	}
+
+	if !token.Valid {
+		return nil, errors.ErrTokenInvalid
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, errors.ErrInvalidClaims
+	}
+
+	tokenType, ok := claims["token_type"].(string)
+	if !ok || tokenType != "mfa" {
+		return nil, errors.ErrTokenInvalid
+	}
+
+	userID, ok := claims["user_id"].(float64)
+	if !ok {
+		return nil, errors.ErrInvalidClaims
+	}
+
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return nil, errors.ErrInvalidClaims
+	}
+
+	iat, ok := claims["iat"].(float64)
+	if !ok {
+		return nil, errors.ErrInvalidClaims
+	}
+
+	return &contracts.MFATokenClaims{
+		UserID:    int64(userID),
+		TokenType: tokenType,
+		ExpiresAt: time.Unix(int64(exp), 0),
+		IssuedAt:  time.Unix(int64(iat), 0),
+	}, nil
+}
+
+// generateJTI returns a random token identifier. It also guarantees that two
+// tokens issued for the same user within the same second are distinct.
+func generateJTI() (string, error) {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(raw), nil
+}
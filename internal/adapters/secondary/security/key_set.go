@@ -0,0 +1,193 @@
+package security
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"go-gin-clean/internal/core/contracts"
+	"go-gin-clean/internal/core/domain/errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// KeySet holds the asymmetric keys used for access tokens. Every key is
+// identified by a kid taken from its file name. Only the active key signs new
+// tokens; all keys (including public-only ones) verify, which allows rotating
+// the signing key without invalidating tokens that are still in flight.
+//
+// Files in the key directory:
+//
+//	<kid>.pem      PKCS#8 / PKCS#1 private key (RSA or Ed25519)
+//	<kid>.pub.pem  PKIX public key of a retired key
+type KeySet struct {
+	activeKeyID string
+	signers     map[string]crypto.Signer
+	publicKeys  map[string]crypto.PublicKey
+}
+
+func LoadKeySet(dir, activeKeyID string) (*KeySet, error) {
+	keySet := &KeySet{
+		activeKeyID: activeKeyID,
+		signers:     make(map[string]crypto.Signer),
+		publicKeys:  make(map[string]crypto.PublicKey),
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key directory: %v", err)
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".pem") {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key %s: %v", name, err)
+		}
+
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("key %s is not PEM encoded", name)
+		}
+
+		if strings.HasSuffix(name, ".pub.pem") {
+			kid := strings.TrimSuffix(name, ".pub.pem")
+			publicKey, err := parsePublicKey(block)
+			if err != nil {
+				return nil, fmt.Errorf("key %s: %v", name, err)
+			}
+			keySet.publicKeys[kid] = publicKey
+			continue
+		}
+
+		kid := strings.TrimSuffix(name, ".pem")
+		signer, err := parsePrivateKey(block)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %v", name, err)
+		}
+		keySet.signers[kid] = signer
+		keySet.publicKeys[kid] = signer.Public()
+	}
+
+	if _, ok := keySet.signers[activeKeyID]; !ok {
+		return nil, fmt.Errorf("active signing key %q not found in %s", activeKeyID, dir)
+	}
+
+	return keySet, nil
+}
+
+func parsePrivateKey(block *pem.Block) (crypto.Signer, error) {
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		switch k := key.(type) {
+		case *rsa.PrivateKey:
+			return k, nil
+		case ed25519.PrivateKey:
+			return k, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported private key type %q", block.Type)
+}
+
+func parsePublicKey(block *pem.Block) (crypto.PublicKey, error) {
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	switch key.(type) {
+	case *rsa.PublicKey, ed25519.PublicKey:
+		return key, nil
+	}
+	return nil, fmt.Errorf("unsupported public key type %T", key)
+}
+
+func signingMethodFor(key crypto.PublicKey) jwt.SigningMethod {
+	switch key.(type) {
+	case *rsa.PublicKey:
+		return jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA
+	}
+	return nil
+}
+
+// Sign signs the claims with the active key and sets the kid header.
+func (k *KeySet) Sign(claims jwt.Claims) (string, error) {
+	signer := k.signers[k.activeKeyID]
+
+	token := jwt.NewWithClaims(signingMethodFor(signer.Public()), claims)
+	token.Header["kid"] = k.activeKeyID
+
+	return token.SignedString(signer)
+}
+
+// VerificationKey returns the public key for kid, making sure the token was
+// signed with the algorithm that belongs to that key.
+func (k *KeySet) VerificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	publicKey, ok := k.publicKeys[kid]
+	if !ok {
+		return nil, errors.ErrTokenInvalid
+	}
+
+	method := signingMethodFor(publicKey)
+	if method == nil || method.Alg() != token.Method.Alg() {
+		return nil, errors.ErrUnexpectedSigningMethod
+	}
+
+	return publicKey, nil
+}
+
+// PublicKeys returns every verification key in JWK form, sorted by kid.
+func (k *KeySet) PublicKeys() []contracts.JSONWebKey {
+	kids := make([]string, 0, len(k.publicKeys))
+	for kid := range k.publicKeys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	keys := make([]contracts.JSONWebKey, 0, len(kids))
+	for _, kid := range kids {
+		switch publicKey := k.publicKeys[kid].(type) {
+		case *rsa.PublicKey:
+			keys = append(keys, contracts.JSONWebKey{
+				KeyType:   "RSA",
+				KeyID:     kid,
+				Use:       "sig",
+				Algorithm: jwt.SigningMethodRS256.Alg(),
+				Modulus:   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+				Exponent:  base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			keys = append(keys, contracts.JSONWebKey{
+				KeyType:   "OKP",
+				KeyID:     kid,
+				Use:       "sig",
+				Algorithm: jwt.SigningMethodEdDSA.Alg(),
+				Curve:     "Ed25519",
+				X:         base64.RawURLEncoding.EncodeToString(publicKey),
+			})
+		}
+	}
+
+	return keys
+}
@@ -0,0 +1,44 @@
+package security
+
+import (
+	"go-gin-clean/internal/core/ports"
+	"go-gin-clean/pkg/config"
+)
+
+// PasswordHasher hashes new passwords with the configured algorithm and
+// verifies hashes produced by any supported algorithm, detected from the
+// encoded hash prefix.
+type PasswordHasher struct {
+	algorithm string
+	bcrypt    *BcryptService
+	argon2id  *Argon2idService
+}
+
+func NewPasswordHasher(cfg *config.PasswordConfig) ports.PasswordHasher {
+	return &PasswordHasher{
+		algorithm: cfg.Algorithm,
+		bcrypt:    NewBcryptService(cfg.BcryptCost),
+		argon2id:  NewArgon2idService(&cfg.Argon2),
+	}
+}
+
+func (h *PasswordHasher) HashPassword(password string) (string, error) {
+	if h.algorithm == config.PasswordAlgorithmBcrypt {
+		return h.bcrypt.HashPassword(password)
+	}
+	return h.argon2id.HashPassword(password)
+}
+
+func (h *PasswordHasher) ValidatePassword(password, hashedPassword string) error {
+	if isArgon2idHash(hashedPassword) {
+		return h.argon2id.ValidatePassword(password, hashedPassword)
+	}
+	return h.bcrypt.ValidatePassword(password, hashedPassword)
+}
+
+func (h *PasswordHasher) NeedsRehash(hashedPassword string) bool {
+	if h.algorithm == config.PasswordAlgorithmBcrypt {
+		return isArgon2idHash(hashedPassword) || h.bcrypt.NeedsRehash(hashedPassword)
+	}
+	return !isArgon2idHash(hashedPassword) || h.argon2id.NeedsRehash(hashedPassword)
+}
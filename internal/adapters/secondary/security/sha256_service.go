@@ -0,0 +1,30 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"go-gin-clean/internal/core/ports"
+)
+
+// SHA256Service hashes high-entropy tokens (refresh tokens, one-time links)
+// for storage and lookup. It must not be used for passwords.
+type SHA256Service struct{}
+
+func NewSHA256Service() ports.HashService {
+	return &SHA256Service{}
+}
+
+func (s *SHA256Service) Hash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *SHA256Service) GenerateToken(size int) (string, error) {
+	raw := make([]byte, size)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
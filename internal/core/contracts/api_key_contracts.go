@@ -0,0 +1,38 @@
+package contracts
+
+import "time"
+
+type (
+	CreateAPIKeyRequest struct {
+		Name      string
+		Scopes    []string
+		ExpiresAt *time.Time
+	}
+
+	// CreateAPIKeyResponse carries the plaintext key, which is only ever
+	// returned once at creation time.
+	CreateAPIKeyResponse struct {
+		Key    string
+		APIKey APIKeyInfo
+	}
+
+	APIKeyInfo struct {
+		ID         int64
+		Name       string
+		Prefix     string
+		Scopes     []string
+		ExpiresAt  *time.Time
+		LastUsedAt *time.Time
+		CreatedAt  time.Time
+	}
+
+	// APIKeyPrincipal is the identity an authenticated API key acts as.
+	// Permissions are the key scopes the user still holds.
+	APIKeyPrincipal struct {
+		KeyID       int64
+		UserID      int64
+		Email       string
+		Roles       []string
+		Permissions []string
+	}
+)
@@ -0,0 +1,51 @@
+package contracts
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+type (
+	// AuditActor describes who performed a request. It travels in the request
+	// context so use cases can attribute audit entries without every method
+	// taking it as a parameter.
+	AuditActor struct {
+		UserID    *int64
+		IPAddress string
+		UserAgent string
+	}
+
+	AuditLogFilter struct {
+		ActorID  *int64
+		TargetID *int64
+		Action   string
+		From     *time.Time
+		To       *time.Time
+	}
+
+	AuditLogInfo struct {
+		ID        int64
+		ActorID   *int64
+		TargetID  *int64
+		Action    string
+		IPAddress string
+		UserAgent string
+		Changes   json.RawMessage
+		Details   json.RawMessage
+		CreatedAt time.Time
+	}
+)
+
+type auditActorKey struct{}
+
+func WithAuditActor(ctx context.Context, actor AuditActor) context.Context {
+	return context.WithValue(ctx, auditActorKey{}, actor)
+}
+
+// AuditActorFromContext returns the actor stored in ctx, or an empty actor
+// for requests made outside of HTTP handlers.
+func AuditActorFromContext(ctx context.Context) AuditActor {
+	actor, _ := ctx.Value(auditActorKey{}).(AuditActor)
+	return actor
+}
@@ -0,0 +1,12 @@
+package contracts
+
+type JSONWebKey struct {
+	KeyType   string
+	KeyID     string
+	Use       string
+	Algorithm string
+	Modulus   string
+	Exponent  string
+	Curve     string
+	X         string
+}
@@ -0,0 +1,25 @@
+package contracts
+
+type (
+	// ExternalIdentity is the verified identity returned by an OAuth/OIDC provider.
+	ExternalIdentity struct {
+		Provider      string
+		Subject       string
+		Email         string
+		EmailVerified bool
+		Name          string
+	}
+
+	OAuthStartResponse struct {
+		AuthURL     string
+		StateCookie string
+	}
+
+	OAuthCallbackRequest struct {
+		Provider    string
+		Code        string
+		State       string
+		StateCookie string
+		Client      ClientInfo
+	}
+)
@@ -29,6 +29,46 @@ func NewPaginationResponse[T any](data []T, page, perPage, total int) *Paginatio
 	}
 }
 
+// CursorRequest asks for the page after or before an opaque cursor returned
+// by a previous page. An empty cursor starts at the beginning.
+type CursorRequest struct {
+	Cursor       string
+	Limit        int
+	IncludeTotal bool
+}
+
+// CursorPage says where a keyset page sits. A cursor is empty when there is
+// nothing further in that direction; Total is only set when requested.
+type CursorPage struct {
+	NextCursor string
+	PrevCursor string
+	Total      *int64
+}
+
+type CursorResponse[T any] struct {
+	Data       []T
+	Limit      int
+	NextCursor string
+	PrevCursor string
+	Total      *int
+}
+
+func NewCursorResponse[T any](data []T, limit int, page *CursorPage) *CursorResponse[T] {
+	resp := &CursorResponse[T]{
+		Data:       data,
+		Limit:      limit,
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+	}
+
+	if page.Total != nil {
+		total := int(*page.Total)
+		resp.Total = &total
+	}
+
+	return resp
+}
+
 func Offset(page, pageSize int) int {
 	if page < 1 {
 		page = 1
@@ -0,0 +1,181 @@
+package contracts
+
+import (
+	"go-gin-clean/internal/core/domain/errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type FilterOperator string
+
+const (
+	FilterEq   FilterOperator = "eq"
+	FilterNe   FilterOperator = "ne"
+	FilterGt   FilterOperator = "gt"
+	FilterGte  FilterOperator = "gte"
+	FilterLt   FilterOperator = "lt"
+	FilterLte  FilterOperator = "lte"
+	FilterLike FilterOperator = "like"
+	FilterIn   FilterOperator = "in"
+)
+
+type FieldType string
+
+const (
+	FieldString FieldType = "string"
+	FieldEnum   FieldType = "enum"
+	FieldBool   FieldType = "bool"
+	FieldInt    FieldType = "int"
+	FieldTime   FieldType = "time"
+)
+
+// operatorsByType lists the filter operators each field type accepts.
+var operatorsByType = map[FieldType][]FilterOperator{
+	FieldString: {FilterEq, FilterNe, FilterLike, FilterIn},
+	FieldEnum:   {FilterEq, FilterNe, FilterIn},
+	FieldBool:   {FilterEq, FilterNe},
+	FieldInt:    {FilterEq, FilterNe, FilterGt, FilterGte, FilterLt, FilterLte, FilterIn},
+	FieldTime:   {FilterEq, FilterNe, FilterGt, FilterGte, FilterLt, FilterLte},
+}
+
+type Filter struct {
+	Field    string
+	Operator FilterOperator
+	Value    string
+}
+
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// QuerySpec is a list request as sent by the client. Field names are the
+// public ones and are only mapped to columns through a QueryFields
+// whitelist.
+type QuerySpec struct {
+	Search  string
+	Filters []Filter
+	Sort    []Sort
+}
+
+// QueryField maps a public field name to a column and says what clients may
+// do with it. Nullable columns cannot be keyset sort keys, since rows with
+// NULL would never match the cursor condition.
+type QueryField struct {
+	Column     string
+	Type       FieldType
+	Values     []string
+	Nullable   bool
+	Filterable bool
+	Sortable   bool
+}
+
+// QueryFields is the per-entity whitelist of filterable and sortable fields,
+// keyed by the name used in query parameters.
+type QueryFields map[string]QueryField
+
+// Validate rejects filters and sort keys that are not whitelisted, use an
+// operator the field type does not support or carry an unparsable value.
+func (f QueryFields) Validate(spec *QuerySpec) error {
+	if spec == nil {
+		return nil
+	}
+
+	for _, filter := range spec.Filters {
+		if _, err := f.FilterValue(filter); err != nil {
+			return err
+		}
+	}
+
+	for _, sort := range spec.Sort {
+		field, ok := f[sort.Field]
+		if !ok || !field.Sortable {
+			return errors.NewQueryError("sort", "cannot sort by "+sort.Field)
+		}
+	}
+
+	return nil
+}
+
+// ValidateKeyset is Validate for cursor pagination, which additionally
+// needs every sort key to be non-nullable.
+func (f QueryFields) ValidateKeyset(spec *QuerySpec) error {
+	if err := f.Validate(spec); err != nil {
+		return err
+	}
+
+	if spec == nil {
+		return nil
+	}
+
+	for _, sort := range spec.Sort {
+		if f[sort.Field].Nullable {
+			return errors.NewQueryError("sort", "cannot sort by "+sort.Field+" when paginating with a cursor")
+		}
+	}
+
+	return nil
+}
+
+// FilterValue returns the filter value converted to the field type, or a
+// slice of converted values for FilterIn.
+func (f QueryFields) FilterValue(filter Filter) (any, error) {
+	field, ok := f[filter.Field]
+	if !ok || !field.Filterable {
+		return nil, errors.NewQueryError(filter.Field, "cannot filter by this field")
+	}
+
+	if !slices.Contains(operatorsByType[field.Type], filter.Operator) {
+		return nil, errors.NewQueryError(filter.Field, "operator "+string(filter.Operator)+" is not supported")
+	}
+
+	if filter.Operator != FilterIn {
+		return field.parse(filter.Field, filter.Value)
+	}
+
+	var values []any
+	for _, raw := range strings.Split(filter.Value, ",") {
+		value, err := field.parse(filter.Field, strings.TrimSpace(raw))
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+func (f QueryField) parse(name, raw string) (any, error) {
+	switch f.Type {
+	case FieldEnum:
+		if !slices.Contains(f.Values, raw) {
+			return nil, errors.NewQueryError(name, "must be one of "+strings.Join(f.Values, ", "))
+		}
+		return raw, nil
+	case FieldBool:
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, errors.NewQueryError(name, "must be true or false")
+		}
+		return value, nil
+	case FieldInt:
+		value, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, errors.NewQueryError(name, "must be an integer")
+		}
+		return value, nil
+	case FieldTime:
+		if value, err := time.Parse(time.RFC3339, raw); err == nil {
+			return value, nil
+		}
+		value, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			return nil, errors.NewQueryError(name, "must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+		}
+		return value, nil
+	default:
+		return raw, nil
+	}
+}
@@ -14,17 +14,64 @@ type (
 		Avatar   string
 		Gender   enums.Gender
 		IsActive bool
+		Roles    []string
+
+		PendingEmail     string
+		TwoFactorEnabled bool
+		DeletedAt        *time.Time
+
+		// Match is only set on search results.
+		Match *SearchMatch
+	}
+
+	// SearchMatch names the field a search term was found in, with the term
+	// wrapped in <mark> in an HTML-escaped copy of the field.
+	SearchMatch struct {
+		Field     string
+		Highlight string
+	}
+
+	ClientInfo struct {
+		UserAgent string
+		IPAddress string
 	}
 
 	LoginRequest struct {
 		Email    string
 		Password string
+		Client   ClientInfo
 	}
 
 	LoginResponse struct {
 		AccessToken  string
 		RefreshToken string
 		User         UserInfo
+		MFARequired  bool
+		MFAToken     string
+	}
+
+	TwoFactorLoginRequest struct {
+		MFAToken string
+		Code     string
+		Client   ClientInfo
+	}
+
+	TwoFactorSetupResponse struct {
+		Secret string
+		URI    string
+	}
+
+	TwoFactorEnableRequest struct {
+		Code string
+	}
+
+	TwoFactorEnableResponse struct {
+		RecoveryCodes []string
+	}
+
+	TwoFactorDisableRequest struct {
+		Password string
+		Code     string
 	}
 
 	RegisterRequest struct {
@@ -33,6 +80,16 @@ type (
 		Password string
 	}
 
+	MagicLinkLoginRequest struct {
+		Token  string
+		Client ClientInfo
+	}
+
+	RefreshTokenRequest struct {
+		RefreshToken string
+		Client       ClientInfo
+	}
+
 	RefreshTokenResponse struct {
 		AccessToken  string
 		RefreshToken string
@@ -48,11 +105,17 @@ type (
 		NewPassword string
 	}
 
+	ChangeEmailRequest struct {
+		NewEmail string
+		Password string
+	}
+
 	CreateUserRequest struct {
 		Name     string
 		Email    string
 		Password string
 		Gender   enums.Gender
+		Roles    []string
 	}
 
 	UpdateUserRequest struct {
@@ -61,6 +124,20 @@ type (
 		Avatar *FileUpload
 	}
 
+	SessionInfo struct {
+		ID         string
+		UserAgent  string
+		IPAddress  string
+		CreatedAt  time.Time
+		LastUsedAt time.Time
+		ExpiresAt  time.Time
+		Current    bool
+	}
+
+	AssignRolesRequest struct {
+		Roles []string
+	}
+
 	FileUpload struct {
 		Filename string
 		Size     int64
@@ -68,14 +145,25 @@ type (
 	}
 
 	AccessTokenClaims struct {
+		TokenID     string
+		UserID      int64
+		Email       string
+		Roles       []string
+		Permissions []string
+		SessionID   string
+		TokenType   string
+		ExpiresAt   time.Time
+		IssuedAt    time.Time
+		NotBefore   time.Time
+		Issuer      string
+		Subject     string
+	}
+
+	MFATokenClaims struct {
 		UserID    int64
-		Email     string
 		TokenType string
 		ExpiresAt time.Time
 		IssuedAt  time.Time
-		NotBefore time.Time
-		Issuer    string
-		Subject   string
 	}
 
 	RefreshTokenClaims struct {
@@ -88,3 +176,16 @@ type (
 		Subject   string
 	}
 )
+
+// UserQueryFields whitelists the fields GET /users can be filtered and
+// sorted by.
+var UserQueryFields = QueryFields{
+	"id":                 {Column: "id", Type: FieldInt, Filterable: true, Sortable: true},
+	"name":               {Column: "name", Type: FieldString, Filterable: true, Sortable: true},
+	"email":              {Column: "email", Type: FieldString, Filterable: true, Sortable: true},
+	"gender":             {Column: "gender", Type: FieldEnum, Values: []string{enums.Male.String(), enums.Female.String(), enums.Unknown.String()}, Nullable: true, Filterable: true, Sortable: true},
+	"is_active":          {Column: "is_active", Type: FieldBool, Filterable: true, Sortable: true},
+	"two_factor_enabled": {Column: "two_factor_enabled", Type: FieldBool, Filterable: true},
+	"created_at":         {Column: "created_at", Type: FieldTime, Filterable: true, Sortable: true},
+	"updated_at":         {Column: "updated_at", Type: FieldTime, Filterable: true, Sortable: true},
+}
@@ -0,0 +1,54 @@
+package entities
+
+import (
+	"strings"
+	"time"
+)
+
+// APIKey is a long-lived personal credential for machine clients. Only the
+// SHA-256 hash of the key is stored; Prefix is kept to identify it in lists.
+type APIKey struct {
+	ID         int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID     int64      `json:"user_id" gorm:"not null;index"`
+	Name       string     `json:"name" gorm:"type:varchar(100);not null"`
+	Prefix     string     `json:"prefix" gorm:"type:varchar(32);not null"`
+	KeyHash    string     `json:"-" gorm:"type:varchar(64);not null;uniqueIndex"`
+	Scopes     string     `json:"scopes" gorm:"type:text;default:''"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" gorm:"type:timestamp;default:NULL"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" gorm:"type:timestamp;default:NULL"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" gorm:"type:timestamp;default:NULL"`
+	User       User       `json:"user" gorm:"foreignKey:UserID;references:ID"`
+
+	Audit
+}
+
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+func NewAPIKey(userID int64, name, prefix, keyHash string, scopes []string, expiresAt *time.Time) *APIKey {
+	return &APIKey{
+		UserID:    userID,
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   keyHash,
+		Scopes:    strings.Join(scopes, " "),
+		ExpiresAt: expiresAt,
+	}
+}
+
+func (k *APIKey) ScopeList() []string {
+	return strings.Fields(k.Scopes)
+}
+
+func (k *APIKey) IsExpired() bool {
+	return k.ExpiresAt != nil && time.Now().After(*k.ExpiresAt)
+}
+
+func (k *APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
+
+func (k *APIKey) IsValid() bool {
+	return !k.IsRevoked() && !k.IsExpired()
+}
@@ -0,0 +1,36 @@
+package entities
+
+import "time"
+
+// AuditLog records a security-relevant action. Changes holds a JSON object
+// mapping each changed field to its previous and new value, Details any
+// further context as a JSON object.
+type AuditLog struct {
+	ID        int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	ActorID   *int64    `json:"actor_id,omitempty" gorm:"index;default:NULL"`
+	TargetID  *int64    `json:"target_id,omitempty" gorm:"index;default:NULL"`
+	Action    string    `json:"action" gorm:"not null;index"`
+	IPAddress string    `json:"ip_address" gorm:"type:varchar(45);default:''"`
+	UserAgent string    `json:"user_agent" gorm:"type:text;default:''"`
+	Changes   string    `json:"changes" gorm:"type:text;default:''"`
+	Details   string    `json:"details" gorm:"type:text;default:''"`
+	CreatedAt time.Time `json:"created_at" gorm:"column:created_at;type:timestamp;default:CURRENT_TIMESTAMP;index"`
+}
+
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+func NewAuditLog(actorID, targetID *int64, action, details string) *AuditLog {
+	return &AuditLog{
+		ActorID:  actorID,
+		TargetID: targetID,
+		Action:   action,
+		Details:  details,
+	}
+}
+
+func (al *AuditLog) SetClient(ipAddress, userAgent string) {
+	al.IPAddress = ipAddress
+	al.UserAgent = userAgent
+}
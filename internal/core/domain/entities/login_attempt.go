@@ -0,0 +1,71 @@
+package entities
+
+import "time"
+
+// LoginAttempt tracks consecutive failed logins for a throttling key such as
+// an email address or a client IP.
+type LoginAttempt struct {
+	Key           string     `json:"key" gorm:"column:attempt_key;primaryKey"`
+	Failures      int        `json:"failures" gorm:"default:0;not null"`
+	LockCount     int        `json:"lock_count" gorm:"default:0;not null"`
+	LockedUntil   *time.Time `json:"locked_until,omitempty" gorm:"type:timestamp;default:NULL"`
+	LastFailureAt time.Time  `json:"last_failure_at" gorm:"type:timestamp"`
+}
+
+func (LoginAttempt) TableName() string {
+	return "login_attempts"
+}
+
+func NewLoginAttempt(key string) *LoginAttempt {
+	return &LoginAttempt{
+		Key: key,
+	}
+}
+
+func (la *LoginAttempt) IsLocked() bool {
+	return la.LockedUntil != nil && time.Now().Before(*la.LockedUntil)
+}
+
+// IsStale reports whether the counter no longer affects anything: it is not
+// locked and its last failure happened before lastFailureBefore.
+func (la *LoginAttempt) IsStale(lastFailureBefore time.Time) bool {
+	return !la.IsLocked() && la.LastFailureAt.Before(lastFailureBefore)
+}
+
+func (la *LoginAttempt) RetryAfter() time.Duration {
+	if !la.IsLocked() {
+		return 0
+	}
+	return time.Until(*la.LockedUntil)
+}
+
+// RegisterFailure counts a failed attempt. Failures older than window start a
+// new streak. Once maxAttempts is reached the key is locked for baseLockout,
+// doubling with every further lock up to maxLockout.
+func (la *LoginAttempt) RegisterFailure(maxAttempts int, window, baseLockout, maxLockout time.Duration) {
+	now := time.Now()
+
+	if !la.LastFailureAt.IsZero() && now.Sub(la.LastFailureAt) > window {
+		la.Failures = 0
+	}
+
+	la.Failures++
+	la.LastFailureAt = now
+
+	if la.Failures < maxAttempts {
+		return
+	}
+
+	lockout := baseLockout
+	for i := 0; i < la.LockCount && lockout < maxLockout; i++ {
+		lockout *= 2
+	}
+	if lockout > maxLockout {
+		lockout = maxLockout
+	}
+
+	lockedUntil := now.Add(lockout)
+	la.LockedUntil = &lockedUntil
+	la.LockCount++
+	la.Failures = 0
+}
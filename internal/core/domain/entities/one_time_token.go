@@ -0,0 +1,46 @@
+package entities
+
+import (
+	"go-gin-clean/internal/core/domain/enums"
+	"time"
+)
+
+// OneTimeToken backs emailed links (email verification, password reset).
+// Only the SHA-256 hash of the token is stored.
+type OneTimeToken struct {
+	ID         int64              `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID     int64              `json:"user_id" gorm:"not null;index:idx_one_time_tokens_user_purpose"`
+	Purpose    enums.TokenPurpose `json:"purpose" gorm:"type:varchar(32);not null;index:idx_one_time_tokens_user_purpose"`
+	TokenHash  string             `json:"-" gorm:"type:varchar(64);not null;uniqueIndex"`
+	ExpiresAt  time.Time          `json:"expires_at" gorm:"type:timestamp;not null"`
+	ConsumedAt *time.Time         `json:"consumed_at,omitempty" gorm:"type:timestamp;default:NULL"`
+	User       User               `json:"user" gorm:"foreignKey:UserID;references:ID"`
+
+	Audit
+}
+
+func (OneTimeToken) TableName() string {
+	return "one_time_tokens"
+}
+
+func NewOneTimeToken(userID int64, purpose enums.TokenPurpose, tokenHash string, expiresAt time.Time) *OneTimeToken {
+	return &OneTimeToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: tokenHash,
+		ExpiresAt: expiresAt,
+	}
+}
+
+func (t *OneTimeToken) IsConsumed() bool {
+	return t.ConsumedAt != nil
+}
+
+func (t *OneTimeToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
+
+func (t *OneTimeToken) MarkAsConsumed() {
+	now := time.Now()
+	t.ConsumedAt = &now
+}
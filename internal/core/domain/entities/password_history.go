@@ -0,0 +1,23 @@
+package entities
+
+import "time"
+
+type PasswordHistory struct {
+	ID           int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID       int64     `json:"user_id" gorm:"not null;index"`
+	PasswordHash string    `json:"-" gorm:"not null"`
+	CreatedAt    time.Time `json:"created_at" gorm:"type:timestamp;default:CURRENT_TIMESTAMP"`
+	User         User      `json:"user" gorm:"foreignKey:UserID;references:ID"`
+}
+
+func (PasswordHistory) TableName() string {
+	return "password_histories"
+}
+
+func NewPasswordHistory(userID int64, passwordHash string) *PasswordHistory {
+	return &PasswordHistory{
+		UserID:       userID,
+		PasswordHash: passwordHash,
+		CreatedAt:    time.Now(),
+	}
+}
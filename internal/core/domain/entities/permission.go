@@ -0,0 +1,20 @@
+package entities
+
+type Permission struct {
+	ID          int64  `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name        string `json:"name" gorm:"uniqueIndex;not null"`
+	Description string `json:"description" gorm:"default:''"`
+
+	Audit
+}
+
+func (Permission) TableName() string {
+	return "permissions"
+}
+
+func NewPermission(name, description string) *Permission {
+	return &Permission{
+		Name:        name,
+		Description: description,
+	}
+}
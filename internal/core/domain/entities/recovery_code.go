@@ -0,0 +1,33 @@
+package entities
+
+import "time"
+
+type RecoveryCode struct {
+	ID       int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID   int64      `json:"user_id" gorm:"not null;index"`
+	CodeHash string     `json:"-" gorm:"not null"`
+	UsedAt   *time.Time `json:"used_at,omitempty" gorm:"type:timestamp;default:NULL"`
+	User     User       `json:"user" gorm:"foreignKey:UserID;references:ID"`
+
+	Audit
+}
+
+func (RecoveryCode) TableName() string {
+	return "recovery_codes"
+}
+
+func NewRecoveryCode(userID int64, codeHash string) *RecoveryCode {
+	return &RecoveryCode{
+		UserID:   userID,
+		CodeHash: codeHash,
+	}
+}
+
+func (rc *RecoveryCode) IsUsed() bool {
+	return rc.UsedAt != nil
+}
+
+func (rc *RecoveryCode) MarkAsUsed() {
+	now := time.Now()
+	rc.UsedAt = &now
+}
@@ -3,12 +3,20 @@ package entities
 import "time"
 
 type RefreshToken struct {
-	ID        int64     `json:"id" gorm:"primaryKey;autoIncrement"`
-	UserID    int64     `json:"user_id" gorm:"not null"`
-	Token     string    `json:"token" gorm:"not null;unique"`
-	ExpiryAt  time.Time `json:"expiry_at" gorm:"not null;type:timestamp"`
-	IsRevoked bool      `json:"is_revoked" gorm:"default:false;not null"`
-	User      User      `json:"user" gorm:"foreignKey:UserID;references:ID"`
+	ID        int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID    int64      `json:"user_id" gorm:"not null"`
+	Token     string     `json:"token" gorm:"not null;unique"`
+	FamilyID  string     `json:"family_id" gorm:"not null;index"`
+	ParentID  *int64     `json:"parent_id,omitempty" gorm:"default:NULL"`
+	ExpiryAt  time.Time  `json:"expiry_at" gorm:"not null;type:timestamp"`
+	IsRevoked bool       `json:"is_revoked" gorm:"default:false;not null"`
+	RotatedAt *time.Time `json:"rotated_at,omitempty" gorm:"type:timestamp;default:NULL"`
+	User      User       `json:"user" gorm:"foreignKey:UserID;references:ID"`
+
+	UserAgent        string    `json:"user_agent" gorm:"default:''"`
+	IPAddress        string    `json:"ip_address" gorm:"default:''"`
+	SessionStartedAt time.Time `json:"session_started_at" gorm:"type:timestamp;default:CURRENT_TIMESTAMP"`
+	LastUsedAt       time.Time `json:"last_used_at" gorm:"type:timestamp;default:CURRENT_TIMESTAMP"`
 
 	Audit
 }
@@ -17,16 +25,29 @@ func (RefreshToken) TableName() string {
 	return "refresh_tokens"
 }
 
-func NewRefreshToken(userID int64, token string, expiryAt time.Time, isRevoked bool, user User) *RefreshToken {
+// NewRefreshToken creates a token in the given family. parentID is the token
+// it was rotated from, or nil for the first token issued at login.
+func NewRefreshToken(userID int64, token, familyID string, parentID *int64, expiryAt time.Time, isRevoked bool, user User) *RefreshToken {
 	return &RefreshToken{
 		UserID:    userID,
 		Token:     token,
+		FamilyID:  familyID,
+		ParentID:  parentID,
 		ExpiryAt:  expiryAt,
 		IsRevoked: isRevoked,
 		User:      user,
 	}
 }
 
+// SetSessionMetadata records the device the token was issued to. The session
+// start time is carried over from the first token of the family on rotation.
+func (rf *RefreshToken) SetSessionMetadata(userAgent, ipAddress string, sessionStartedAt time.Time) {
+	rf.UserAgent = userAgent
+	rf.IPAddress = ipAddress
+	rf.SessionStartedAt = sessionStartedAt
+	rf.LastUsedAt = time.Now()
+}
+
 func (rf *RefreshToken) GetID() int64 {
 	return rf.ID
 }
@@ -47,6 +68,19 @@ func (rf *RefreshToken) Revoke() {
 	rf.IsRevoked = true
 }
 
+// MarkAsRotated revokes the token because a successor has been issued from it.
+func (rf *RefreshToken) MarkAsRotated() {
+	now := time.Now()
+	rf.IsRevoked = true
+	rf.RotatedAt = &now
+}
+
+// IsRotated reports whether the token was already exchanged for a new one.
+// Presenting a rotated token again means it has leaked.
+func (rf *RefreshToken) IsRotated() bool {
+	return rf.RotatedAt != nil
+}
+
 func (rf *RefreshToken) IsValid() bool {
 	return !rf.IsRevoked && !rf.IsExpired()
 }
@@ -0,0 +1,28 @@
+package entities
+
+import "time"
+
+// RevokedToken is a denylist entry for access tokens. The key identifies a
+// single token ("jti:<id>"), a session ("sid:<id>") or, together with
+// IssuedBefore, every token issued to a user before a point in time
+// ("user:<id>"). Entries are only kept until the tokens they cover expire.
+type RevokedToken struct {
+	Key          string     `json:"key" gorm:"column:revocation_key;type:varchar(128);primaryKey"`
+	IssuedBefore *time.Time `json:"issued_before,omitempty" gorm:"type:timestamp;default:NULL"`
+	ExpiresAt    time.Time  `json:"expires_at" gorm:"type:timestamp;not null;index"`
+}
+
+func (RevokedToken) TableName() string {
+	return "revoked_tokens"
+}
+
+func NewRevokedToken(key string, expiresAt time.Time) *RevokedToken {
+	return &RevokedToken{
+		Key:       key,
+		ExpiresAt: expiresAt,
+	}
+}
+
+func (rt *RevokedToken) IsExpired() bool {
+	return !time.Now().Before(rt.ExpiresAt)
+}
@@ -0,0 +1,31 @@
+package entities
+
+type Role struct {
+	ID          int64        `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name        string       `json:"name" gorm:"uniqueIndex;not null"`
+	Description string       `json:"description" gorm:"default:''"`
+	Permissions []Permission `json:"permissions" gorm:"many2many:role_permissions"`
+
+	Audit
+}
+
+func (Role) TableName() string {
+	return "roles"
+}
+
+func NewRole(name, description string, permissions []Permission) *Role {
+	return &Role{
+		Name:        name,
+		Description: description,
+		Permissions: permissions,
+	}
+}
+
+func (r *Role) HasPermission(permission string) bool {
+	for _, p := range r.Permissions {
+		if p.Name == permission {
+			return true
+		}
+	}
+	return false
+}
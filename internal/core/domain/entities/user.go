@@ -10,8 +10,16 @@ type User struct {
 	Email    string       `json:"email" gorm:"uniqueIndex;not null"`
 	Password string       `json:"password" gorm:"not null"`
 	Avatar   string       `json:"avatar" gorm:"default:''"`
-	Gender   enums.Gender `json:"gender" gorm:"type:gender;default:null"`
+	Gender   enums.Gender `json:"gender" gorm:"default:null"`
 	IsActive bool         `json:"is_active" gorm:"default:false;not null"`
+	Roles    []Role       `json:"roles" gorm:"many2many:user_roles"`
+
+	// PendingEmail holds a requested new address until it is confirmed.
+	PendingEmail string `json:"pending_email" gorm:"default:''"`
+
+	TwoFactorEnabled  bool   `json:"two_factor_enabled" gorm:"default:false;not null"`
+	TwoFactorSecret   string `json:"-" gorm:"default:''"`
+	TwoFactorLastStep int64  `json:"-" gorm:"default:0;not null"`
 
 	Audit
 }
@@ -43,6 +51,19 @@ func (u *User) Equals(other *User) bool {
 		u.Gender == other.Gender
 }
 
+func (u *User) RequestEmailChange(email string) {
+	u.PendingEmail = email
+}
+
+// ConfirmEmailChange switches to the pending address and returns the
+// previous one.
+func (u *User) ConfirmEmailChange() string {
+	previous := u.Email
+	u.Email = u.PendingEmail
+	u.PendingEmail = ""
+	return previous
+}
+
 func (u *User) ChangePassword(newPassword string) error {
 	u.Password = newPassword
 	return nil
@@ -65,3 +86,62 @@ func (u *User) Activate() {
 func (u *User) Deactivate() {
 	u.IsActive = false
 }
+
+// SetTwoFactorSecret stores a pending (encrypted) TOTP secret. Two-factor
+// authentication stays disabled until the secret is confirmed.
+func (u *User) SetTwoFactorSecret(secret string) {
+	u.TwoFactorSecret = secret
+	u.TwoFactorEnabled = false
+	u.TwoFactorLastStep = 0
+}
+
+func (u *User) EnableTwoFactor() {
+	u.TwoFactorEnabled = true
+}
+
+func (u *User) DisableTwoFactor() {
+	u.TwoFactorEnabled = false
+	u.TwoFactorSecret = ""
+	u.TwoFactorLastStep = 0
+}
+
+func (u *User) HasRole(name string) bool {
+	for _, role := range u.Roles {
+		if role.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (u *User) RoleNames() []string {
+	names := make([]string, len(u.Roles))
+	for i, role := range u.Roles {
+		names[i] = role.Name
+	}
+	return names
+}
+
+func (u *User) PermissionNames() []string {
+	seen := make(map[string]bool)
+	names := []string{}
+	for _, role := range u.Roles {
+		for _, permission := range role.Permissions {
+			if seen[permission.Name] {
+				continue
+			}
+			seen[permission.Name] = true
+			names = append(names, permission.Name)
+		}
+	}
+	return names
+}
+
+func (u *User) HasPermission(permission string) bool {
+	for _, role := range u.Roles {
+		if role.HasPermission(permission) {
+			return true
+		}
+	}
+	return false
+}
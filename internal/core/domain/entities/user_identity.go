@@ -0,0 +1,26 @@
+package entities
+
+// UserIdentity links a user to an account at an external OAuth/OIDC provider.
+type UserIdentity struct {
+	ID       int64  `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID   int64  `json:"user_id" gorm:"not null;index"`
+	Provider string `json:"provider" gorm:"type:varchar(64);not null;uniqueIndex:idx_user_identities_provider_subject"`
+	Subject  string `json:"subject" gorm:"type:varchar(255);not null;uniqueIndex:idx_user_identities_provider_subject"`
+	Email    string `json:"email" gorm:"default:''"`
+	User     User   `json:"user" gorm:"foreignKey:UserID;references:ID"`
+
+	Audit
+}
+
+func (UserIdentity) TableName() string {
+	return "user_identities"
+}
+
+func NewUserIdentity(userID int64, provider, subject, email string) *UserIdentity {
+	return &UserIdentity{
+		UserID:   userID,
+		Provider: provider,
+		Subject:  subject,
+		Email:    email,
+	}
+}
@@ -0,0 +1,27 @@
+package enums
+
+type AuditAction string
+
+const (
+	AuditActionRefreshTokenReuse    AuditAction = "refresh_token.reuse_detected"
+	AuditActionLogin                AuditAction = "user.login"
+	AuditActionLoginFailed          AuditAction = "user.login_failed"
+	AuditActionLogout               AuditAction = "user.logout"
+	AuditActionPasswordChanged      AuditAction = "user.password_changed"
+	AuditActionPasswordReset        AuditAction = "user.password_reset"
+	AuditActionEmailChangeRequested AuditAction = "user.email_change_requested"
+	AuditActionEmailChanged         AuditAction = "user.email_changed"
+	AuditActionTwoFactorEnabled     AuditAction = "user.two_factor_enabled"
+	AuditActionTwoFactorDisabled    AuditAction = "user.two_factor_disabled"
+	AuditActionUserCreated          AuditAction = "user.created"
+	AuditActionUserUpdated          AuditAction = "user.updated"
+	AuditActionUserDeleted          AuditAction = "user.deleted"
+	AuditActionUserRestored         AuditAction = "user.restored"
+	AuditActionUserUnlocked         AuditAction = "user.unlocked"
+	AuditActionRolesAssigned        AuditAction = "user.roles_assigned"
+)
+
+// String returns the string representation of audit action
+func (a AuditAction) String() string {
+	return string(a)
+}
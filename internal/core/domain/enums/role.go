@@ -0,0 +1,22 @@
+package enums
+
+type Permission string
+
+const (
+	PermissionUsersRead   Permission = "users:read"
+	PermissionUsersCreate Permission = "users:create"
+	PermissionUsersUpdate Permission = "users:update"
+	PermissionUsersDelete Permission = "users:delete"
+	PermissionRolesAssign Permission = "roles:assign"
+	PermissionAuditRead   Permission = "audit_logs:read"
+)
+
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+)
+
+// String returns the string representation of permission
+func (p Permission) String() string {
+	return string(p)
+}
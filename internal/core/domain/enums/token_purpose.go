@@ -0,0 +1,15 @@
+package enums
+
+type TokenPurpose string
+
+const (
+	TokenPurposeEmailVerification TokenPurpose = "email_verification"
+	TokenPurposePasswordReset     TokenPurpose = "password_reset"
+	TokenPurposeMagicLink         TokenPurpose = "magic_link"
+	TokenPurposeEmailChange       TokenPurpose = "email_change"
+)
+
+// String returns the string representation of token purpose
+func (p TokenPurpose) String() string {
+	return string(p)
+}
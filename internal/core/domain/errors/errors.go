@@ -1,6 +1,12 @@
 package errors
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
 
 // Application errors
 var (
@@ -11,12 +17,16 @@ var (
 	ErrTokenInvalid            = errors.New("token is invalid or expired")
 	ErrTokenNotFound           = errors.New("token not found")
 	ErrTokenExpired            = errors.New("token has expired")
+	ErrTokenRevoked            = errors.New("token has been revoked")
 	ErrInvalidClaims           = errors.New("invalid claims in token")
 	ErrInvalidIDFormat         = errors.New("invalid ID format")
 	ErrUnexpectedSigningMethod = errors.New("unexpected signing method")
 	ErrCreateFileSpace         = errors.New("failed to create file space")
 	ErrUploadFile              = errors.New("failed to upload file")
 	ErrDeleteFile              = errors.New("failed to delete file")
+	ErrPermissionDenied        = errors.New("you do not have permission to perform this action")
+	ErrOAuthExchangeFailed     = errors.New("failed to complete sign-in with the identity provider")
+	ErrInvalidQuery            = errors.New("invalid query parameter")
 )
 
 // Domain errors
@@ -24,9 +34,122 @@ var (
 	ErrUserNotFound          = errors.New("user not found")
 	ErrUserAlreadyExists     = errors.New("user already exists")
 	ErrEmailAlreadyExists    = errors.New("email already exists")
+	ErrEmailUnchanged        = errors.New("new email must differ from the current email")
 	ErrPasswordNotMatch      = errors.New("password does not match")
 	ErrInvalidEmail          = errors.New("invalid email format")
 	ErrInvalidEmailLength    = errors.New("email length must be between 5 and 254 characters")
 	ErrInvalidPasswordLength = errors.New("password must be at least 8 characters long")
-	ErrPasswordWeak          = errors.New("password must contain at least one special character")
+	ErrPasswordWeak          = errors.New("password does not meet the password policy")
+	ErrRoleNotFound          = errors.New("role not found")
+	ErrTwoFactorNotSetup     = errors.New("two-factor authentication has not been set up")
+	ErrTwoFactorEnabled      = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled   = errors.New("two-factor authentication is not enabled")
+	ErrInvalidTwoFactorCode  = errors.New("invalid two-factor authentication code")
+	ErrTokenReused           = errors.New("refresh token reuse detected, session has been revoked")
+	ErrSessionNotFound       = errors.New("session not found")
+	ErrAccountLocked         = errors.New("account is temporarily locked")
+	ErrOAuthProviderNotFound = errors.New("oauth provider is not configured")
+	ErrOAuthStateInvalid     = errors.New("oauth state is invalid or expired")
+	ErrOAuthEmailNotVerified = errors.New("the identity provider did not return a verified email")
+	ErrAPIKeyNotFound        = errors.New("api key not found")
+	ErrAPIKeyInvalid         = errors.New("api key is invalid, expired or revoked")
+	ErrAPIKeyScopeInvalid    = errors.New("api key needs at least one scope and scopes must be permissions you hold")
+	ErrAPIKeyExpiryInvalid   = errors.New("api key expiry is required and must be in the future")
+	ErrAPIKeyNotAllowed      = errors.New("this action requires signing in, api keys are not accepted")
 )
+
+// LockedError is returned when too many failed attempts locked an account or
+// client. It matches ErrAccountLocked with errors.Is.
+type LockedError struct {
+	RetryAfter time.Duration
+}
+
+func NewLockedError(retryAfter time.Duration) *LockedError {
+	return &LockedError{RetryAfter: retryAfter}
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("%s, retry after %d seconds", ErrAccountLocked.Error(), e.RetryAfterSeconds())
+}
+
+func (e *LockedError) Unwrap() error {
+	return ErrAccountLocked
+}
+
+func (e *LockedError) RetryAfterSeconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
+// AsLockedError reports whether err carries a lockout with a retry-after.
+func AsLockedError(err error) (*LockedError, bool) {
+	var lockedErr *LockedError
+	if errors.As(err, &lockedErr) {
+		return lockedErr, true
+	}
+	return nil, false
+}
+
+// PasswordViolation describes a single password policy rule that failed.
+type PasswordViolation struct {
+	Code    string
+	Message string
+}
+
+// PasswordPolicyError lists every rule a password failed. It matches
+// ErrPasswordWeak with errors.Is.
+type PasswordPolicyError struct {
+	Violations []PasswordViolation
+}
+
+func NewPasswordPolicyError(violations []PasswordViolation) *PasswordPolicyError {
+	return &PasswordPolicyError{Violations: violations}
+}
+
+func (e *PasswordPolicyError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		messages[i] = violation.Message
+	}
+	return fmt.Sprintf("%s: %s", ErrPasswordWeak.Error(), strings.Join(messages, "; "))
+}
+
+func (e *PasswordPolicyError) Unwrap() error {
+	return ErrPasswordWeak
+}
+
+// AsPasswordPolicyError reports whether err carries password policy violations.
+func AsPasswordPolicyError(err error) (*PasswordPolicyError, bool) {
+	var policyErr *PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		return policyErr, true
+	}
+	return nil, false
+}
+
+// QueryError names a filter or sort parameter that was rejected. It matches
+// ErrInvalidQuery with errors.Is.
+type QueryError struct {
+	Param  string
+	Reason string
+}
+
+func NewQueryError(param, reason string) *QueryError {
+	return &QueryError{Param: param, Reason: reason}
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("%s %q: %s", ErrInvalidQuery.Error(), e.Param, e.Reason)
+}
+
+func (e *QueryError) Unwrap() error {
+	return ErrInvalidQuery
+}
+
+// AsQueryError reports whether err is a rejected query parameter.
+func AsQueryError(err error) (*QueryError, bool) {
+	var queryErr *QueryError
+	if errors.As(err, &queryErr) {
+		return queryErr, true
+	}
+	return nil, false
+}
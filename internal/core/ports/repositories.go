@@ -2,37 +2,173 @@ package ports
 
 import (
 	"context"
+	"go-gin-clean/internal/core/contracts"
 	"go-gin-clean/internal/core/domain/entities"
+	"go-gin-clean/internal/core/domain/enums"
+	"time"
 )
 
 // Repository interfaces (secondary ports)
 type BaseRepository[T any] interface {
 	FindAll(ctx context.Context, limit, offset int, query any, args ...any) ([]*T, int64, error)
+	// FindAllByQuery is FindAll narrowed and ordered by spec. Field names are
+	// resolved through the fields whitelist and anything else is rejected.
+	FindAllByQuery(ctx context.Context, limit, offset int, fields contracts.QueryFields, spec *contracts.QuerySpec, query any, args ...any) ([]*T, int64, error)
+	// FindAllByCursor is FindAllByQuery with keyset pagination: it returns the
+	// rows after or before an opaque cursor and the cursors around them.
+	FindAllByCursor(ctx context.Context, fields contracts.QueryFields, spec *contracts.QuerySpec, page *contracts.CursorRequest, query any, args ...any) ([]*T, *contracts.CursorPage, error)
 	FindByID(ctx context.Context, id int64) (*T, error)
 	FindFirst(ctx context.Context, query any, args ...any) (*T, error)
 	Where(ctx context.Context, query any, args ...any) ([]*T, error)
 	WhereExisting(ctx context.Context, query any, args ...any) (bool, error)
 	Create(ctx context.Context, entity *T) (*T, error)
 	Update(ctx context.Context, entity *T) (*T, error)
+	// Delete soft-deletes entities embedding entities.Audit and removes any
+	// other entity. Reads skip soft-deleted rows.
 	Delete(ctx context.Context, id int64) error
+	// WithDeleted returns a repository whose reads include soft-deleted rows.
+	WithDeleted() BaseRepository[T]
+	Restore(ctx context.Context, id int64) error
+	// Purge permanently removes rows soft-deleted before deletedBefore.
+	Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
 }
 
 type UserRepository interface {
-	FindAll(ctx context.Context, limit, offset int, search string) ([]*entities.User, int64, error)
+	FindAll(ctx context.Context, limit, offset int, spec *contracts.QuerySpec) ([]*entities.User, int64, error)
+	FindAllByCursor(ctx context.Context, page *contracts.CursorRequest, spec *contracts.QuerySpec) ([]*entities.User, *contracts.CursorPage, error)
 	FindByID(ctx context.Context, id int64) (*entities.User, error)
 	Create(ctx context.Context, user *entities.User) (*entities.User, error)
 	Update(ctx context.Context, user *entities.User) (*entities.User, error)
 	Delete(ctx context.Context, id int64) error
+	FindAllDeleted(ctx context.Context, limit, offset int, search string) ([]*entities.User, int64, error)
+	// Restore returns errors.ErrUserNotFound when no soft-deleted user has id.
+	Restore(ctx context.Context, id int64) error
+	// Purge permanently removes users soft-deleted before deletedBefore,
+	// together with the rows that reference them.
+	Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
 	FindByEmail(ctx context.Context, email string) (*entities.User, error)
+	// ExistsByEmail also matches soft-deleted users, whose addresses stay
+	// reserved until they are purged.
 	ExistsByEmail(ctx context.Context, email string) bool
+	// UpdateEmail writes email and pending_email, including empty values.
+	UpdateEmail(ctx context.Context, user *entities.User) error
+	UpdateTwoFactor(ctx context.Context, user *entities.User) error
+	// UpdateTwoFactorStep writes two_factor_last_step and two_factor_secret
+	// only while the stored step is older than user.TwoFactorLastStep, so each
+	// TOTP code is accepted once. It returns errors.ErrInvalidTwoFactorCode
+	// when the step has already been used.
+	UpdateTwoFactorStep(ctx context.Context, user *entities.User) error
+	UpdatePassword(ctx context.Context, user *entities.User) error
 }
 
 type RefreshTokenRepository interface {
 	Save(ctx context.Context, token *entities.RefreshToken) error
 	FindByToken(ctx context.Context, token string) (*entities.RefreshToken, error)
 	FindByUserID(ctx context.Context, userID int64) ([]*entities.RefreshToken, error)
+	FindActiveByUserID(ctx context.Context, userID int64) ([]*entities.RefreshToken, error)
 	RevokeAllByUserID(ctx context.Context, userID int64) error
+	RevokeAllByUserIDExceptFamily(ctx context.Context, userID int64, familyID string) error
 	RevokeByToken(ctx context.Context, token string) error
+	RevokeFamily(ctx context.Context, familyID string) error
+	// Rotate marks an active token as rotated. It returns
+	// errors.ErrTokenReused when the token was already rotated or revoked.
+	Rotate(ctx context.Context, token *entities.RefreshToken) error
 	DeleteExpired(ctx context.Context) error
 	IsTokenValid(ctx context.Context, token string) bool
 }
+
+type RoleRepository interface {
+	FindAll(ctx context.Context) ([]*entities.Role, error)
+	FindByName(ctx context.Context, name string) (*entities.Role, error)
+	FindByNames(ctx context.Context, names []string) ([]*entities.Role, error)
+	AssignToUser(ctx context.Context, user *entities.User, roles []*entities.Role) error
+	Create(ctx context.Context, role *entities.Role) (*entities.Role, error)
+	// AssignPermissions replaces the permissions granted by the role.
+	AssignPermissions(ctx context.Context, role *entities.Role, permissions []*entities.Permission) error
+}
+
+type RecoveryCodeRepository interface {
+	ReplaceAll(ctx context.Context, userID int64, codes []*entities.RecoveryCode) error
+	FindUnusedByUserID(ctx context.Context, userID int64) ([]*entities.RecoveryCode, error)
+	MarkAsUsed(ctx context.Context, code *entities.RecoveryCode) error
+	DeleteByUserID(ctx context.Context, userID int64) error
+}
+
+type OneTimeTokenRepository interface {
+	// Issue stores a new token and deletes any unconsumed tokens the user
+	// holds for the same purpose.
+	Issue(ctx context.Context, token *entities.OneTimeToken) error
+	// FindUnconsumed looks up a token without using it up.
+	FindUnconsumed(ctx context.Context, tokenHash string, purpose enums.TokenPurpose) (*entities.OneTimeToken, error)
+	// Consume atomically marks an unconsumed token as used and returns it.
+	Consume(ctx context.Context, tokenHash string, purpose enums.TokenPurpose) (*entities.OneTimeToken, error)
+	InvalidateByUserID(ctx context.Context, userID int64, purpose enums.TokenPurpose) error
+	DeleteExpired(ctx context.Context) error
+}
+
+type PasswordHistoryRepository interface {
+	Create(ctx context.Context, history *entities.PasswordHistory) error
+	FindRecentByUserID(ctx context.Context, userID int64, limit int) ([]*entities.PasswordHistory, error)
+	// Prune keeps only the most recent entries for the user.
+	Prune(ctx context.Context, userID int64, keep int) error
+}
+
+type UserIdentityRepository interface {
+	Create(ctx context.Context, identity *entities.UserIdentity) error
+	FindByProviderSubject(ctx context.Context, provider, subject string) (*entities.UserIdentity, error)
+	FindByUserID(ctx context.Context, userID int64) ([]*entities.UserIdentity, error)
+}
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *entities.APIKey) error
+	FindByHash(ctx context.Context, keyHash string) (*entities.APIKey, error)
+	FindActiveByUserID(ctx context.Context, userID int64) ([]*entities.APIKey, error)
+	Revoke(ctx context.Context, userID, keyID int64) error
+	RevokeAllByUserID(ctx context.Context, userID int64) error
+	TouchLastUsed(ctx context.Context, keyID int64) error
+}
+
+type AuditLogRepository interface {
+	Create(ctx context.Context, log *entities.AuditLog) error
+	FindAll(ctx context.Context, limit, offset int, filter *contracts.AuditLogFilter) ([]*entities.AuditLog, int64, error)
+}
+
+type RevokedTokenRepository interface {
+	// Save creates the entry or replaces an existing one with the same key.
+	Save(ctx context.Context, token *entities.RevokedToken) error
+	// FindByKeys returns the unexpired entries among the given keys.
+	FindByKeys(ctx context.Context, keys []string) ([]*entities.RevokedToken, error)
+	DeleteExpired(ctx context.Context) error
+}
+
+type LoginAttemptRepository interface {
+	// FindByKey returns an empty attempt record when the key is unknown.
+	FindByKey(ctx context.Context, key string) (*entities.LoginAttempt, error)
+	// Update applies fn to the attempt stored under key, or to an empty one,
+	// and saves the result. Concurrent updates of the same key run one after
+	// the other, so no counted failure is lost.
+	Update(ctx context.Context, key string, fn func(attempt *entities.LoginAttempt)) (*entities.LoginAttempt, error)
+	Delete(ctx context.Context, key string) error
+	// DeleteStale removes unlocked entries whose last failure happened before
+	// lastFailureBefore.
+	DeleteStale(ctx context.Context, lastFailureBefore time.Time) error
+}
+
+// Repositories exposes repositories bound to the transaction of a
+// UnitOfWork. They must not be used after the unit of work returns.
+type Repositories interface {
+	Users() UserRepository
+	RefreshTokens() RefreshTokenRepository
+	Roles() RoleRepository
+	RecoveryCodes() RecoveryCodeRepository
+	OneTimeTokens() OneTimeTokenRepository
+	UserIdentities() UserIdentityRepository
+	PasswordHistories() PasswordHistoryRepository
+	APIKeys() APIKeyRepository
+}
+
+type UnitOfWork interface {
+	// Do runs fn in a single transaction, committing when it returns nil and
+	// rolling back when it returns an error or panics.
+	Do(ctx context.Context, fn func(repos Repositories) error) error
+}
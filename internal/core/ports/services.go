@@ -1,6 +1,7 @@
 package ports
 
 import (
+	"context"
 	"go-gin-clean/internal/core/contracts"
 	"go-gin-clean/internal/core/domain/entities"
 	"io"
@@ -9,15 +10,35 @@ import (
 
 // External service interfaces (secondary ports)
 type JWTService interface {
-	GenerateAccessToken(user *entities.User) (string, time.Time, error)
+	GenerateAccessToken(user *entities.User, sessionID string) (string, time.Time, error)
 	GenerateRefreshToken(userID int64) (string, time.Time, error)
 	ValidateAccessToken(token string) (*contracts.AccessTokenClaims, error)
 	ValidateRefreshToken(token string) (*contracts.RefreshTokenClaims, error)
+	GenerateMFAToken(userID int64) (string, time.Time, error)
+	ValidateMFAToken(token string) (*contracts.MFATokenClaims, error)
+	PublicKeys() []contracts.JSONWebKey
 }
 
-type BcryptService interface {
+type TOTPService interface {
+	GenerateSecret() (string, error)
+	GenerateURI(secret, accountName string) string
+	// ValidateCode returns the time step the code matched so callers can
+	// reject a code that has already been used.
+	ValidateCode(secret, code string) (int64, bool)
+	GenerateRecoveryCodes(count int) ([]string, error)
+}
+
+type PasswordHasher interface {
 	HashPassword(password string) (string, error)
 	ValidatePassword(password, hashedPassword string) error
+	// NeedsRehash reports whether the hash was produced with a different
+	// algorithm or weaker parameters than currently configured.
+	NeedsRehash(hashedPassword string) bool
+}
+
+type HashService interface {
+	Hash(value string) string
+	GenerateToken(size int) (string, error)
 }
 
 type EncryptionService interface {
@@ -25,6 +46,9 @@ type EncryptionService interface {
 	DecryptInternal(ciphertext string) (string, error)
 	EncryptURLSafe(plaintext string) (string, error)
 	DecryptURLSafe(ciphertext string) (string, error)
+	// NeedsRotation reports whether ciphertext was not produced with the
+	// active key (or uses the legacy format) and should be re-encrypted.
+	NeedsRotation(ciphertext string) bool
 }
 
 type MailerService interface {
@@ -36,3 +60,12 @@ type MediaService interface {
 	UploadFile(filename string, size int64, content io.Reader, filePath string) (*string, error)
 	DeleteFile(filePath string) error
 }
+
+type OAuthService interface {
+	// AuthCodeURL builds the provider authorization URL, including the PKCE
+	// S256 challenge derived from codeVerifier.
+	AuthCodeURL(ctx context.Context, provider, state, nonce, codeVerifier string) (string, error)
+	// Exchange redeems the authorization code and returns the identity from
+	// the verified ID token.
+	Exchange(ctx context.Context, provider, code, codeVerifier, nonce string) (*contracts.ExternalIdentity, error)
+}
@@ -3,27 +3,72 @@ package ports
 import (
 	"context"
 	"go-gin-clean/internal/core/contracts"
+	"time"
 )
 
 // Use case interfaces (primary ports)
 type UserUseCase interface {
 	Login(ctx context.Context, req *contracts.LoginRequest) (*contracts.LoginResponse, error)
+	LoginTwoFactor(ctx context.Context, req *contracts.TwoFactorLoginRequest) (*contracts.LoginResponse, error)
+	StartOAuth(ctx context.Context, provider string) (*contracts.OAuthStartResponse, error)
+	CompleteOAuth(ctx context.Context, req *contracts.OAuthCallbackRequest) (*contracts.LoginResponse, error)
+	SendMagicLink(ctx context.Context, email string) error
+	LoginMagicLink(ctx context.Context, req *contracts.MagicLinkLoginRequest) (*contracts.LoginResponse, error)
 	Register(ctx context.Context, req *contracts.RegisterRequest) error
-	RefreshToken(ctx context.Context, refreshToken string) (*contracts.RefreshTokenResponse, error)
-	Logout(ctx context.Context, userID int64) error
+	RefreshToken(ctx context.Context, req *contracts.RefreshTokenRequest) (*contracts.RefreshTokenResponse, error)
+	// Logout revokes every session of the user and denylists the access
+	// token the request was made with. current is nil for API keys.
+	Logout(ctx context.Context, userID int64, current *contracts.AccessTokenClaims) error
+	ListSessions(ctx context.Context, userID int64, currentSessionID string) ([]contracts.SessionInfo, error)
+	RevokeSession(ctx context.Context, userID int64, sessionID string, current *contracts.AccessTokenClaims) error
+	RevokeOtherSessions(ctx context.Context, userID int64, currentSessionID string) error
 	VerifyEmail(ctx context.Context, token string) error
 	SendVerifyEmail(ctx context.Context, email string) error
 	SendResetPassword(ctx context.Context, email string) error
 	ResetPassword(ctx context.Context, req *contracts.ResetPasswordRequest) error
-	GetAllUsers(ctx context.Context, page, pageSize int, search string) (*contracts.PaginationResponse[contracts.UserInfo], error)
+	GetAllUsers(ctx context.Context, page, pageSize int, spec *contracts.QuerySpec) (*contracts.PaginationResponse[contracts.UserInfo], error)
+	GetUsersByCursor(ctx context.Context, page *contracts.CursorRequest, spec *contracts.QuerySpec) (*contracts.CursorResponse[contracts.UserInfo], error)
 	GetUserByID(ctx context.Context, userID int64) (*contracts.UserInfo, error)
 	CreateUser(ctx context.Context, req *contracts.CreateUserRequest) (*contracts.UserInfo, error)
 	UpdateUser(ctx context.Context, userID int64, req *contracts.UpdateUserRequest) (*contracts.UserInfo, error)
 	ChangePassword(ctx context.Context, userID int64, req *contracts.ChangePasswordRequest) error
+	RequestEmailChange(ctx context.Context, userID int64, req *contracts.ChangeEmailRequest) error
+	ConfirmEmailChange(ctx context.Context, token string) error
 	DeleteUser(ctx context.Context, userID int64) error
+	GetDeletedUsers(ctx context.Context, page, pageSize int, search string) (*contracts.PaginationResponse[contracts.UserInfo], error)
+	RestoreUser(ctx context.Context, userID int64) (*contracts.UserInfo, error)
+	SetupTwoFactor(ctx context.Context, userID int64) (*contracts.TwoFactorSetupResponse, error)
+	EnableTwoFactor(ctx context.Context, userID int64, req *contracts.TwoFactorEnableRequest) (*contracts.TwoFactorEnableResponse, error)
+	DisableTwoFactor(ctx context.Context, userID int64, req *contracts.TwoFactorDisableRequest) error
+	UnlockUser(ctx context.Context, userID int64) error
+	AssignRoles(ctx context.Context, userID int64, req *contracts.AssignRolesRequest) (*contracts.UserInfo, error)
+}
+
+type APIKeyUseCase interface {
+	CreateAPIKey(ctx context.Context, userID int64, req *contracts.CreateAPIKeyRequest) (*contracts.CreateAPIKeyResponse, error)
+	ListAPIKeys(ctx context.Context, userID int64) ([]contracts.APIKeyInfo, error)
+	RevokeAPIKey(ctx context.Context, userID, keyID int64) error
+	Authenticate(ctx context.Context, key string) (*contracts.APIKeyPrincipal, error)
+}
+
+type AuditLogUseCase interface {
+	ListAuditLogs(ctx context.Context, page, pageSize int, filter *contracts.AuditLogFilter) (*contracts.PaginationResponse[contracts.AuditLogInfo], error)
+}
+
+// TokenRevocationUseCase maintains the access token denylist consulted by
+// the auth middleware on every request.
+type TokenRevocationUseCase interface {
+	RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error
+	RevokeSession(ctx context.Context, sessionID string) error
+	// RevokeAllForUser invalidates every access token issued to the user so far.
+	RevokeAllForUser(ctx context.Context, userID int64) error
+	IsRevoked(ctx context.Context, claims *contracts.AccessTokenClaims) (bool, error)
 }
 
 type EmailUseCase interface {
 	SendVerifyEmail(toEmail, toName, verifyToken string) error
 	SendResetPasswordEmail(toEmail, toName, resetToken string) error
+	SendMagicLinkEmail(toEmail, toName, loginURL string, expiresIn time.Duration) error
+	SendEmailChangeConfirmation(toEmail, toName, confirmURL string, expiresIn time.Duration) error
+	SendEmailChangeNotice(toEmail, toName, newEmail string) error
 }
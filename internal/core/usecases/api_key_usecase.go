@@ -0,0 +1,149 @@
+package usecases
+
+import (
+	"context"
+	"go-gin-clean/internal/core/contracts"
+	"go-gin-clean/internal/core/domain/entities"
+	"go-gin-clean/internal/core/domain/errors"
+	"go-gin-clean/internal/core/ports"
+	"log"
+	"slices"
+	"strings"
+	"time"
+)
+
+// apiKeyPrefix makes keys recognizable, e.g. for secret scanners.
+const apiKeyPrefix = "ggc_"
+
+type APIKeyUseCase struct {
+	apiKeyRepo  ports.APIKeyRepository
+	userRepo    ports.UserRepository
+	hashService ports.HashService
+}
+
+func NewAPIKeyUseCase(apiKeyRepo ports.APIKeyRepository, userRepo ports.UserRepository, hashService ports.HashService) ports.APIKeyUseCase {
+	return &APIKeyUseCase{
+		apiKeyRepo:  apiKeyRepo,
+		userRepo:    userRepo,
+		hashService: hashService,
+	}
+}
+
+func FormatAPIKeyInfo(key *entities.APIKey) contracts.APIKeyInfo {
+	return contracts.APIKeyInfo{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.ScopeList(),
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
+
+func (uc *APIKeyUseCase) CreateAPIKey(ctx context.Context, userID int64, req *contracts.CreateAPIKeyRequest) (*contracts.CreateAPIKeyResponse, error) {
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.ErrUserNotFound
+	}
+
+	// Keys can never grant more than the owner currently holds, and a key
+	// without scopes would be a credential that grants nothing
+	if len(req.Scopes) == 0 {
+		return nil, errors.ErrAPIKeyScopeInvalid
+	}
+
+	for _, scope := range req.Scopes {
+		if !user.HasPermission(scope) {
+			return nil, errors.ErrAPIKeyScopeInvalid
+		}
+	}
+
+	// Keys must expire so a leaked one does not stay valid forever
+	if req.ExpiresAt == nil || !req.ExpiresAt.After(time.Now()) {
+		return nil, errors.ErrAPIKeyExpiryInvalid
+	}
+
+	identifier, err := uc.hashService.GenerateToken(6)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := uc.hashService.GenerateToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := apiKeyPrefix + identifier
+	plainKey := prefix + "." + secret
+
+	apiKey := entities.NewAPIKey(user.ID, req.Name, prefix, uc.hashService.Hash(plainKey), req.Scopes, req.ExpiresAt)
+	if err := uc.apiKeyRepo.Create(ctx, apiKey); err != nil {
+		return nil, err
+	}
+
+	return &contracts.CreateAPIKeyResponse{
+		Key:    plainKey,
+		APIKey: FormatAPIKeyInfo(apiKey),
+	}, nil
+}
+
+func (uc *APIKeyUseCase) ListAPIKeys(ctx context.Context, userID int64) ([]contracts.APIKeyInfo, error) {
+	keys, err := uc.apiKeyRepo.FindActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]contracts.APIKeyInfo, len(keys))
+	for i, key := range keys {
+		infos[i] = FormatAPIKeyInfo(key)
+	}
+
+	return infos, nil
+}
+
+func (uc *APIKeyUseCase) RevokeAPIKey(ctx context.Context, userID, keyID int64) error {
+	if err := uc.apiKeyRepo.Revoke(ctx, userID, keyID); err != nil {
+		return errors.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// Authenticate resolves a presented key to its owner. The effective
+// permissions are the key scopes the user still holds, so revoking a role
+// also narrows existing keys.
+func (uc *APIKeyUseCase) Authenticate(ctx context.Context, key string) (*contracts.APIKeyPrincipal, error) {
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return nil, errors.ErrAPIKeyInvalid
+	}
+
+	apiKey, err := uc.apiKeyRepo.FindByHash(ctx, uc.hashService.Hash(key))
+	if err != nil || !apiKey.IsValid() {
+		return nil, errors.ErrAPIKeyInvalid
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, apiKey.UserID)
+	if err != nil || !user.IsActive {
+		return nil, errors.ErrAPIKeyInvalid
+	}
+
+	userPermissions := user.PermissionNames()
+	permissions := make([]string, 0, len(apiKey.ScopeList()))
+	for _, scope := range apiKey.ScopeList() {
+		if slices.Contains(userPermissions, scope) {
+			permissions = append(permissions, scope)
+		}
+	}
+
+	if err := uc.apiKeyRepo.TouchLastUsed(ctx, apiKey.ID); err != nil {
+		log.Printf("Failed to update last use of api key %d: %v", apiKey.ID, err)
+	}
+
+	return &contracts.APIKeyPrincipal{
+		KeyID:       apiKey.ID,
+		UserID:      user.ID,
+		Email:       user.Email,
+		Roles:       user.RoleNames(),
+		Permissions: permissions,
+	}, nil
+}
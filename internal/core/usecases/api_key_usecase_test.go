@@ -0,0 +1,71 @@
+package usecases_test
+
+import (
+	"context"
+	"go-gin-clean/internal/adapters/secondary/memory"
+	"go-gin-clean/internal/adapters/secondary/security"
+	"go-gin-clean/internal/core/contracts"
+	"go-gin-clean/internal/core/domain/entities"
+	"go-gin-clean/internal/core/domain/enums"
+	"go-gin-clean/internal/core/domain/errors"
+	"go-gin-clean/internal/core/usecases"
+	"testing"
+	"time"
+)
+
+func TestCreateAPIKeyRequiresScopesAndExpiry(t *testing.T) {
+	env := newTestEnv(t, nil)
+	ctx := context.Background()
+	info := env.createUser(t, "Alice", "alice@example.com")
+
+	roles := memory.NewRoleRepository(env.db)
+	reader, err := roles.Create(ctx, entities.NewRole("reader", "", []entities.Permission{
+		*entities.NewPermission(enums.PermissionUsersRead.String(), ""),
+	}))
+	if err != nil {
+		t.Fatalf("create role: %v", err)
+	}
+
+	user, err := env.userRepo.FindByID(ctx, info.ID)
+	if err != nil {
+		t.Fatalf("find user: %v", err)
+	}
+	if err := roles.AssignToUser(ctx, user, []*entities.Role{reader}); err != nil {
+		t.Fatalf("assign role: %v", err)
+	}
+
+	apiKeys := usecases.NewAPIKeyUseCase(memory.NewAPIKeyRepository(env.db), env.userRepo, security.NewSHA256Service())
+	expiresAt := time.Now().Add(time.Hour)
+	scopes := []string{enums.PermissionUsersRead.String()}
+
+	rejected := map[string]struct {
+		req  contracts.CreateAPIKeyRequest
+		want error
+	}{
+		"no scopes":       {contracts.CreateAPIKeyRequest{Name: "ci", ExpiresAt: &expiresAt}, errors.ErrAPIKeyScopeInvalid},
+		"unheld scope":    {contracts.CreateAPIKeyRequest{Name: "ci", Scopes: []string{"users:delete"}, ExpiresAt: &expiresAt}, errors.ErrAPIKeyScopeInvalid},
+		"unknown scope":   {contracts.CreateAPIKeyRequest{Name: "ci", Scopes: []string{"everything"}, ExpiresAt: &expiresAt}, errors.ErrAPIKeyScopeInvalid},
+		"no expiry":       {contracts.CreateAPIKeyRequest{Name: "ci", Scopes: scopes}, errors.ErrAPIKeyExpiryInvalid},
+		"expired already": {contracts.CreateAPIKeyRequest{Name: "ci", Scopes: scopes, ExpiresAt: new(time.Time)}, errors.ErrAPIKeyExpiryInvalid},
+	}
+
+	for name, tc := range rejected {
+		if _, err := apiKeys.CreateAPIKey(ctx, info.ID, &tc.req); err != tc.want {
+			t.Errorf("%s: err = %v, want %v", name, err, tc.want)
+		}
+	}
+
+	created, err := apiKeys.CreateAPIKey(ctx, info.ID, &contracts.CreateAPIKeyRequest{Name: "ci", Scopes: scopes, ExpiresAt: &expiresAt})
+	if err != nil {
+		t.Fatalf("CreateAPIKey: %v", err)
+	}
+
+	principal, err := apiKeys.Authenticate(ctx, created.Key)
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+
+	if principal.UserID != info.ID {
+		t.Fatalf("principal = %+v, want user %d", principal, info.ID)
+	}
+}
@@ -0,0 +1,138 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"go-gin-clean/internal/core/contracts"
+	"go-gin-clean/internal/core/domain/entities"
+	"go-gin-clean/internal/core/domain/enums"
+	"go-gin-clean/internal/core/ports"
+	"log"
+	"reflect"
+)
+
+type AuditLogUseCase struct {
+	auditLogRepo ports.AuditLogRepository
+}
+
+func NewAuditLogUseCase(auditLogRepo ports.AuditLogRepository) ports.AuditLogUseCase {
+	return &AuditLogUseCase{
+		auditLogRepo: auditLogRepo,
+	}
+}
+
+func FormatAuditLogInfo(auditLog *entities.AuditLog) contracts.AuditLogInfo {
+	info := contracts.AuditLogInfo{
+		ID:        auditLog.ID,
+		ActorID:   auditLog.ActorID,
+		TargetID:  auditLog.TargetID,
+		Action:    auditLog.Action,
+		IPAddress: auditLog.IPAddress,
+		UserAgent: auditLog.UserAgent,
+		CreatedAt: auditLog.CreatedAt,
+	}
+
+	if json.Valid([]byte(auditLog.Changes)) {
+		info.Changes = json.RawMessage(auditLog.Changes)
+	}
+	if json.Valid([]byte(auditLog.Details)) {
+		info.Details = json.RawMessage(auditLog.Details)
+	}
+
+	return info
+}
+
+func (uc *AuditLogUseCase) ListAuditLogs(ctx context.Context, page, pageSize int, filter *contracts.AuditLogFilter) (*contracts.PaginationResponse[contracts.AuditLogInfo], error) {
+	offset := contracts.Offset(page, pageSize)
+	logs, total, err := uc.auditLogRepo.FindAll(ctx, pageSize, offset, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]contracts.AuditLogInfo, len(logs))
+	for i, auditLog := range logs {
+		infos[i] = FormatAuditLogInfo(auditLog)
+	}
+
+	return contracts.NewPaginationResponse(infos, page, pageSize, int(total)), nil
+}
+
+// auditChange is the previous and new value of one field in an audit diff.
+type auditChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// auditUserSnapshot lists the user fields tracked by the audit log. Secrets
+// such as the password hash and TOTP secret are deliberately left out.
+func auditUserSnapshot(user *entities.User) map[string]any {
+	if user == nil {
+		return nil
+	}
+
+	return map[string]any{
+		"name":               user.Name,
+		"email":              user.Email,
+		"pending_email":      user.PendingEmail,
+		"avatar":             user.Avatar,
+		"gender":             user.Gender,
+		"is_active":          user.IsActive,
+		"roles":              user.RoleNames(),
+		"two_factor_enabled": user.TwoFactorEnabled,
+	}
+}
+
+// auditDiff returns the fields whose value differs between two snapshots.
+// A nil snapshot stands for a record that did not exist.
+func auditDiff(before, after map[string]any) map[string]auditChange {
+	changes := make(map[string]auditChange)
+
+	for field, to := range after {
+		from, existed := before[field]
+		if !existed || !reflect.DeepEqual(from, to) {
+			changes[field] = auditChange{From: from, To: to}
+		}
+	}
+
+	for field, from := range before {
+		if _, exists := after[field]; !exists {
+			changes[field] = auditChange{From: from}
+		}
+	}
+
+	return changes
+}
+
+// recordAudit stores an audit entry. The IP address and user agent come
+// from the request context, as does the actor unless actorID is given.
+// Failures are only logged so auditing never blocks the audited action.
+func recordAudit(ctx context.Context, repo ports.AuditLogRepository, action enums.AuditAction, actorID, targetID *int64, changes map[string]auditChange, details map[string]any) {
+	actor := contracts.AuditActorFromContext(ctx)
+	if actorID == nil {
+		actorID = actor.UserID
+	}
+
+	var detailsJSON string
+	if len(details) > 0 {
+		encoded, err := json.Marshal(details)
+		if err != nil {
+			log.Printf("Failed to encode audit details for %s: %v", action, err)
+		}
+		detailsJSON = string(encoded)
+	}
+
+	auditLog := entities.NewAuditLog(actorID, targetID, action.String(), detailsJSON)
+	auditLog.SetClient(actor.IPAddress, actor.UserAgent)
+
+	if len(changes) > 0 {
+		encoded, err := json.Marshal(changes)
+		if err != nil {
+			log.Printf("Failed to encode audit changes for %s: %v", action, err)
+		}
+		auditLog.Changes = string(encoded)
+	}
+
+	if err := repo.Create(ctx, auditLog); err != nil {
+		log.Printf("Failed to record audit log %s: %v", action, err)
+	}
+}
@@ -3,6 +3,7 @@ package usecases
 import (
 	"fmt"
 	"go-gin-clean/internal/core/ports"
+	"time"
 )
 
 type EmailUseCase struct {
@@ -48,3 +49,53 @@ func (e *EmailUseCase) SendResetPasswordEmail(to, name, url string) error {
 
 	return e.smtp.SendEmail(to, subject, body)
 }
+
+func (e *EmailUseCase) SendMagicLinkEmail(to, name, url string, expiresIn time.Duration) error {
+	subject := fmt.Sprintf("Sign In to %s", e.application)
+
+	data := map[string]any{
+		"Name":      name,
+		"LoginURL":  url,
+		"ExpiresIn": int(expiresIn.Minutes()),
+	}
+
+	body, err := e.smtp.LoadTemplate("magic_link", data)
+	if err != nil {
+		return fmt.Errorf("failed to load magic link email template: %v", err)
+	}
+
+	return e.smtp.SendEmail(to, subject, body)
+}
+
+func (e *EmailUseCase) SendEmailChangeConfirmation(to, name, url string, expiresIn time.Duration) error {
+	subject := fmt.Sprintf("Confirm Your New %s Email", e.application)
+
+	data := map[string]any{
+		"Name":       name,
+		"ConfirmURL": url,
+		"ExpiresIn":  int(expiresIn.Hours()),
+	}
+
+	body, err := e.smtp.LoadTemplate("confirm_email_change", data)
+	if err != nil {
+		return fmt.Errorf("failed to load email change confirmation template: %v", err)
+	}
+
+	return e.smtp.SendEmail(to, subject, body)
+}
+
+func (e *EmailUseCase) SendEmailChangeNotice(to, name, newEmail string) error {
+	subject := fmt.Sprintf("%s Email Change Requested", e.application)
+
+	data := map[string]any{
+		"Name":     name,
+		"NewEmail": newEmail,
+	}
+
+	body, err := e.smtp.LoadTemplate("email_change_notice", data)
+	if err != nil {
+		return fmt.Errorf("failed to load email change notice template: %v", err)
+	}
+
+	return e.smtp.SendEmail(to, subject, body)
+}
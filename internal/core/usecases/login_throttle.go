@@ -0,0 +1,104 @@
+package usecases
+
+import (
+	"context"
+	"go-gin-clean/internal/core/domain/entities"
+	"go-gin-clean/internal/core/domain/errors"
+	"go-gin-clean/internal/core/ports"
+	"go-gin-clean/pkg/config"
+	"log"
+	"strings"
+	"time"
+)
+
+// LoginThrottle applies the lockout policy on top of a pluggable attempt
+// store. Failures are tracked per email and per client IP; only the email
+// counter is reset by a successful login.
+type LoginThrottle struct {
+	attempts ports.LoginAttemptRepository
+	cfg      *config.LockoutConfig
+}
+
+func NewLoginThrottle(attempts ports.LoginAttemptRepository, cfg *config.LockoutConfig) *LoginThrottle {
+	return &LoginThrottle{
+		attempts: attempts,
+		cfg:      cfg,
+	}
+}
+
+func emailThrottleKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func magicLinkThrottleKey(email string) string {
+	return "magic_link:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipThrottleKey(ip string) string {
+	return "ip:" + ip
+}
+
+// Check returns a LockedError when either the email or the IP is locked.
+func (t *LoginThrottle) Check(ctx context.Context, email, ip string) error {
+	for _, key := range t.keys(email, ip) {
+		attempt, err := t.attempts.FindByKey(ctx, key)
+		if err != nil {
+			return err
+		}
+
+		if attempt.IsLocked() {
+			return errors.NewLockedError(attempt.RetryAfter())
+		}
+	}
+
+	return nil
+}
+
+func (t *LoginThrottle) RegisterFailure(ctx context.Context, email, ip string) {
+	for _, key := range t.keys(email, ip) {
+		maxAttempts := t.cfg.MaxAttempts
+		if strings.HasPrefix(key, "ip:") {
+			maxAttempts = t.cfg.IPMaxAttempts
+		}
+
+		_, err := t.attempts.Update(ctx, key, func(attempt *entities.LoginAttempt) {
+			attempt.RegisterFailure(maxAttempts, t.cfg.Window, t.cfg.BaseDuration, t.cfg.MaxDuration)
+		})
+		if err != nil {
+			log.Printf("Failed to save login attempts for %s: %v", key, err)
+		}
+	}
+}
+
+func (t *LoginThrottle) Reset(ctx context.Context, email string) error {
+	return t.attempts.Delete(ctx, emailThrottleKey(email))
+}
+
+// Allow counts a request against a per-key quota of limit requests per
+// window, used to rate limit actions such as sending sign-in links.
+func (t *LoginThrottle) Allow(ctx context.Context, key string, limit int, window time.Duration) error {
+	var retryAfter time.Duration
+	_, err := t.attempts.Update(ctx, key, func(attempt *entities.LoginAttempt) {
+		retryAfter = attempt.RetryAfter()
+		if retryAfter == 0 {
+			attempt.RegisterFailure(limit, window, window, window)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	if retryAfter > 0 {
+		return errors.NewLockedError(retryAfter)
+	}
+
+	return nil
+}
+
+func (t *LoginThrottle) keys(email, ip string) []string {
+	keys := []string{emailThrottleKey(email)}
+	if ip != "" {
+		keys = append(keys, ipThrottleKey(ip))
+	}
+	return keys
+}
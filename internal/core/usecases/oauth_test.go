@@ -0,0 +1,82 @@
+package usecases_test
+
+import (
+	"context"
+	"go-gin-clean/internal/core/contracts"
+	"go-gin-clean/internal/core/domain/errors"
+	"testing"
+)
+
+// stubOAuthService stands in for the provider so the tests can tell whether
+// a callback got as far as the code exchange.
+type stubOAuthService struct {
+	exchanges int
+}
+
+func (s *stubOAuthService) AuthCodeURL(ctx context.Context, provider, state, nonce, codeVerifier string) (string, error) {
+	return "https://idp.example/authorize?state=" + state, nil
+}
+
+func (s *stubOAuthService) Exchange(ctx context.Context, provider, code, codeVerifier, nonce string) (*contracts.ExternalIdentity, error) {
+	s.exchanges++
+	return &contracts.ExternalIdentity{
+		Provider:      provider,
+		Subject:       "subject-1",
+		Email:         "oauth@example.com",
+		EmailVerified: true,
+		Name:          "OAuth User",
+	}, nil
+}
+
+func TestCompleteOAuthRejectsStateMismatch(t *testing.T) {
+	provider := &stubOAuthService{}
+	env := newTestEnv(t, provider)
+	ctx := context.Background()
+
+	start, err := env.users.StartOAuth(ctx, "test")
+	if err != nil {
+		t.Fatalf("StartOAuth: %v", err)
+	}
+
+	cases := map[string]contracts.OAuthCallbackRequest{
+		"wrong state":     {Provider: "test", Code: "code", State: "forged", StateCookie: start.StateCookie},
+		"other provider":  {Provider: "other", Code: "code", State: stateOf(t, start), StateCookie: start.StateCookie},
+		"missing cookie":  {Provider: "test", Code: "code", State: stateOf(t, start)},
+		"tampered cookie": {Provider: "test", Code: "code", State: stateOf(t, start), StateCookie: start.StateCookie + "x"},
+	}
+
+	for name, req := range cases {
+		if _, err := env.users.CompleteOAuth(ctx, &req); err != errors.ErrOAuthStateInvalid {
+			t.Errorf("%s: err = %v, want %v", name, err, errors.ErrOAuthStateInvalid)
+		}
+	}
+
+	if provider.exchanges != 0 {
+		t.Fatalf("code exchanged %d times for invalid callbacks", provider.exchanges)
+	}
+
+	resp, err := env.users.CompleteOAuth(ctx, &contracts.OAuthCallbackRequest{
+		Provider:    "test",
+		Code:        "code",
+		State:       stateOf(t, start),
+		StateCookie: start.StateCookie,
+	})
+	if err != nil {
+		t.Fatalf("CompleteOAuth: %v", err)
+	}
+
+	if resp.AccessToken == "" || resp.User.Email != "oauth@example.com" {
+		t.Fatalf("unexpected login response %+v", resp)
+	}
+}
+
+// stateOf reads the state parameter the stub put in the authorization URL.
+func stateOf(t *testing.T, start *contracts.OAuthStartResponse) string {
+	t.Helper()
+
+	const prefix = "https://idp.example/authorize?state="
+	if len(start.AuthURL) <= len(prefix) {
+		t.Fatalf("unexpected auth URL %s", start.AuthURL)
+	}
+	return start.AuthURL[len(prefix):]
+}
@@ -0,0 +1,193 @@
+package usecases
+
+import (
+	"bufio"
+	"context"
+	_ "embed"
+	"fmt"
+	"go-gin-clean/internal/core/domain/entities"
+	"go-gin-clean/internal/core/domain/errors"
+	"go-gin-clean/internal/core/ports"
+	"go-gin-clean/pkg/config"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+//go:embed common_passwords.txt
+var commonPasswordList string
+
+// minPersonalInfoLength avoids rejecting passwords for containing very short
+// name fragments.
+const minPersonalInfoLength = 3
+
+// PasswordPolicy validates new passwords against the configured rules and
+// keeps the per-user password history used to prevent reuse.
+type PasswordPolicy struct {
+	history         ports.PasswordHistoryRepository
+	hasher          ports.PasswordHasher
+	cfg             *config.PasswordPolicyConfig
+	commonPasswords map[string]struct{}
+}
+
+// NewPasswordPolicy loads the embedded common password list and, when
+// configured, the one in cfg.CommonPasswordsFile.
+func NewPasswordPolicy(history ports.PasswordHistoryRepository, hasher ports.PasswordHasher, cfg *config.PasswordPolicyConfig) (*PasswordPolicy, error) {
+	commonPasswords := make(map[string]struct{})
+	if err := parseCommonPasswords(strings.NewReader(commonPasswordList), commonPasswords); err != nil {
+		return nil, fmt.Errorf("failed to read built-in common password list: %w", err)
+	}
+
+	if cfg.CommonPasswordsFile != "" {
+		file, err := os.Open(cfg.CommonPasswordsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open common password list: %w", err)
+		}
+		defer file.Close()
+
+		if err := parseCommonPasswords(file, commonPasswords); err != nil {
+			return nil, fmt.Errorf("failed to read common password list: %w", err)
+		}
+	}
+
+	return &PasswordPolicy{
+		history:         history,
+		hasher:          hasher,
+		cfg:             cfg,
+		commonPasswords: commonPasswords,
+	}, nil
+}
+
+func parseCommonPasswords(list io.Reader, passwords map[string]struct{}) error {
+	scanner := bufio.NewScanner(list)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		passwords[strings.ToLower(line)] = struct{}{}
+	}
+	return scanner.Err()
+}
+
+// Validate returns a PasswordPolicyError listing every failed rule. The user
+// supplies the email and name to compare against; a zero ID skips the
+// history check (new accounts).
+func (p *PasswordPolicy) Validate(ctx context.Context, password string, user *entities.User) error {
+	var violations []errors.PasswordViolation
+	add := func(code, message string) {
+		violations = append(violations, errors.PasswordViolation{Code: code, Message: message})
+	}
+
+	length := utf8.RuneCountInString(password)
+	if length < p.cfg.MinLength {
+		add("min_length", fmt.Sprintf("password must be at least %d characters long", p.cfg.MinLength))
+	}
+	if p.cfg.MaxLength > 0 && length > p.cfg.MaxLength {
+		add("max_length", fmt.Sprintf("password must be at most %d characters long", p.cfg.MaxLength))
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSpecial bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSpecial = true
+		}
+	}
+
+	if p.cfg.RequireUppercase && !hasUpper {
+		add("uppercase", "password must contain at least one uppercase letter")
+	}
+	if p.cfg.RequireLowercase && !hasLower {
+		add("lowercase", "password must contain at least one lowercase letter")
+	}
+	if p.cfg.RequireDigit && !hasDigit {
+		add("digit", "password must contain at least one digit")
+	}
+	if p.cfg.RequireSpecial && !hasSpecial {
+		add("special", "password must contain at least one special character")
+	}
+
+	lowered := strings.ToLower(password)
+
+	if p.cfg.DisallowPersonalInfo && user != nil {
+		localPart, _, _ := strings.Cut(strings.ToLower(user.Email), "@")
+		if len(localPart) >= minPersonalInfoLength && strings.Contains(lowered, localPart) {
+			add("contains_email", "password must not contain your email address")
+		}
+
+		for _, part := range strings.Fields(strings.ToLower(user.Name)) {
+			if utf8.RuneCountInString(part) >= minPersonalInfoLength && strings.Contains(lowered, part) {
+				add("contains_name", "password must not contain your name")
+				break
+			}
+		}
+	}
+
+	if p.cfg.DisallowCommon {
+		if _, ok := p.commonPasswords[lowered]; ok {
+			add("common", "password is too common")
+		}
+	}
+
+	if len(violations) == 0 && user != nil && user.ID != 0 && p.isReused(ctx, password, user) {
+		add("reused", fmt.Sprintf("password must not match any of your last %d passwords", p.cfg.HistorySize))
+	}
+
+	if len(violations) > 0 {
+		return errors.NewPasswordPolicyError(violations)
+	}
+
+	return nil
+}
+
+// isReused compares against the current hash and the stored history. Only
+// run once every cheap rule has passed, as each comparison is a full hash.
+func (p *PasswordPolicy) isReused(ctx context.Context, password string, user *entities.User) bool {
+	if p.cfg.HistorySize <= 0 {
+		return false
+	}
+
+	if user.Password != "" && p.hasher.ValidatePassword(password, user.Password) == nil {
+		return true
+	}
+
+	histories, err := p.history.FindRecentByUserID(ctx, user.ID, p.cfg.HistorySize)
+	if err != nil {
+		log.Printf("Failed to load password history for user %d: %v", user.ID, err)
+		return false
+	}
+
+	for _, history := range histories {
+		if p.hasher.ValidatePassword(password, history.PasswordHash) == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Record stores the new hash in the user's history and trims old entries.
+func (p *PasswordPolicy) Record(ctx context.Context, userID int64, passwordHash string) {
+	if p.cfg.HistorySize <= 0 {
+		return
+	}
+
+	if err := p.history.Create(ctx, entities.NewPasswordHistory(userID, passwordHash)); err != nil {
+		log.Printf("Failed to record password history for user %d: %v", userID, err)
+		return
+	}
+
+	if err := p.history.Prune(ctx, userID, p.cfg.HistorySize); err != nil {
+		log.Printf("Failed to prune password history for user %d: %v", userID, err)
+	}
+}
@@ -0,0 +1,147 @@
+package usecases_test
+
+import (
+	"context"
+	"go-gin-clean/internal/adapters/secondary/memory"
+	"go-gin-clean/internal/adapters/secondary/oauth"
+	"go-gin-clean/internal/adapters/secondary/security"
+	"go-gin-clean/internal/core/contracts"
+	"go-gin-clean/internal/core/domain/entities"
+	"go-gin-clean/internal/core/domain/enums"
+	"go-gin-clean/internal/core/ports"
+	"go-gin-clean/internal/core/usecases"
+	"go-gin-clean/pkg/config"
+	"testing"
+	"time"
+)
+
+const testPassword = "Correct-Horse-7"
+
+// testEnv wires the use cases on the in-memory adapters, the way
+// infrastructure.NewContainer wires them on the database.
+type testEnv struct {
+	cfg    *config.Config
+	db     *memory.DB
+	mailer *memory.MailerService
+
+	userRepo         ports.UserRepository
+	refreshTokenRepo ports.RefreshTokenRepository
+	totpService      ports.TOTPService
+	jwtService       ports.JWTService
+
+	users           ports.UserUseCase
+	tokenRevocation ports.TokenRevocationUseCase
+
+	// now is the time TOTP codes are validated against, the wall clock
+	// while it is zero
+	now time.Time
+}
+
+// newTestEnv loads the configuration from env, so tests adjust it with
+// t.Setenv before calling it. oauthService may be nil to use the OIDC
+// service for the configured providers.
+func newTestEnv(t *testing.T, oauthService ports.OAuthService) *testEnv {
+	t.Helper()
+
+	t.Setenv("PASSWORD_HASH_ALGORITHM", config.PasswordAlgorithmBcrypt)
+	t.Setenv("BCRYPT_COST", "4")
+	t.Setenv("AES_KEYS", "1:0123456789abcdef0123456789abcdef")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+
+	db := memory.NewDB()
+	mailer := memory.NewMailerService()
+
+	userRepo := memory.NewUserRepository(db)
+	refreshTokenRepo := memory.NewRefreshTokenRepository(db)
+	roleRepo := memory.NewRoleRepository(db)
+	apiKeyRepo := memory.NewAPIKeyRepository(db)
+
+	// The migrations seed the default role, new accounts need it
+	if _, err := roleRepo.Create(context.Background(), entities.NewRole(enums.RoleUser, "Default role", nil)); err != nil {
+		t.Fatalf("seed role: %v", err)
+	}
+
+	jwtService, err := security.NewJWTService(&cfg.JWT)
+	if err != nil {
+		t.Fatalf("create JWT service: %v", err)
+	}
+	passwordHasher := security.NewPasswordHasher(&cfg.Password)
+	aesService, err := security.NewAESService(&cfg.AES)
+	if err != nil {
+		t.Fatalf("create AES service: %v", err)
+	}
+	env := &testEnv{}
+	totpService := security.NewTOTPService(&cfg.TOTP, func() time.Time {
+		if env.now.IsZero() {
+			return time.Now()
+		}
+		return env.now
+	})
+	if oauthService == nil {
+		oauthService = oauth.NewOIDCService(&cfg.OAuth, nil)
+	}
+
+	loginThrottle := usecases.NewLoginThrottle(memory.NewLoginAttemptRepository(cfg.LoginAttemptRetention()), &cfg.Lockout)
+	passwordPolicy, err := usecases.NewPasswordPolicy(memory.NewPasswordHistoryRepository(db), passwordHasher, &cfg.Policy)
+	if err != nil {
+		t.Fatalf("create password policy: %v", err)
+	}
+	tokenRevocation := usecases.NewTokenRevocationUseCase(memory.NewRevokedTokenRepository(), &cfg.JWT)
+
+	users := usecases.NewUserUseCase(
+		userRepo,
+		usecases.NewEmailUseCase(mailer),
+		refreshTokenRepo,
+		roleRepo,
+		memory.NewRecoveryCodeRepository(db),
+		memory.NewOneTimeTokenRepository(db),
+		memory.NewUserIdentityRepository(db),
+		apiKeyRepo,
+		memory.NewAuditLogRepository(db),
+		memory.NewUnitOfWork(db),
+		jwtService,
+		tokenRevocation,
+		passwordHasher,
+		aesService,
+		security.NewSHA256Service(),
+		totpService,
+		memory.NewMediaService(),
+		oauthService,
+		loginThrottle,
+		passwordPolicy,
+		&cfg.Magic,
+		&cfg.JWT,
+	)
+
+	*env = testEnv{
+		cfg:              cfg,
+		db:               db,
+		mailer:           mailer,
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		totpService:      totpService,
+		jwtService:       jwtService,
+		users:            users,
+		tokenRevocation:  tokenRevocation,
+	}
+	return env
+}
+
+// createUser adds an active user with testPassword.
+func (e *testEnv) createUser(t *testing.T, name, email string) *contracts.UserInfo {
+	t.Helper()
+
+	user, err := e.users.CreateUser(context.Background(), &contracts.CreateUserRequest{
+		Name:     name,
+		Email:    email,
+		Password: testPassword,
+	})
+	if err != nil {
+		t.Fatalf("create user %s: %v", email, err)
+	}
+	return user
+}
@@ -0,0 +1,69 @@
+package usecases_test
+
+import (
+	"context"
+	"go-gin-clean/internal/core/contracts"
+	"testing"
+	"time"
+)
+
+// accessClaims signs in and returns the claims of the issued access token.
+func (e *testEnv) accessClaims(t *testing.T, email string) *contracts.AccessTokenClaims {
+	t.Helper()
+
+	login, err := e.users.Login(context.Background(), &contracts.LoginRequest{Email: email, Password: testPassword})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+
+	claims, err := e.jwtService.ValidateAccessToken(login.AccessToken)
+	if err != nil {
+		t.Fatalf("validate access token: %v", err)
+	}
+	return claims
+}
+
+func (e *testEnv) isRevoked(t *testing.T, claims *contracts.AccessTokenClaims) bool {
+	t.Helper()
+
+	revoked, err := e.tokenRevocation.IsRevoked(context.Background(), claims)
+	if err != nil {
+		t.Fatalf("IsRevoked: %v", err)
+	}
+	return revoked
+}
+
+func TestRevokeAllForUserWithinTheSameSecond(t *testing.T) {
+	env := newTestEnv(t, nil)
+	user := env.createUser(t, "Alice", "alice@example.com")
+
+	before := env.accessClaims(t, "alice@example.com")
+	if err := env.tokenRevocation.RevokeAllForUser(context.Background(), user.ID); err != nil {
+		t.Fatalf("RevokeAllForUser: %v", err)
+	}
+	after := env.accessClaims(t, "alice@example.com")
+
+	if !env.isRevoked(t, before) {
+		t.Fatal("token issued before the watermark is still accepted")
+	}
+
+	if env.isRevoked(t, after) {
+		t.Fatal("token issued after the watermark is rejected")
+	}
+}
+
+func TestLogoutDenylistsPresentedToken(t *testing.T) {
+	env := newTestEnv(t, nil)
+	user := env.createUser(t, "Alice", "alice@example.com")
+
+	claims := env.accessClaims(t, "alice@example.com")
+	if err := env.users.Logout(context.Background(), user.ID, claims); err != nil {
+		t.Fatalf("Logout: %v", err)
+	}
+
+	// Only the jti entry applies to a token issued after the watermark
+	claims.IssuedAt = claims.IssuedAt.Add(time.Hour)
+	if !env.isRevoked(t, claims) {
+		t.Fatal("access token used to log out is still accepted")
+	}
+}
@@ -2,6 +2,8 @@ package usecases
 
 import (
 	"context"
+	"crypto/subtle"
+	"encoding/json"
 	"fmt"
 	"go-gin-clean/internal/core/contracts"
 	"go-gin-clean/internal/core/domain/entities"
@@ -9,8 +11,9 @@ import (
 	"go-gin-clean/internal/core/domain/errors"
 	"go-gin-clean/internal/core/ports"
 	"go-gin-clean/pkg/config"
+	"go-gin-clean/pkg/utils"
 	"log"
-	"strconv"
+	"slices"
 	"strings"
 	"time"
 )
@@ -19,29 +22,92 @@ type UserUseCase struct {
 	userRepo            ports.UserRepository
 	email               ports.EmailUseCase
 	refreshTokenRepo    ports.RefreshTokenRepository
+	roleRepo            ports.RoleRepository
+	recoveryCodeRepo    ports.RecoveryCodeRepository
+	oneTimeTokenRepo    ports.OneTimeTokenRepository
+	userIdentityRepo    ports.UserIdentityRepository
+	apiKeyRepo          ports.APIKeyRepository
+	auditLogRepo        ports.AuditLogRepository
+	unitOfWork          ports.UnitOfWork
 	jwtService          ports.JWTService
-	bcryptService       ports.BcryptService
+	tokenRevocation     ports.TokenRevocationUseCase
+	passwordHasher      ports.PasswordHasher
 	aesService          ports.EncryptionService
+	hashService         ports.HashService
+	totpService         ports.TOTPService
 	localStorageService ports.MediaService
+	oauthService        ports.OAuthService
+	loginThrottle       *LoginThrottle
+	passwordPolicy      *PasswordPolicy
+	magicLinkCfg        *config.MagicLinkConfig
+	jwtCfg              *config.JWTConfig
+}
+
+const (
+	recoveryCodeCount      = 10
+	verifyEmailTokenExpiry = 24 * time.Hour
+	resetTokenExpiry       = 1 * time.Hour
+	emailChangeTokenExpiry = 24 * time.Hour
+	oauthStateExpiry       = 10 * time.Minute
+)
+
+// oauthState travels in an encrypted cookie between the OAuth start and
+// callback requests, so no server-side state is needed.
+type oauthState struct {
+	Provider     string `json:"provider"`
+	State        string `json:"state"`
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"code_verifier"`
+	ExpiresAt    int64  `json:"expires_at"`
 }
 
 func NewUserUseCase(
 	userRepo ports.UserRepository,
 	email ports.EmailUseCase,
 	refreshTokenRepo ports.RefreshTokenRepository,
+	roleRepo ports.RoleRepository,
+	recoveryCodeRepo ports.RecoveryCodeRepository,
+	oneTimeTokenRepo ports.OneTimeTokenRepository,
+	userIdentityRepo ports.UserIdentityRepository,
+	apiKeyRepo ports.APIKeyRepository,
+	auditLogRepo ports.AuditLogRepository,
+	unitOfWork ports.UnitOfWork,
 	jwtService ports.JWTService,
-	bcryptService ports.BcryptService,
+	tokenRevocation ports.TokenRevocationUseCase,
+	passwordHasher ports.PasswordHasher,
 	aesService ports.EncryptionService,
+	hashService ports.HashService,
+	totpService ports.TOTPService,
 	localStorageService ports.MediaService,
+	oauthService ports.OAuthService,
+	loginThrottle *LoginThrottle,
+	passwordPolicy *PasswordPolicy,
+	magicLinkCfg *config.MagicLinkConfig,
+	jwtCfg *config.JWTConfig,
 ) ports.UserUseCase {
 	return &UserUseCase{
 		userRepo:            userRepo,
 		email:               email,
 		refreshTokenRepo:    refreshTokenRepo,
+		roleRepo:            roleRepo,
+		recoveryCodeRepo:    recoveryCodeRepo,
+		oneTimeTokenRepo:    oneTimeTokenRepo,
+		userIdentityRepo:    userIdentityRepo,
+		apiKeyRepo:          apiKeyRepo,
+		auditLogRepo:        auditLogRepo,
+		unitOfWork:          unitOfWork,
 		jwtService:          jwtService,
-		bcryptService:       bcryptService,
+		tokenRevocation:     tokenRevocation,
+		passwordHasher:      passwordHasher,
 		aesService:          aesService,
+		hashService:         hashService,
+		totpService:         totpService,
 		localStorageService: localStorageService,
+		oauthService:        oauthService,
+		loginThrottle:       loginThrottle,
+		passwordPolicy:      passwordPolicy,
+		magicLinkCfg:        magicLinkCfg,
+		jwtCfg:              jwtCfg,
 	}
 }
 
@@ -53,367 +119,1432 @@ func FormatUserInfo(user *entities.User) *contracts.UserInfo {
 		Gender:   user.Gender,
 		Avatar:   user.Avatar,
 		IsActive: user.IsActive,
+		Roles:    user.RoleNames(),
+
+		PendingEmail:     user.PendingEmail,
+		TwoFactorEnabled: user.TwoFactorEnabled,
+		DeletedAt:        user.DeletedAt,
 	}
 }
 
-func (uc *UserUseCase) Login(ctx context.Context, req *contracts.LoginRequest) (*contracts.LoginResponse, error) {
-	user, err := uc.userRepo.FindByEmail(ctx, req.Email)
-	if err != nil {
-		return nil, errors.ErrUserNotFound
+// searchMatch highlights the first of name and email containing term, the
+// same way the repository matched it.
+func searchMatch(user *entities.User, term string) *contracts.SearchMatch {
+	if highlight, ok := utils.Highlight(user.Name, term); ok {
+		return &contracts.SearchMatch{Field: "name", Highlight: highlight}
 	}
 
-	if !user.IsActive {
-		return nil, errors.ErrUserNotFound
+	if highlight, ok := utils.Highlight(user.Email, term); ok {
+		return &contracts.SearchMatch{Field: "email", Highlight: highlight}
 	}
 
-	if err := uc.bcryptService.ValidatePassword(req.Password, user.Password); err != nil {
-		return nil, errors.ErrPasswordNotMatch
+	return nil
+}
+
+// formatSearchResults formats a page of users, highlighting the search
+// term when the spec has one.
+func formatSearchResults(users []*entities.User, spec *contracts.QuerySpec) []contracts.UserInfo {
+	userInfos := make([]contracts.UserInfo, len(users))
+	for i, user := range users {
+		userInfos[i] = *FormatUserInfo(user)
+		if spec != nil && strings.TrimSpace(spec.Search) != "" {
+			userInfos[i].Match = searchMatch(user, spec.Search)
+		}
+	}
+	return userInfos
+}
+
+// resolveRoles looks up the given role names, falling back to the default
+// user role when none are requested.
+func (uc *UserUseCase) resolveRoles(ctx context.Context, names []string) ([]*entities.Role, error) {
+	if len(names) == 0 {
+		names = []string{enums.RoleUser}
 	}
 
-	accessToken, _, err := uc.jwtService.GenerateAccessToken(user)
+	names = slices.Compact(slices.Sorted(slices.Values(names)))
+
+	roles, err := uc.roleRepo.FindByNames(ctx, names)
 	if err != nil {
 		return nil, err
 	}
 
-	refreshToken, expiryAt, err := uc.jwtService.GenerateRefreshToken(user.ID)
-	if err != nil {
+	if len(roles) != len(names) {
+		return nil, errors.ErrRoleNotFound
+	}
+
+	return roles, nil
+}
+
+// inTransaction runs fn with a copy of the use case whose repositories are
+// bound to a single transaction. Audit entries, password history, access
+// token revocation and emails stay outside so they only happen after commit.
+func (uc *UserUseCase) inTransaction(ctx context.Context, fn func(tx *UserUseCase) error) error {
+	return uc.unitOfWork.Do(ctx, func(repos ports.Repositories) error {
+		tx := *uc
+		tx.userRepo = repos.Users()
+		tx.refreshTokenRepo = repos.RefreshTokens()
+		tx.roleRepo = repos.Roles()
+		tx.recoveryCodeRepo = repos.RecoveryCodes()
+		tx.oneTimeTokenRepo = repos.OneTimeTokens()
+		tx.userIdentityRepo = repos.UserIdentities()
+		tx.apiKeyRepo = repos.APIKeys()
+		return fn(&tx)
+	})
+}
+
+func (uc *UserUseCase) Login(ctx context.Context, req *contracts.LoginRequest) (*contracts.LoginResponse, error) {
+	if err := uc.loginThrottle.Check(ctx, req.Email, req.Client.IPAddress); err != nil {
+		uc.auditLoginFailure(ctx, nil, req.Email, "locked")
 		return nil, err
 	}
 
-	hashedRefreshToken, err := uc.aesService.EncryptInternal(refreshToken)
+	user, err := uc.userRepo.FindByEmail(ctx, req.Email)
 	if err != nil {
-		return nil, err
+		uc.loginThrottle.RegisterFailure(ctx, req.Email, req.Client.IPAddress)
+		uc.auditLoginFailure(ctx, nil, req.Email, "unknown_email")
+		return nil, errors.ErrUserNotFound
 	}
 
-	token := entities.NewRefreshToken(user.ID, hashedRefreshToken, expiryAt, false, *user)
+	if !user.IsActive {
+		uc.loginThrottle.RegisterFailure(ctx, req.Email, req.Client.IPAddress)
+		uc.auditLoginFailure(ctx, &user.ID, req.Email, "inactive")
+		return nil, errors.ErrUserNotFound
+	}
 
-	if err := uc.refreshTokenRepo.Save(ctx, token); err != nil {
-		return nil, err
+	if err := uc.passwordHasher.ValidatePassword(req.Password, user.Password); err != nil {
+		uc.loginThrottle.RegisterFailure(ctx, req.Email, req.Client.IPAddress)
+		uc.auditLoginFailure(ctx, &user.ID, req.Email, "invalid_password")
+		return nil, errors.ErrPasswordNotMatch
 	}
 
-	return &contracts.LoginResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		User:         *FormatUserInfo(user),
-	}, nil
-}
+	uc.rehashPassword(ctx, user, req.Password)
 
-func (uc *UserUseCase) Register(ctx context.Context, req *contracts.RegisterRequest) error {
-	if uc.userRepo.ExistsByEmail(ctx, req.Email) {
-		return errors.ErrEmailAlreadyExists
+	if user.TwoFactorEnabled {
+		return uc.mfaChallenge(user)
 	}
 
-	hashedPassword, err := uc.bcryptService.HashPassword(req.Password)
-	if err != nil {
-		return err
+	if err := uc.loginThrottle.Reset(ctx, user.Email); err != nil {
+		log.Printf("Failed to reset login attempts for %s: %v", user.Email, err)
 	}
 
-	user, err := entities.NewUser(req.Name, req.Email, hashedPassword, "", enums.Unknown)
+	return uc.issueTokens(ctx, user, req.Client)
+}
+
+// auditLoginFailure records a rejected login. userID is nil when the email
+// does not belong to an account.
+func (uc *UserUseCase) auditLoginFailure(ctx context.Context, userID *int64, email, reason string) {
+	recordAudit(ctx, uc.auditLogRepo, enums.AuditActionLoginFailed, userID, userID, nil, map[string]any{
+		"email":  email,
+		"reason": reason,
+	})
+}
+
+// mfaChallenge answers a successful first factor with a short-lived MFA
+// token instead of session tokens.
+func (uc *UserUseCase) mfaChallenge(user *entities.User) (*contracts.LoginResponse, error) {
+	mfaToken, _, err := uc.jwtService.GenerateMFAToken(user.ID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	savedUser, err := uc.userRepo.Create(ctx, user)
-	if err != nil {
+	return &contracts.LoginResponse{
+		MFARequired: true,
+		MFAToken:    mfaToken,
+	}, nil
+}
+
+// SendMagicLink emails a single-use sign-in link. Unknown and inactive
+// accounts are ignored silently so the endpoint does not reveal which
+// addresses are registered.
+func (uc *UserUseCase) SendMagicLink(ctx context.Context, email string) error {
+	if err := uc.loginThrottle.Allow(ctx, magicLinkThrottleKey(email), uc.magicLinkCfg.RateLimit, uc.magicLinkCfg.RateWindow); err != nil {
 		return err
 	}
 
-	plainText := fmt.Sprintf("%d_%s", savedUser.ID, time.Now().Add(24*time.Hour).Format(time.RFC3339))
+	user, err := uc.userRepo.FindByEmail(ctx, email)
+	if err != nil || !user.IsActive {
+		return nil
+	}
 
-	token, err := uc.aesService.EncryptURLSafe(plainText)
+	token, err := uc.issueOneTimeToken(ctx, user.ID, enums.TokenPurposeMagicLink, uc.magicLinkCfg.Expiry)
 	if err != nil {
 		return err
 	}
 
-	verificationURL := fmt.Sprintf("%s/verify-email?token=%s", config.GetAppURL(), token)
+	loginURL := fmt.Sprintf("%s/magic-link?token=%s", config.GetAppURL(), token)
 
 	go func() {
-		if err := uc.email.SendVerifyEmail(user.Email, user.Name, verificationURL); err != nil {
-			log.Printf("Failed to send verification email to %s: %v", user.Email, err)
+		if err := uc.email.SendMagicLinkEmail(user.Email, user.Name, loginURL, uc.magicLinkCfg.Expiry); err != nil {
+			log.Printf("Failed to send magic link email to %s: %v", user.Email, err)
 		}
 	}()
 
 	return nil
 }
 
-func (uc *UserUseCase) RefreshToken(ctx context.Context, refreshToken string) (*contracts.RefreshTokenResponse, error) {
-	claims, err := uc.jwtService.ValidateRefreshToken(refreshToken)
+func (uc *UserUseCase) LoginMagicLink(ctx context.Context, req *contracts.MagicLinkLoginRequest) (*contracts.LoginResponse, error) {
+	magicLink, err := uc.consumeOneTimeToken(ctx, req.Token, enums.TokenPurposeMagicLink)
 	if err != nil {
-		return nil, errors.ErrTokenInvalid
+		return nil, err
 	}
 
-	if !uc.refreshTokenRepo.IsTokenValid(ctx, refreshToken) {
-		return nil, errors.ErrTokenInvalid
+	user, err := uc.userRepo.FindByID(ctx, magicLink.UserID)
+	if err != nil {
+		return nil, errors.ErrUserNotFound
 	}
 
-	user, err := uc.userRepo.FindByID(ctx, claims.UserID)
-	if err != nil {
+	if !user.IsActive {
 		return nil, errors.ErrUserNotFound
 	}
 
-	newAccessToken, _, err := uc.jwtService.GenerateAccessToken(user)
+	if user.TwoFactorEnabled {
+		return uc.mfaChallenge(user)
+	}
+
+	return uc.issueTokens(ctx, user, req.Client)
+}
+
+func (uc *UserUseCase) StartOAuth(ctx context.Context, provider string) (*contracts.OAuthStartResponse, error) {
+	state, err := uc.hashService.GenerateToken(32)
 	if err != nil {
 		return nil, err
 	}
 
-	newRefreshToken, expiryAt, err := uc.jwtService.GenerateRefreshToken(user.ID)
+	nonce, err := uc.hashService.GenerateToken(32)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := uc.refreshTokenRepo.RevokeByToken(ctx, refreshToken); err != nil {
+	codeVerifier, err := uc.hashService.GenerateToken(32)
+	if err != nil {
 		return nil, err
 	}
 
-	hashedRefreshToken, err := uc.aesService.EncryptInternal(newRefreshToken)
+	authURL, err := uc.oauthService.AuthCodeURL(ctx, provider, state, nonce, codeVerifier)
 	if err != nil {
 		return nil, err
 	}
 
-	token := entities.NewRefreshToken(user.ID, hashedRefreshToken, expiryAt, false, *user)
+	payload, err := json.Marshal(oauthState{
+		Provider:     provider,
+		State:        state,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		ExpiresAt:    time.Now().Add(oauthStateExpiry).Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	if err := uc.refreshTokenRepo.Save(ctx, token); err != nil {
+	stateCookie, err := uc.aesService.EncryptURLSafe(string(payload))
+	if err != nil {
 		return nil, err
 	}
 
-	return &contracts.RefreshTokenResponse{
-		AccessToken:  newAccessToken,
-		RefreshToken: newRefreshToken,
+	return &contracts.OAuthStartResponse{
+		AuthURL:     authURL,
+		StateCookie: stateCookie,
 	}, nil
 }
 
-func (uc *UserUseCase) Logout(ctx context.Context, userID int64) error {
-	return uc.refreshTokenRepo.RevokeAllByUserID(ctx, userID)
-}
-
-func (uc *UserUseCase) VerifyEmail(ctx context.Context, token string) error {
-	token, err := uc.aesService.DecryptURLSafe(token)
+func (uc *UserUseCase) CompleteOAuth(ctx context.Context, req *contracts.OAuthCallbackRequest) (*contracts.LoginResponse, error) {
+	state, err := uc.decodeOAuthState(req)
 	if err != nil {
-		return errors.ErrTokenInvalid
-	}
-
-	payloads := strings.Split(token, "_")
-	if len(payloads) != 2 {
-		return errors.ErrTokenInvalid
+		return nil, err
 	}
 
-	expiryAt, err := time.Parse(time.RFC3339, payloads[1])
+	identity, err := uc.oauthService.Exchange(ctx, req.Provider, req.Code, state.CodeVerifier, state.Nonce)
 	if err != nil {
-		return errors.ErrTokenInvalid
-	}
+		if err == errors.ErrOAuthProviderNotFound {
+			return nil, err
+		}
 
-	if time.Now().After(expiryAt) {
-		return errors.ErrTokenExpired
+		log.Printf("OAuth code exchange with %s failed: %v", req.Provider, err)
+		return nil, errors.ErrOAuthExchangeFailed
 	}
 
-	userID, err := strconv.ParseInt(payloads[0], 10, 64)
+	user, err := uc.resolveOAuthUser(ctx, identity)
 	if err != nil {
-		return errors.ErrInvalidIDFormat
+		return nil, err
 	}
 
-	user, err := uc.userRepo.FindByID(ctx, userID)
-	if err != nil {
-		return errors.ErrUserNotFound
+	if !user.IsActive {
+		return nil, errors.ErrUserNotFound
 	}
 
-	user.Activate()
+	if user.TwoFactorEnabled {
+		return uc.mfaChallenge(user)
+	}
 
-	_, err = uc.userRepo.Update(ctx, user)
-	return err
+	return uc.issueTokens(ctx, user, req.Client)
 }
 
-func (uc *UserUseCase) SendVerifyEmail(ctx context.Context, email string) error {
-	user, err := uc.userRepo.FindByEmail(ctx, email)
-	if err != nil {
-		return errors.ErrUserNotFound
+func (uc *UserUseCase) decodeOAuthState(req *contracts.OAuthCallbackRequest) (*oauthState, error) {
+	if req.StateCookie == "" || req.State == "" {
+		return nil, errors.ErrOAuthStateInvalid
 	}
 
-	plainText := fmt.Sprintf("%d_%s", user.ID, time.Now().Add(24*time.Hour).Format(time.RFC3339))
-
-	token, err := uc.aesService.EncryptURLSafe(plainText)
+	payload, err := uc.aesService.DecryptURLSafe(req.StateCookie)
 	if err != nil {
-		return err
+		return nil, errors.ErrOAuthStateInvalid
 	}
 
-	verificationURL := fmt.Sprintf("%s/verify-email?token=%s", config.GetAppURL(), token)
+	var state oauthState
+	if err := json.Unmarshal([]byte(payload), &state); err != nil {
+		return nil, errors.ErrOAuthStateInvalid
+	}
 
-	go func() {
-		if err := uc.email.SendVerifyEmail(user.Email, user.Name, verificationURL); err != nil {
-			log.Printf("Failed to send verification email to %s: %v", user.Email, err)
-		}
-	}()
+	if state.Provider != req.Provider ||
+		subtle.ConstantTimeCompare([]byte(state.State), []byte(req.State)) != 1 ||
+		time.Now().Unix() > state.ExpiresAt {
+		return nil, errors.ErrOAuthStateInvalid
+	}
 
-	return nil
+	return &state, nil
 }
 
-func (uc *UserUseCase) SendResetPassword(ctx context.Context, email string) error {
-	user, err := uc.userRepo.FindByEmail(ctx, email)
-	if err != nil {
-		return errors.ErrUserNotFound
+// resolveOAuthUser returns the user linked to the external identity. Unknown
+// identities are linked to the account with the same verified email, or a new
+// active account is created for them.
+func (uc *UserUseCase) resolveOAuthUser(ctx context.Context, identity *contracts.ExternalIdentity) (*entities.User, error) {
+	linked, err := uc.userIdentityRepo.FindByProviderSubject(ctx, identity.Provider, identity.Subject)
+	if err == nil {
+		user, err := uc.userRepo.FindByID(ctx, linked.UserID)
+		if err != nil {
+			return nil, errors.ErrUserNotFound
+		}
+		return user, nil
 	}
 
-	plainText := fmt.Sprintf("%s_%s", user.Email, time.Now().Add(1*time.Hour).Format(time.RFC3339))
-
-	token, err := uc.aesService.EncryptURLSafe(plainText)
-	if err != nil {
-		return err
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, errors.ErrOAuthEmailNotVerified
 	}
 
-	resetURL := fmt.Sprintf("%s/reset-password?token=%s", config.GetAppURL(), token)
+	// A new account and its identity link are created together, so a failed
+	// link does not leave an account nobody can sign in to.
+	user, _ := uc.userRepo.FindByEmail(ctx, identity.Email)
 
-	go func() {
-		if err := uc.email.SendResetPasswordEmail(user.Email, user.Name, resetURL); err != nil {
-			log.Printf("Failed to send password reset email to %s: %v", user.Email, err)
+	err = uc.inTransaction(ctx, func(tx *UserUseCase) error {
+		if user == nil {
+			var err error
+			if user, err = tx.createOAuthUser(ctx, identity); err != nil {
+				return err
+			}
 		}
-	}()
-
-	return nil
-}
 
-func (uc *UserUseCase) ResetPassword(ctx context.Context, req *contracts.ResetPasswordRequest) error {
-	paylaod, err := uc.aesService.DecryptURLSafe(req.Token)
+		return tx.userIdentityRepo.Create(ctx, entities.NewUserIdentity(user.ID, identity.Provider, identity.Subject, identity.Email))
+	})
 	if err != nil {
-		return errors.ErrTokenInvalid
+		return nil, err
 	}
 
-	parts := strings.Split(paylaod, "_")
-	if len(parts) != 2 {
-		return errors.ErrTokenInvalid
+	return user, nil
+}
+
+// createOAuthUser registers an account for a new external identity. The
+// random password is never disclosed; the user can set one via reset.
+func (uc *UserUseCase) createOAuthUser(ctx context.Context, identity *contracts.ExternalIdentity) (*entities.User, error) {
+	randomPassword, err := uc.hashService.GenerateToken(32)
+	if err != nil {
+		return nil, err
 	}
 
-	email := parts[0]
-	expiryAt, err := time.Parse(time.RFC3339, parts[1])
+	hashedPassword, err := uc.passwordHasher.HashPassword(randomPassword)
 	if err != nil {
-		return errors.ErrTokenInvalid
+		return nil, err
 	}
 
-	if time.Now().After(expiryAt) {
-		return errors.ErrTokenExpired
+	name := identity.Name
+	if name == "" {
+		name, _, _ = strings.Cut(identity.Email, "@")
 	}
 
-	user, err := uc.userRepo.FindByEmail(ctx, email)
+	user, err := entities.NewUser(name, identity.Email, hashedPassword, "", enums.Unknown)
 	if err != nil {
-		return errors.ErrUserNotFound
+		return nil, err
 	}
 
-	hashedPassword, err := uc.bcryptService.HashPassword(req.NewPassword)
+	roles, err := uc.resolveRoles(ctx, nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if err := user.ChangePassword(hashedPassword); err != nil {
-		return err
+	for _, role := range roles {
+		user.Roles = append(user.Roles, *role)
 	}
 
-	_, err = uc.userRepo.Update(ctx, user)
-	return err
+	// The provider has already verified the email address
+	user.Activate()
+
+	return uc.userRepo.Create(ctx, user)
 }
 
-func (uc *UserUseCase) GetAllUsers(ctx context.Context, page, pageSize int, search string) (*contracts.PaginationResponse[contracts.UserInfo], error) {
-	offset := contracts.Offset(page, pageSize)
-	users, total, err := uc.userRepo.FindAll(ctx, pageSize, offset, search)
+func (uc *UserUseCase) LoginTwoFactor(ctx context.Context, req *contracts.TwoFactorLoginRequest) (*contracts.LoginResponse, error) {
+	claims, err := uc.jwtService.ValidateMFAToken(req.MFAToken)
 	if err != nil {
-		return nil, err
+		return nil, errors.ErrTokenInvalid
 	}
 
-	userInfos := make([]contracts.UserInfo, len(users))
-	for i, user := range users {
-		userInfos[i] = *FormatUserInfo(user)
+	user, err := uc.userRepo.FindByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, errors.ErrUserNotFound
 	}
 
-	return contracts.NewPaginationResponse(userInfos, page, pageSize, int(total)), nil
-}
-
-func (uc *UserUseCase) GetUserByID(ctx context.Context, userID int64) (*contracts.UserInfo, error) {
-	user, err := uc.userRepo.FindByID(ctx, userID)
-	if err != nil {
+	if !user.IsActive {
 		return nil, errors.ErrUserNotFound
 	}
 
-	return FormatUserInfo(user), nil
-}
+	if !user.TwoFactorEnabled {
+		return nil, errors.ErrTwoFactorNotEnabled
+	}
 
-func (uc *UserUseCase) CreateUser(ctx context.Context, req *contracts.CreateUserRequest) (*contracts.UserInfo, error) {
-	if uc.userRepo.ExistsByEmail(ctx, req.Email) {
-		return nil, errors.ErrEmailAlreadyExists
+	if err := uc.loginThrottle.Check(ctx, user.Email, req.Client.IPAddress); err != nil {
+		return nil, err
 	}
 
-	hashedPassword, err := uc.bcryptService.HashPassword(req.Password)
-	if err != nil {
+	if err := uc.verifyTwoFactorCode(ctx, user, req.Code, true); err != nil {
+		uc.loginThrottle.RegisterFailure(ctx, user.Email, req.Client.IPAddress)
+		uc.auditLoginFailure(ctx, &user.ID, user.Email, "invalid_two_factor_code")
 		return nil, err
 	}
 
-	user, err := entities.NewUser(req.Name, req.Email, hashedPassword, "", enums.Unknown)
+	if err := uc.loginThrottle.Reset(ctx, user.Email); err != nil {
+		log.Printf("Failed to reset login attempts for %s: %v", user.Email, err)
+	}
+
+	return uc.issueTokens(ctx, user, req.Client)
+}
+
+// issueTokens creates a new access/refresh token pair for an authenticated user.
+func (uc *UserUseCase) issueTokens(ctx context.Context, user *entities.User, client contracts.ClientInfo) (*contracts.LoginResponse, error) {
+	familyID, err := uc.hashService.GenerateToken(16)
 	if err != nil {
 		return nil, err
 	}
 
-	user.IsActive = true
+	session := entities.NewRefreshToken(user.ID, "", familyID, nil, time.Time{}, false, *user)
+	session.SetSessionMetadata(client.UserAgent, client.IPAddress, time.Now())
 
-	savedUser, err := uc.userRepo.Create(ctx, user)
+	accessToken, refreshToken, err := uc.issueTokenPair(ctx, user, session)
 	if err != nil {
 		return nil, err
 	}
 
-	return FormatUserInfo(savedUser), nil
+	recordAudit(ctx, uc.auditLogRepo, enums.AuditActionLogin, &user.ID, &user.ID, nil, map[string]any{
+		"session_id": familyID,
+	})
+
+	return &contracts.LoginResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		User:         *FormatUserInfo(user),
+	}, nil
 }
 
-func (uc *UserUseCase) UpdateUser(ctx context.Context, userID int64, req *contracts.UpdateUserRequest) (*contracts.UserInfo, error) {
-	user, err := uc.userRepo.FindByID(ctx, userID)
+// issueTokenPair generates an access token and a refresh token for the session
+// (token family and device metadata) described by token, then persists the
+// hashed refresh token.
+func (uc *UserUseCase) issueTokenPair(ctx context.Context, user *entities.User, token *entities.RefreshToken) (string, string, error) {
+	accessToken, _, err := uc.jwtService.GenerateAccessToken(user, token.FamilyID)
 	if err != nil {
-		return nil, errors.ErrUserNotFound
+		return "", "", err
 	}
 
-	if req.Name != nil {
-		user.Name = *req.Name
+	refreshToken, expiryAt, err := uc.jwtService.GenerateRefreshToken(user.ID)
+	if err != nil {
+		return "", "", err
 	}
 
-	if req.Avatar != nil {
-		path := fmt.Sprintf("avatars/user_%d/", user.ID)
+	token.Token = uc.hashService.Hash(refreshToken)
+	token.ExpiryAt = expiryAt
 
-		filePath, err := uc.localStorageService.UploadFile(req.Avatar.Filename, req.Avatar.Size, req.Avatar.Content, path)
-		if err != nil {
-			return nil, err
-		}
+	if err := uc.refreshTokenRepo.Save(ctx, token); err != nil {
+		return "", "", err
+	}
 
-		user.Avatar = *filePath
+	return accessToken, refreshToken, nil
+}
+
+func (uc *UserUseCase) Register(ctx context.Context, req *contracts.RegisterRequest) error {
+	if uc.userRepo.ExistsByEmail(ctx, req.Email) {
+		return errors.ErrEmailAlreadyExists
 	}
 
-	if req.Gender != nil {
-		user.Gender = *req.Gender
+	if err := uc.passwordPolicy.Validate(ctx, req.Password, &entities.User{Name: req.Name, Email: req.Email}); err != nil {
+		return err
 	}
 
-	updatedUser, err := uc.userRepo.Update(ctx, user)
+	hashedPassword, err := uc.passwordHasher.HashPassword(req.Password)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return FormatUserInfo(updatedUser), nil
-}
+	user, err := entities.NewUser(req.Name, req.Email, hashedPassword, "", enums.Unknown)
+	if err != nil {
+		return err
+	}
 
-func (uc *UserUseCase) ChangePassword(ctx context.Context, userID int64, req *contracts.ChangePasswordRequest) error {
-	user, err := uc.userRepo.FindByID(ctx, userID)
+	roles, err := uc.resolveRoles(ctx, nil)
 	if err != nil {
-		return errors.ErrUserNotFound
+		return err
 	}
 
-	if err := uc.bcryptService.ValidatePassword(req.OldPassword, user.Password); err != nil {
-		return errors.ErrPasswordNotMatch
+	for _, role := range roles {
+		user.Roles = append(user.Roles, *role)
 	}
 
-	hashedPassword, err := uc.bcryptService.HashPassword(req.NewPassword)
+	var token string
+	err = uc.inTransaction(ctx, func(tx *UserUseCase) error {
+		savedUser, err := tx.userRepo.Create(ctx, user)
+		if err != nil {
+			return err
+		}
+
+		token, err = tx.issueOneTimeToken(ctx, savedUser.ID, enums.TokenPurposeEmailVerification, verifyEmailTokenExpiry)
+		return err
+	})
 	if err != nil {
 		return err
 	}
 
-	if err := user.ChangePassword(hashedPassword); err != nil {
-		return err
-	}
+	uc.passwordPolicy.Record(ctx, user.ID, user.Password)
+
+	verificationURL := fmt.Sprintf("%s/verify-email?token=%s", config.GetAppURL(), token)
+
+	go func() {
+		if err := uc.email.SendVerifyEmail(user.Email, user.Name, verificationURL); err != nil {
+			log.Printf("Failed to send verification email to %s: %v", user.Email, err)
+		}
+	}()
 
-	_, err = uc.userRepo.Update(ctx, user)
-	return err
+	return nil
+}
+
+func (uc *UserUseCase) RefreshToken(ctx context.Context, req *contracts.RefreshTokenRequest) (*contracts.RefreshTokenResponse, error) {
+	claims, err := uc.jwtService.ValidateRefreshToken(req.RefreshToken)
+	if err != nil {
+		return nil, errors.ErrTokenInvalid
+	}
+
+	storedToken, err := uc.refreshTokenRepo.FindByToken(ctx, uc.hashService.Hash(req.RefreshToken))
+	if err != nil || storedToken.UserID != claims.UserID {
+		return nil, errors.ErrTokenInvalid
+	}
+
+	if storedToken.IsRotated() {
+		uc.handleRefreshTokenReuse(ctx, storedToken)
+		return nil, errors.ErrTokenReused
+	}
+
+	if !storedToken.IsValid() {
+		return nil, errors.ErrTokenInvalid
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, errors.ErrUserNotFound
+	}
+
+	// The old token is only marked rotated if its successor is stored too.
+	var newAccessToken, newRefreshToken string
+	err = uc.inTransaction(ctx, func(tx *UserUseCase) error {
+		// Losing this race means another request already rotated the same
+		// token; Rotate then returns ErrTokenReused. Other errors are passed
+		// on as they are so a failing database does not revoke the session.
+		if err := tx.refreshTokenRepo.Rotate(ctx, storedToken); err != nil {
+			return err
+		}
+
+		successor := entities.NewRefreshToken(user.ID, "", storedToken.FamilyID, &storedToken.ID, time.Time{}, false, *user)
+		successor.SetSessionMetadata(req.Client.UserAgent, req.Client.IPAddress, storedToken.SessionStartedAt)
+
+		var err error
+		newAccessToken, newRefreshToken, err = tx.issueTokenPair(ctx, user, successor)
+		return err
+	})
+	if err == errors.ErrTokenReused {
+		uc.handleRefreshTokenReuse(ctx, storedToken)
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &contracts.RefreshTokenResponse{
+		AccessToken:  newAccessToken,
+		RefreshToken: newRefreshToken,
+	}, nil
+}
+
+// handleRefreshTokenReuse revokes the whole family of a replayed refresh token
+// (or every session of the user when configured) and records the event.
+func (uc *UserUseCase) handleRefreshTokenReuse(ctx context.Context, token *entities.RefreshToken) {
+	revokeAll := uc.jwtCfg.RevokeAllOnReuse
+
+	if err := uc.refreshTokenRepo.RevokeFamily(ctx, token.FamilyID); err != nil {
+		log.Printf("Failed to revoke refresh token family %s: %v", token.FamilyID, err)
+	}
+
+	if err := uc.tokenRevocation.RevokeSession(ctx, token.FamilyID); err != nil {
+		log.Printf("Failed to revoke access tokens of session %s: %v", token.FamilyID, err)
+	}
+
+	if revokeAll {
+		if err := uc.revokeAllUserTokens(ctx, token.UserID); err != nil {
+			log.Printf("Failed to revoke tokens of user %d: %v", token.UserID, err)
+		}
+
+		if err := uc.apiKeyRepo.RevokeAllByUserID(ctx, token.UserID); err != nil {
+			log.Printf("Failed to revoke api keys of user %d: %v", token.UserID, err)
+		}
+	}
+
+	recordAudit(ctx, uc.auditLogRepo, enums.AuditActionRefreshTokenReuse, nil, &token.UserID, nil, map[string]any{
+		"family_id":            token.FamilyID,
+		"token_id":             token.ID,
+		"revoked_all_sessions": revokeAll,
+	})
+}
+
+func (uc *UserUseCase) Logout(ctx context.Context, userID int64, current *contracts.AccessTokenClaims) error {
+	if err := uc.revokeAllUserTokens(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := uc.revokeAccessToken(ctx, current); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, uc.auditLogRepo, enums.AuditActionLogout, &userID, &userID, nil, nil)
+	return nil
+}
+
+// revokeAllUserTokens signs the user out everywhere: refresh tokens are
+// revoked and access tokens issued so far stop being accepted.
+func (uc *UserUseCase) revokeAllUserTokens(ctx context.Context, userID int64) error {
+	if err := uc.refreshTokenRepo.RevokeAllByUserID(ctx, userID); err != nil {
+		return err
+	}
+
+	return uc.tokenRevocation.RevokeAllForUser(ctx, userID)
+}
+
+// revokeAccessToken denylists the jti of the access token a request was
+// made with, so it stops working even within the second of the watermark.
+func (uc *UserUseCase) revokeAccessToken(ctx context.Context, current *contracts.AccessTokenClaims) error {
+	if current == nil {
+		return nil
+	}
+
+	return uc.tokenRevocation.RevokeToken(ctx, current.TokenID, current.ExpiresAt)
+}
+
+// revokeAllCredentials revokes every refresh token and API key of the user,
+// for when the password may be known to someone else or the account is gone.
+// Access tokens are revoked separately, after commit.
+func (uc *UserUseCase) revokeAllCredentials(ctx context.Context, userID int64) error {
+	if err := uc.refreshTokenRepo.RevokeAllByUserID(ctx, userID); err != nil {
+		return err
+	}
+
+	return uc.apiKeyRepo.RevokeAllByUserID(ctx, userID)
+}
+
+// ListSessions returns one entry per active token family. The session ID is
+// the family ID, which stays stable across refresh token rotations.
+func (uc *UserUseCase) ListSessions(ctx context.Context, userID int64, currentSessionID string) ([]contracts.SessionInfo, error) {
+	tokens, err := uc.refreshTokenRepo.FindActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]contracts.SessionInfo, len(tokens))
+	for i, token := range tokens {
+		sessions[i] = contracts.SessionInfo{
+			ID:         token.FamilyID,
+			UserAgent:  token.UserAgent,
+			IPAddress:  token.IPAddress,
+			CreatedAt:  token.SessionStartedAt,
+			LastUsedAt: token.LastUsedAt,
+			ExpiresAt:  token.ExpiryAt,
+			Current:    token.FamilyID == currentSessionID,
+		}
+	}
+
+	return sessions, nil
+}
+
+func (uc *UserUseCase) RevokeSession(ctx context.Context, userID int64, sessionID string, current *contracts.AccessTokenClaims) error {
+	tokens, err := uc.refreshTokenRepo.FindActiveByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, token := range tokens {
+		if token.FamilyID == sessionID {
+			if err := uc.refreshTokenRepo.RevokeFamily(ctx, sessionID); err != nil {
+				return err
+			}
+
+			if current != nil && current.SessionID == sessionID {
+				if err := uc.revokeAccessToken(ctx, current); err != nil {
+					return err
+				}
+			}
+
+			return uc.tokenRevocation.RevokeSession(ctx, sessionID)
+		}
+	}
+
+	return errors.ErrSessionNotFound
+}
+
+func (uc *UserUseCase) RevokeOtherSessions(ctx context.Context, userID int64, currentSessionID string) error {
+	if currentSessionID == "" {
+		return errors.ErrSessionNotFound
+	}
+
+	tokens, err := uc.refreshTokenRepo.FindActiveByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.refreshTokenRepo.RevokeAllByUserIDExceptFamily(ctx, userID, currentSessionID); err != nil {
+		return err
+	}
+
+	for _, token := range tokens {
+		if token.FamilyID == currentSessionID {
+			continue
+		}
+
+		if err := uc.tokenRevocation.RevokeSession(ctx, token.FamilyID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (uc *UserUseCase) VerifyEmail(ctx context.Context, token string) error {
+	return uc.inTransaction(ctx, func(tx *UserUseCase) error {
+		verification, err := tx.consumeOneTimeToken(ctx, token, enums.TokenPurposeEmailVerification)
+		if err != nil {
+			return err
+		}
+
+		user, err := tx.userRepo.FindByID(ctx, verification.UserID)
+		if err != nil {
+			return errors.ErrUserNotFound
+		}
+
+		user.Activate()
+
+		_, err = tx.userRepo.Update(ctx, user)
+		return err
+	})
+}
+
+func (uc *UserUseCase) SendVerifyEmail(ctx context.Context, email string) error {
+	user, err := uc.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		return errors.ErrUserNotFound
+	}
+
+	token, err := uc.issueOneTimeToken(ctx, user.ID, enums.TokenPurposeEmailVerification, verifyEmailTokenExpiry)
+	if err != nil {
+		return err
+	}
+
+	verificationURL := fmt.Sprintf("%s/verify-email?token=%s", config.GetAppURL(), token)
+
+	go func() {
+		if err := uc.email.SendVerifyEmail(user.Email, user.Name, verificationURL); err != nil {
+			log.Printf("Failed to send verification email to %s: %v", user.Email, err)
+		}
+	}()
+
+	return nil
+}
+
+func (uc *UserUseCase) SendResetPassword(ctx context.Context, email string) error {
+	user, err := uc.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		return errors.ErrUserNotFound
+	}
+
+	token, err := uc.issueOneTimeToken(ctx, user.ID, enums.TokenPurposePasswordReset, resetTokenExpiry)
+	if err != nil {
+		return err
+	}
+
+	resetURL := fmt.Sprintf("%s/reset-password?token=%s", config.GetAppURL(), token)
+
+	go func() {
+		if err := uc.email.SendResetPasswordEmail(user.Email, user.Name, resetURL); err != nil {
+			log.Printf("Failed to send password reset email to %s: %v", user.Email, err)
+		}
+	}()
+
+	return nil
+}
+
+func (uc *UserUseCase) ResetPassword(ctx context.Context, req *contracts.ResetPasswordRequest) error {
+	// Check the policy before consuming the token so a rejected password
+	// does not burn the reset link.
+	pending, err := uc.oneTimeTokenRepo.FindUnconsumed(ctx, uc.hashService.Hash(req.Token), enums.TokenPurposePasswordReset)
+	if err != nil {
+		return errors.ErrTokenInvalid
+	}
+
+	if pending.IsExpired() {
+		return errors.ErrTokenExpired
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, pending.UserID)
+	if err != nil {
+		return errors.ErrUserNotFound
+	}
+
+	if err := uc.passwordPolicy.Validate(ctx, req.NewPassword, user); err != nil {
+		return err
+	}
+
+	hashedPassword, err := uc.passwordHasher.HashPassword(req.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	if err := user.ChangePassword(hashedPassword); err != nil {
+		return err
+	}
+
+	err = uc.inTransaction(ctx, func(tx *UserUseCase) error {
+		if _, err := tx.consumeOneTimeToken(ctx, req.Token, enums.TokenPurposePasswordReset); err != nil {
+			return err
+		}
+
+		if _, err := tx.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+
+		return tx.revokeAllCredentials(ctx, user.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	uc.passwordPolicy.Record(ctx, user.ID, hashedPassword)
+	recordAudit(ctx, uc.auditLogRepo, enums.AuditActionPasswordReset, &user.ID, &user.ID, nil, nil)
+
+	return uc.tokenRevocation.RevokeAllForUser(ctx, user.ID)
+}
+
+// rehashPassword upgrades a hash created with an outdated algorithm or
+// parameters. Failures are logged only, the login itself already succeeded.
+func (uc *UserUseCase) rehashPassword(ctx context.Context, user *entities.User, password string) {
+	if !uc.passwordHasher.NeedsRehash(user.Password) {
+		return
+	}
+
+	hashedPassword, err := uc.passwordHasher.HashPassword(password)
+	if err != nil {
+		log.Printf("Failed to rehash password for user %d: %v", user.ID, err)
+		return
+	}
+
+	user.Password = hashedPassword
+	if err := uc.userRepo.UpdatePassword(ctx, user); err != nil {
+		log.Printf("Failed to store rehashed password for user %d: %v", user.ID, err)
+	}
+}
+
+// issueOneTimeToken creates a single-use link token, replacing any
+// outstanding token of the same purpose. Only its hash is persisted.
+func (uc *UserUseCase) issueOneTimeToken(ctx context.Context, userID int64, purpose enums.TokenPurpose, expiry time.Duration) (string, error) {
+	token, err := uc.hashService.GenerateToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	oneTimeToken := entities.NewOneTimeToken(userID, purpose, uc.hashService.Hash(token), time.Now().Add(expiry))
+	if err := uc.oneTimeTokenRepo.Issue(ctx, oneTimeToken); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+func (uc *UserUseCase) consumeOneTimeToken(ctx context.Context, token string, purpose enums.TokenPurpose) (*entities.OneTimeToken, error) {
+	if token == "" {
+		return nil, errors.ErrTokenInvalid
+	}
+
+	oneTimeToken, err := uc.oneTimeTokenRepo.Consume(ctx, uc.hashService.Hash(token), purpose)
+	if err != nil {
+		return nil, errors.ErrTokenInvalid
+	}
+
+	if oneTimeToken.IsExpired() {
+		return nil, errors.ErrTokenExpired
+	}
+
+	return oneTimeToken, nil
+}
+
+func (uc *UserUseCase) GetAllUsers(ctx context.Context, page, pageSize int, spec *contracts.QuerySpec) (*contracts.PaginationResponse[contracts.UserInfo], error) {
+	if err := contracts.UserQueryFields.Validate(spec); err != nil {
+		return nil, err
+	}
+
+	offset := contracts.Offset(page, pageSize)
+	users, total, err := uc.userRepo.FindAll(ctx, pageSize, offset, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return contracts.NewPaginationResponse(formatSearchResults(users, spec), page, pageSize, int(total)), nil
+}
+
+func (uc *UserUseCase) GetUsersByCursor(ctx context.Context, page *contracts.CursorRequest, spec *contracts.QuerySpec) (*contracts.CursorResponse[contracts.UserInfo], error) {
+	if err := contracts.UserQueryFields.ValidateKeyset(spec); err != nil {
+		return nil, err
+	}
+
+	users, result, err := uc.userRepo.FindAllByCursor(ctx, page, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return contracts.NewCursorResponse(formatSearchResults(users, spec), page.Limit, result), nil
+}
+
+func (uc *UserUseCase) GetUserByID(ctx context.Context, userID int64) (*contracts.UserInfo, error) {
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.ErrUserNotFound
+	}
+
+	return FormatUserInfo(user), nil
+}
+
+func (uc *UserUseCase) CreateUser(ctx context.Context, req *contracts.CreateUserRequest) (*contracts.UserInfo, error) {
+	if uc.userRepo.ExistsByEmail(ctx, req.Email) {
+		return nil, errors.ErrEmailAlreadyExists
+	}
+
+	if err := uc.passwordPolicy.Validate(ctx, req.Password, &entities.User{Name: req.Name, Email: req.Email}); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := uc.passwordHasher.HashPassword(req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := entities.NewUser(req.Name, req.Email, hashedPassword, "", enums.Unknown)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := uc.resolveRoles(ctx, req.Roles)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, role := range roles {
+		user.Roles = append(user.Roles, *role)
+	}
+
+	user.IsActive = true
+
+	savedUser, err := uc.userRepo.Create(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.passwordPolicy.Record(ctx, savedUser.ID, savedUser.Password)
+	recordAudit(ctx, uc.auditLogRepo, enums.AuditActionUserCreated, nil, &savedUser.ID, auditDiff(nil, auditUserSnapshot(savedUser)), nil)
+
+	return FormatUserInfo(savedUser), nil
+}
+
+func (uc *UserUseCase) UpdateUser(ctx context.Context, userID int64, req *contracts.UpdateUserRequest) (*contracts.UserInfo, error) {
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.ErrUserNotFound
+	}
+
+	before := auditUserSnapshot(user)
+
+	if req.Name != nil {
+		user.Name = *req.Name
+	}
+
+	if req.Avatar != nil {
+		path := fmt.Sprintf("avatars/user_%d/", user.ID)
+
+		filePath, err := uc.localStorageService.UploadFile(req.Avatar.Filename, req.Avatar.Size, req.Avatar.Content, path)
+		if err != nil {
+			return nil, err
+		}
+
+		user.Avatar = *filePath
+	}
+
+	if req.Gender != nil {
+		user.Gender = *req.Gender
+	}
+
+	updatedUser, err := uc.userRepo.Update(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	if changes := auditDiff(before, auditUserSnapshot(updatedUser)); len(changes) > 0 {
+		recordAudit(ctx, uc.auditLogRepo, enums.AuditActionUserUpdated, nil, &updatedUser.ID, changes, nil)
+	}
+
+	return FormatUserInfo(updatedUser), nil
+}
+
+// confirmPassword checks the current password of a signed-in user before a
+// sensitive change. Wrong passwords count towards the same lockout as failed
+// logins, so a stolen session cannot be used to guess the password.
+func (uc *UserUseCase) confirmPassword(ctx context.Context, user *entities.User, password string) error {
+	ip := contracts.AuditActorFromContext(ctx).IPAddress
+	if err := uc.loginThrottle.Check(ctx, user.Email, ip); err != nil {
+		return err
+	}
+
+	if err := uc.passwordHasher.ValidatePassword(password, user.Password); err != nil {
+		uc.loginThrottle.RegisterFailure(ctx, user.Email, ip)
+		return errors.ErrPasswordNotMatch
+	}
+
+	return nil
+}
+
+func (uc *UserUseCase) ChangePassword(ctx context.Context, userID int64, req *contracts.ChangePasswordRequest) error {
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return errors.ErrUserNotFound
+	}
+
+	if err := uc.confirmPassword(ctx, user, req.OldPassword); err != nil {
+		return err
+	}
+
+	if err := uc.passwordPolicy.Validate(ctx, req.NewPassword, user); err != nil {
+		return err
+	}
+
+	hashedPassword, err := uc.passwordHasher.HashPassword(req.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	if err := user.ChangePassword(hashedPassword); err != nil {
+		return err
+	}
+
+	err = uc.inTransaction(ctx, func(tx *UserUseCase) error {
+		if _, err := tx.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+
+		return tx.revokeAllCredentials(ctx, user.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	uc.passwordPolicy.Record(ctx, user.ID, hashedPassword)
+	recordAudit(ctx, uc.auditLogRepo, enums.AuditActionPasswordChanged, &user.ID, &user.ID, nil, nil)
+
+	return uc.tokenRevocation.RevokeAllForUser(ctx, user.ID)
+}
+
+// RequestEmailChange stores the new address as pending and sends a
+// confirmation link to it. The current address only gets a notice, the
+// switch happens in ConfirmEmailChange.
+func (uc *UserUseCase) RequestEmailChange(ctx context.Context, userID int64, req *contracts.ChangeEmailRequest) error {
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return errors.ErrUserNotFound
+	}
+
+	if err := uc.confirmPassword(ctx, user, req.Password); err != nil {
+		return err
+	}
+
+	if strings.EqualFold(req.NewEmail, user.Email) {
+		return errors.ErrEmailUnchanged
+	}
+
+	if uc.userRepo.ExistsByEmail(ctx, req.NewEmail) {
+		return errors.ErrEmailAlreadyExists
+	}
+
+	user.RequestEmailChange(req.NewEmail)
+
+	var token string
+	err = uc.inTransaction(ctx, func(tx *UserUseCase) error {
+		if err := tx.userRepo.UpdateEmail(ctx, user); err != nil {
+			return err
+		}
+
+		var err error
+		token, err = tx.issueOneTimeToken(ctx, user.ID, enums.TokenPurposeEmailChange, emailChangeTokenExpiry)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	recordAudit(ctx, uc.auditLogRepo, enums.AuditActionEmailChangeRequested, nil, &user.ID, nil, map[string]any{
+		"new_email": user.PendingEmail,
+	})
+
+	confirmURL := fmt.Sprintf("%s/confirm-email-change?token=%s", config.GetAppURL(), token)
+
+	go func() {
+		if err := uc.email.SendEmailChangeConfirmation(user.PendingEmail, user.Name, confirmURL, emailChangeTokenExpiry); err != nil {
+			log.Printf("Failed to send email change confirmation to %s: %v", user.PendingEmail, err)
+		}
+
+		if err := uc.email.SendEmailChangeNotice(user.Email, user.Name, user.PendingEmail); err != nil {
+			log.Printf("Failed to send email change notice to %s: %v", user.Email, err)
+		}
+	}()
+
+	return nil
+}
+
+// ConfirmEmailChange switches the user to the pending address and signs
+// out every session, since they were opened under the old address.
+func (uc *UserUseCase) ConfirmEmailChange(ctx context.Context, token string) error {
+	var user *entities.User
+	var before map[string]any
+
+	err := uc.inTransaction(ctx, func(tx *UserUseCase) error {
+		changeToken, err := tx.consumeOneTimeToken(ctx, token, enums.TokenPurposeEmailChange)
+		if err != nil {
+			return err
+		}
+
+		user, err = tx.userRepo.FindByID(ctx, changeToken.UserID)
+		if err != nil {
+			return errors.ErrUserNotFound
+		}
+
+		if user.PendingEmail == "" {
+			return errors.ErrTokenInvalid
+		}
+
+		// The address may have been taken since the change was requested.
+		if tx.userRepo.ExistsByEmail(ctx, user.PendingEmail) {
+			return errors.ErrEmailAlreadyExists
+		}
+
+		before = auditUserSnapshot(user)
+
+		user.ConfirmEmailChange()
+		if err := tx.userRepo.UpdateEmail(ctx, user); err != nil {
+			return err
+		}
+
+		return tx.refreshTokenRepo.RevokeAllByUserID(ctx, user.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	recordAudit(ctx, uc.auditLogRepo, enums.AuditActionEmailChanged, &user.ID, &user.ID, auditDiff(before, auditUserSnapshot(user)), nil)
+
+	return uc.tokenRevocation.RevokeAllForUser(ctx, user.ID)
 }
 
 func (uc *UserUseCase) DeleteUser(ctx context.Context, userID int64) error {
-	return uc.userRepo.Delete(ctx, userID)
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return errors.ErrUserNotFound
+	}
+
+	err = uc.inTransaction(ctx, func(tx *UserUseCase) error {
+		if err := tx.revokeAllCredentials(ctx, userID); err != nil {
+			return err
+		}
+
+		return tx.userRepo.Delete(ctx, userID)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := uc.tokenRevocation.RevokeAllForUser(ctx, userID); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, uc.auditLogRepo, enums.AuditActionUserDeleted, nil, &userID, auditDiff(auditUserSnapshot(user), nil), nil)
+	return nil
+}
+
+func (uc *UserUseCase) GetDeletedUsers(ctx context.Context, page, pageSize int, search string) (*contracts.PaginationResponse[contracts.UserInfo], error) {
+	offset := contracts.Offset(page, pageSize)
+	users, total, err := uc.userRepo.FindAllDeleted(ctx, pageSize, offset, search)
+	if err != nil {
+		return nil, err
+	}
+
+	userInfos := make([]contracts.UserInfo, len(users))
+	for i, user := range users {
+		userInfos[i] = *FormatUserInfo(user)
+	}
+
+	return contracts.NewPaginationResponse(userInfos, page, pageSize, int(total)), nil
+}
+
+// RestoreUser undoes a soft delete. Sessions revoked by the deletion stay
+// revoked, so the user has to log in again.
+func (uc *UserUseCase) RestoreUser(ctx context.Context, userID int64) (*contracts.UserInfo, error) {
+	if err := uc.userRepo.Restore(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.ErrUserNotFound
+	}
+
+	recordAudit(ctx, uc.auditLogRepo, enums.AuditActionUserRestored, nil, &user.ID, nil, nil)
+
+	return FormatUserInfo(user), nil
+}
+
+func (uc *UserUseCase) AssignRoles(ctx context.Context, userID int64, req *contracts.AssignRolesRequest) (*contracts.UserInfo, error) {
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.ErrUserNotFound
+	}
+
+	if len(req.Roles) == 0 {
+		return nil, errors.ErrRoleNotFound
+	}
+
+	roles, err := uc.resolveRoles(ctx, req.Roles)
+	if err != nil {
+		return nil, err
+	}
+
+	before := auditUserSnapshot(user)
+
+	if err := uc.roleRepo.AssignToUser(ctx, user, roles); err != nil {
+		return nil, err
+	}
+
+	// Access tokens carry the old roles and permissions. Refresh tokens stay
+	// valid, so clients pick up the new ones on their next refresh.
+	if err := uc.tokenRevocation.RevokeAllForUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	updatedUser, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.ErrUserNotFound
+	}
+
+	recordAudit(ctx, uc.auditLogRepo, enums.AuditActionRolesAssigned, nil, &userID, auditDiff(before, auditUserSnapshot(updatedUser)), nil)
+
+	return FormatUserInfo(updatedUser), nil
+}
+
+func (uc *UserUseCase) SetupTwoFactor(ctx context.Context, userID int64) (*contracts.TwoFactorSetupResponse, error) {
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.ErrUserNotFound
+	}
+
+	if user.TwoFactorEnabled {
+		return nil, errors.ErrTwoFactorEnabled
+	}
+
+	secret, err := uc.totpService.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	encryptedSecret, err := uc.aesService.EncryptInternal(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	user.SetTwoFactorSecret(encryptedSecret)
+
+	if err := uc.userRepo.UpdateTwoFactor(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return &contracts.TwoFactorSetupResponse{
+		Secret: secret,
+		URI:    uc.totpService.GenerateURI(secret, user.Email),
+	}, nil
+}
+
+func (uc *UserUseCase) EnableTwoFactor(ctx context.Context, userID int64, req *contracts.TwoFactorEnableRequest) (*contracts.TwoFactorEnableResponse, error) {
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.ErrUserNotFound
+	}
+
+	if user.TwoFactorEnabled {
+		return nil, errors.ErrTwoFactorEnabled
+	}
+
+	if user.TwoFactorSecret == "" {
+		return nil, errors.ErrTwoFactorNotSetup
+	}
+
+	if err := uc.verifyTwoFactorCode(ctx, user, req.Code, false); err != nil {
+		return nil, err
+	}
+
+	codes, err := uc.totpService.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+
+	// Recovery codes are random, so a fast hash is enough and keeps a bad
+	// login from costing up to ten password hash verifications.
+	recoveryCodes := make([]*entities.RecoveryCode, len(codes))
+	for i, code := range codes {
+		recoveryCodes[i] = entities.NewRecoveryCode(user.ID, uc.hashService.Hash(normalizeRecoveryCode(code)))
+	}
+
+	user.EnableTwoFactor()
+
+	err = uc.inTransaction(ctx, func(tx *UserUseCase) error {
+		if err := tx.recoveryCodeRepo.ReplaceAll(ctx, user.ID, recoveryCodes); err != nil {
+			return err
+		}
+
+		return tx.userRepo.UpdateTwoFactor(ctx, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, uc.auditLogRepo, enums.AuditActionTwoFactorEnabled, nil, &user.ID, nil, nil)
+
+	return &contracts.TwoFactorEnableResponse{
+		RecoveryCodes: codes,
+	}, nil
+}
+
+func (uc *UserUseCase) DisableTwoFactor(ctx context.Context, userID int64, req *contracts.TwoFactorDisableRequest) error {
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return errors.ErrUserNotFound
+	}
+
+	if !user.TwoFactorEnabled {
+		return errors.ErrTwoFactorNotEnabled
+	}
+
+	if err := uc.confirmPassword(ctx, user, req.Password); err != nil {
+		return err
+	}
+
+	if err := uc.verifyTwoFactorCode(ctx, user, req.Code, true); err != nil {
+		uc.loginThrottle.RegisterFailure(ctx, user.Email, contracts.AuditActorFromContext(ctx).IPAddress)
+		return err
+	}
+
+	user.DisableTwoFactor()
+
+	err = uc.inTransaction(ctx, func(tx *UserUseCase) error {
+		if err := tx.userRepo.UpdateTwoFactor(ctx, user); err != nil {
+			return err
+		}
+
+		return tx.recoveryCodeRepo.DeleteByUserID(ctx, user.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	recordAudit(ctx, uc.auditLogRepo, enums.AuditActionTwoFactorDisabled, nil, &user.ID, nil, nil)
+	return nil
+}
+
+// verifyTwoFactorCode accepts a TOTP code from the user's authenticator and,
+// when allowRecovery is set, one of the user's unused recovery codes.
+func (uc *UserUseCase) verifyTwoFactorCode(ctx context.Context, user *entities.User, code string, allowRecovery bool) error {
+	secret, err := uc.aesService.DecryptInternal(user.TwoFactorSecret)
+	if err != nil {
+		return err
+	}
+
+	if step, ok := uc.totpService.ValidateCode(secret, code); ok {
+		if step <= user.TwoFactorLastStep {
+			return errors.ErrInvalidTwoFactorCode
+		}
+
+		user.TwoFactorLastStep = step
+
+		// Re-encrypt secrets written with a retired or legacy key
+		if uc.aesService.NeedsRotation(user.TwoFactorSecret) {
+			if rotated, err := uc.aesService.EncryptInternal(secret); err == nil {
+				user.TwoFactorSecret = rotated
+			}
+		}
+
+		// Two requests with the same code both pass the check above; only
+		// one of them can move the stored step forward.
+		return uc.userRepo.UpdateTwoFactorStep(ctx, user)
+	}
+
+	if !allowRecovery {
+		return errors.ErrInvalidTwoFactorCode
+	}
+
+	recoveryCodes, err := uc.recoveryCodeRepo.FindUnusedByUserID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	codeHash := uc.hashService.Hash(normalizeRecoveryCode(code))
+	for _, recoveryCode := range recoveryCodes {
+		if matchesRecoveryCode(recoveryCode.CodeHash, codeHash) {
+			if err := uc.recoveryCodeRepo.MarkAsUsed(ctx, recoveryCode); err != nil {
+				return errors.ErrInvalidTwoFactorCode
+			}
+			return nil
+		}
+	}
+
+	return errors.ErrInvalidTwoFactorCode
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), " ", ""))
+}
+
+// matchesRecoveryCode compares SHA-256 hashes in constant time.
+func matchesRecoveryCode(stored, codeHash string) bool {
+	return subtle.ConstantTimeCompare([]byte(stored), []byte(codeHash)) == 1
+}
+
+func (uc *UserUseCase) UnlockUser(ctx context.Context, userID int64) error {
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return errors.ErrUserNotFound
+	}
+
+	if err := uc.loginThrottle.Reset(ctx, user.Email); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, uc.auditLogRepo, enums.AuditActionUserUnlocked, nil, &user.ID, nil, nil)
+	return nil
 }
@@ -0,0 +1,329 @@
+package usecases_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"go-gin-clean/internal/core/contracts"
+	"go-gin-clean/internal/core/domain/errors"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoginLocksAccountAfterFailures(t *testing.T) {
+	t.Setenv("LOCKOUT_MAX_ATTEMPTS", "3")
+	env := newTestEnv(t, nil)
+	ctx := context.Background()
+	env.createUser(t, "Alice", "alice@example.com")
+
+	login := func(password string) error {
+		_, err := env.users.Login(ctx, &contracts.LoginRequest{Email: "alice@example.com", Password: password})
+		return err
+	}
+
+	// A successful login clears the failures counted so far
+	for i := 0; i < 2; i++ {
+		if err := login("wrong"); err != errors.ErrPasswordNotMatch {
+			t.Fatalf("wrong password: err = %v, want %v", err, errors.ErrPasswordNotMatch)
+		}
+	}
+	if err := login(testPassword); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := login("wrong"); err != errors.ErrPasswordNotMatch {
+			t.Fatalf("wrong password: err = %v, want %v", err, errors.ErrPasswordNotMatch)
+		}
+	}
+
+	err := login(testPassword)
+	lockedErr, ok := errors.AsLockedError(err)
+	if !ok {
+		t.Fatalf("login after 3 failures: err = %v, want a LockedError", err)
+	}
+
+	if lockedErr.RetryAfterSeconds() <= 0 {
+		t.Fatalf("RetryAfterSeconds = %d, want > 0", lockedErr.RetryAfterSeconds())
+	}
+}
+
+func TestRefreshTokenRotationAndReuse(t *testing.T) {
+	env := newTestEnv(t, nil)
+	ctx := context.Background()
+	env.createUser(t, "Alice", "alice@example.com")
+
+	login, err := env.users.Login(ctx, &contracts.LoginRequest{Email: "alice@example.com", Password: testPassword})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+
+	rotated, err := env.users.RefreshToken(ctx, &contracts.RefreshTokenRequest{RefreshToken: login.RefreshToken})
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+
+	if rotated.RefreshToken == login.RefreshToken || rotated.AccessToken == "" {
+		t.Fatalf("refresh did not issue a new token pair")
+	}
+
+	// Presenting the rotated token again is a replay and ends the session
+	if _, err := env.users.RefreshToken(ctx, &contracts.RefreshTokenRequest{RefreshToken: login.RefreshToken}); err != errors.ErrTokenReused {
+		t.Fatalf("replayed refresh: err = %v, want %v", err, errors.ErrTokenReused)
+	}
+
+	if _, err := env.users.RefreshToken(ctx, &contracts.RefreshTokenRequest{RefreshToken: rotated.RefreshToken}); err != errors.ErrTokenInvalid {
+		t.Fatalf("refresh after reuse: err = %v, want %v", err, errors.ErrTokenInvalid)
+	}
+}
+
+func TestTwoFactorCodeCannotBeReplayed(t *testing.T) {
+	env := newTestEnv(t, nil)
+	ctx := context.Background()
+	user := env.createUser(t, "Alice", "alice@example.com")
+
+	setup, err := env.users.SetupTwoFactor(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("setup 2FA: %v", err)
+	}
+
+	// Pin the clock so both steps stay within the accepted skew
+	env.now = time.Now()
+	step := env.now.Unix() / 30
+
+	// Enable with the previous step so the current one is still unused
+	if _, err := env.users.EnableTwoFactor(ctx, user.ID, &contracts.TwoFactorEnableRequest{Code: totpCode(t, setup.Secret, step-1)}); err != nil {
+		t.Fatalf("enable 2FA: %v", err)
+	}
+
+	loginTwoFactor := func(code string) error {
+		login, err := env.users.Login(ctx, &contracts.LoginRequest{Email: "alice@example.com", Password: testPassword})
+		if err != nil {
+			t.Fatalf("login: %v", err)
+		}
+
+		if !login.MFARequired {
+			t.Fatal("login did not ask for a second factor")
+		}
+
+		_, err = env.users.LoginTwoFactor(ctx, &contracts.TwoFactorLoginRequest{MFAToken: login.MFAToken, Code: code})
+		return err
+	}
+
+	if err := loginTwoFactor(totpCode(t, setup.Secret, step-1)); err != errors.ErrInvalidTwoFactorCode {
+		t.Fatalf("code used to enable 2FA: err = %v, want %v", err, errors.ErrInvalidTwoFactorCode)
+	}
+
+	code := totpCode(t, setup.Secret, step)
+	if err := loginTwoFactor(code); err != nil {
+		t.Fatalf("login with 2FA: %v", err)
+	}
+
+	if err := loginTwoFactor(code); err != errors.ErrInvalidTwoFactorCode {
+		t.Fatalf("replayed code: err = %v, want %v", err, errors.ErrInvalidTwoFactorCode)
+	}
+}
+
+func TestResetPasswordEnforcesPolicy(t *testing.T) {
+	env := newTestEnv(t, nil)
+	ctx := context.Background()
+	env.createUser(t, "Alice", "alice@example.com")
+
+	if err := env.users.SendResetPassword(ctx, "alice@example.com"); err != nil {
+		t.Fatalf("send reset: %v", err)
+	}
+	token := env.linkToken(t, "reset_password", "ResetURL")
+
+	rejected := []struct {
+		password string
+		code     string
+	}{
+		{"Ab1", "min_length"},
+		{"Password123", "common"},
+		{"Alice-Secret-9", "contains_name"},
+		{testPassword, "reused"},
+	}
+
+	for _, tc := range rejected {
+		err := env.users.ResetPassword(ctx, &contracts.ResetPasswordRequest{Token: token, NewPassword: tc.password})
+
+		policyErr, ok := errors.AsPasswordPolicyError(err)
+		if !ok {
+			t.Fatalf("%q: err = %v, want a PasswordPolicyError", tc.password, err)
+		}
+
+		if !slices.ContainsFunc(policyErr.Violations, func(v errors.PasswordViolation) bool { return v.Code == tc.code }) {
+			t.Fatalf("%q: violations %+v, want %s", tc.password, policyErr.Violations, tc.code)
+		}
+	}
+
+	// Rejected passwords do not use up the link
+	if err := env.users.ResetPassword(ctx, &contracts.ResetPasswordRequest{Token: token, NewPassword: "Brand-New-Pass-42"}); err != nil {
+		t.Fatalf("reset: %v", err)
+	}
+
+	if err := env.users.ResetPassword(ctx, &contracts.ResetPasswordRequest{Token: token, NewPassword: "Another-Pass-43"}); err != errors.ErrTokenInvalid {
+		t.Fatalf("second reset: err = %v, want %v", err, errors.ErrTokenInvalid)
+	}
+
+	if _, err := env.users.Login(ctx, &contracts.LoginRequest{Email: "alice@example.com", Password: "Brand-New-Pass-42"}); err != nil {
+		t.Fatalf("login with new password: %v", err)
+	}
+}
+
+func TestGetUsersByCursorPagesBothWays(t *testing.T) {
+	env := newTestEnv(t, nil)
+	ctx := context.Background()
+
+	for _, name := range []string{"Eve", "Bob", "Dan", "Ann", "Cid"} {
+		env.createUser(t, name, strings.ToLower(name)+"@example.com")
+	}
+
+	spec := &contracts.QuerySpec{Sort: []contracts.Sort{{Field: "email"}}}
+	page := func(cursor string) *contracts.CursorResponse[contracts.UserInfo] {
+		t.Helper()
+
+		resp, err := env.users.GetUsersByCursor(ctx, &contracts.CursorRequest{Cursor: cursor, Limit: 2}, spec)
+		if err != nil {
+			t.Fatalf("GetUsersByCursor: %v", err)
+		}
+		return resp
+	}
+
+	first := page("")
+	assertEmails(t, first, "ann@example.com", "bob@example.com")
+	if first.PrevCursor != "" {
+		t.Fatalf("first page has a previous cursor")
+	}
+
+	second := page(first.NextCursor)
+	assertEmails(t, second, "cid@example.com", "dan@example.com")
+
+	last := page(second.NextCursor)
+	assertEmails(t, last, "eve@example.com")
+	if last.NextCursor != "" {
+		t.Fatalf("last page has a next cursor")
+	}
+
+	back := page(last.PrevCursor)
+	assertEmails(t, back, "cid@example.com", "dan@example.com")
+
+	start := page(back.PrevCursor)
+	assertEmails(t, start, "ann@example.com", "bob@example.com")
+	if start.PrevCursor != "" {
+		t.Fatalf("first page reached backwards has a previous cursor")
+	}
+}
+
+func assertEmails(t *testing.T, resp *contracts.CursorResponse[contracts.UserInfo], want ...string) {
+	t.Helper()
+
+	got := make([]string, len(resp.Data))
+	for i, user := range resp.Data {
+		got[i] = user.Email
+	}
+
+	if !slices.Equal(got, want) {
+		t.Fatalf("page = %v, want %v", got, want)
+	}
+}
+
+// linkToken waits for the email rendered from template, which is sent in
+// the background, and returns the token query parameter of its link.
+func (e *testEnv) linkToken(t *testing.T, template, field string) string {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		for _, rendered := range e.mailer.Templates() {
+			if rendered.Name != template {
+				continue
+			}
+
+			link, _ := rendered.Data.(map[string]any)[field].(string)
+			parsed, err := url.Parse(link)
+			if err != nil {
+				t.Fatalf("parse %s link: %v", template, err)
+			}
+			return parsed.Query().Get("token")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	t.Fatalf("no %s email sent", template)
+	return ""
+}
+
+// totpCode computes the RFC 6238 code for step, as an authenticator app would.
+func totpCode(t *testing.T, secret string, step int64) string {
+	t.Helper()
+
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		t.Fatalf("decode secret: %v", err)
+	}
+
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%06d", value%1_000_000)
+}
+
+func TestChangePasswordCountsTowardsLockout(t *testing.T) {
+	t.Setenv("LOCKOUT_MAX_ATTEMPTS", "3")
+	env := newTestEnv(t, nil)
+	ctx := context.Background()
+	user := env.createUser(t, "Alice", "alice@example.com")
+
+	for i := 0; i < 3; i++ {
+		err := env.users.ChangePassword(ctx, user.ID, &contracts.ChangePasswordRequest{OldPassword: "wrong", NewPassword: "Brand-New-Pass-42"})
+		if err != errors.ErrPasswordNotMatch {
+			t.Fatalf("wrong password: err = %v, want %v", err, errors.ErrPasswordNotMatch)
+		}
+	}
+
+	err := env.users.ChangePassword(ctx, user.ID, &contracts.ChangePasswordRequest{OldPassword: testPassword, NewPassword: "Brand-New-Pass-42"})
+	if _, ok := errors.AsLockedError(err); !ok {
+		t.Fatalf("change after 3 failures: err = %v, want a LockedError", err)
+	}
+
+	if _, err := env.users.Login(ctx, &contracts.LoginRequest{Email: "alice@example.com", Password: testPassword}); err == nil {
+		t.Fatal("login succeeded while the account is locked")
+	}
+}
+
+func TestRestoreUser(t *testing.T) {
+	env := newTestEnv(t, nil)
+	ctx := context.Background()
+	user := env.createUser(t, "Alice", "alice@example.com")
+
+	if _, err := env.users.RestoreUser(ctx, user.ID); err != errors.ErrUserNotFound {
+		t.Fatalf("restore active user: err = %v, want %v", err, errors.ErrUserNotFound)
+	}
+
+	if err := env.users.DeleteUser(ctx, user.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	restored, err := env.users.RestoreUser(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+
+	if restored.Email != "alice@example.com" {
+		t.Fatalf("restored %+v", restored)
+	}
+}
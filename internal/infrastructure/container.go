@@ -4,40 +4,88 @@ import (
 	"go-gin-clean/internal/adapters/secondary/database"
 	"go-gin-clean/internal/adapters/secondary/mailer"
 	"go-gin-clean/internal/adapters/secondary/media"
+	"go-gin-clean/internal/adapters/secondary/memory"
+	"go-gin-clean/internal/adapters/secondary/oauth"
 	"go-gin-clean/internal/adapters/secondary/security"
 	"go-gin-clean/internal/core/ports"
 	"go-gin-clean/internal/core/usecases"
 	"go-gin-clean/pkg/config"
+	"log"
 
 	"gorm.io/gorm"
 )
 
 type Container struct {
-	UserUseCase   ports.UserUseCase
-	EmailUseCase  ports.EmailUseCase
-	JWTService    ports.JWTService
-	MailerService ports.MailerService
+	UserUseCase     ports.UserUseCase
+	APIKeyUseCase   ports.APIKeyUseCase
+	AuditLogUseCase ports.AuditLogUseCase
+	TokenRevocation ports.TokenRevocationUseCase
+	EmailUseCase    ports.EmailUseCase
+	JWTService      ports.JWTService
+	MailerService   ports.MailerService
 }
 
 func NewContainer(db *gorm.DB, cfg *config.Config) *Container {
 	// Init repositories
 	userRepo := database.NewUserRepository(db)
 	refreshTokenRepo := database.NewRefreshTokenRepository(db)
+	roleRepo := database.NewRoleRepository(db)
+	recoveryCodeRepo := database.NewRecoveryCodeRepository(db)
+	oneTimeTokenRepo := database.NewOneTimeTokenRepository(db)
+	userIdentityRepo := database.NewUserIdentityRepository(db)
+	apiKeyRepo := database.NewAPIKeyRepository(db)
+	passwordHistoryRepo := database.NewPasswordHistoryRepository(db)
+	auditLogRepo := database.NewAuditLogRepository(db)
+	unitOfWork := database.NewUnitOfWork(db)
+
+	var loginAttemptRepo ports.LoginAttemptRepository
+	if cfg.Lockout.Store == "memory" {
+		loginAttemptRepo = memory.NewLoginAttemptRepository(cfg.LoginAttemptRetention())
+	} else {
+		loginAttemptRepo = database.NewLoginAttemptRepository(db)
+	}
+
+	var revokedTokenRepo ports.RevokedTokenRepository
+	if cfg.JWT.DenylistStore == "memory" {
+		revokedTokenRepo = memory.NewRevokedTokenRepository()
+	} else {
+		revokedTokenRepo = database.NewRevokedTokenRepository(db)
+	}
 
 	// Init services
-	jwtService := security.NewJWTService(&cfg.JWT)
-	bcryptService := security.NewBcryptService()
-	aesService := security.NewAESService(&cfg.AES)
+	jwtService, err := security.NewJWTService(&cfg.JWT)
+	if err != nil {
+		log.Fatalf("Error loading JWT signing keys: %v", err)
+	}
+	passwordHasher := security.NewPasswordHasher(&cfg.Password)
+	aesService, err := security.NewAESService(&cfg.AES)
+	if err != nil {
+		log.Fatalf("Error loading AES keys: %v", err)
+	}
+	sha256Service := security.NewSHA256Service()
+	totpService := security.NewTOTPService(&cfg.TOTP, nil)
 	smtpService := mailer.NewSMTPService(&cfg.Mailer)
 	localStorageService := media.NewLocalStorageService()
+	oidcService := oauth.NewOIDCService(&cfg.OAuth, nil)
 
 	// Init use cases
+	loginThrottle := usecases.NewLoginThrottle(loginAttemptRepo, &cfg.Lockout)
+	passwordPolicy, err := usecases.NewPasswordPolicy(passwordHistoryRepo, passwordHasher, &cfg.Policy)
+	if err != nil {
+		log.Fatalf("Error loading password policy: %v", err)
+	}
 	emailUseCase := usecases.NewEmailUseCase(smtpService)
-	userUseCase := usecases.NewUserUseCase(userRepo, emailUseCase, refreshTokenRepo, jwtService, bcryptService, aesService, localStorageService)
+	tokenRevocation := usecases.NewTokenRevocationUseCase(revokedTokenRepo, &cfg.JWT)
+	userUseCase := usecases.NewUserUseCase(userRepo, emailUseCase, refreshTokenRepo, roleRepo, recoveryCodeRepo, oneTimeTokenRepo, userIdentityRepo, apiKeyRepo, auditLogRepo, unitOfWork, jwtService, tokenRevocation, passwordHasher, aesService, sha256Service, totpService, localStorageService, oidcService, loginThrottle, passwordPolicy, &cfg.Magic, &cfg.JWT)
+	apiKeyUseCase := usecases.NewAPIKeyUseCase(apiKeyRepo, userRepo, sha256Service)
+	auditLogUseCase := usecases.NewAuditLogUseCase(auditLogRepo)
 
 	return &Container{
-		UserUseCase:  userUseCase,
-		EmailUseCase: emailUseCase,
-		JWTService:   jwtService,
+		UserUseCase:     userUseCase,
+		APIKeyUseCase:   apiKeyUseCase,
+		AuditLogUseCase: auditLogUseCase,
+		TokenRevocation: tokenRevocation,
+		EmailUseCase:    emailUseCase,
+		JWTService:      jwtService,
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -13,6 +14,12 @@ type Config struct {
 	JWT      JWTConfig
 	Mailer   MailerConfig
 	AES      AESConfig
+	TOTP     TOTPConfig
+	Lockout  LockoutConfig
+	Password PasswordConfig
+	Policy   PasswordPolicyConfig
+	OAuth    OAuthConfig
+	Magic    MagicLinkConfig
 }
 
 type ServerConfig struct {
@@ -21,16 +28,30 @@ type ServerConfig struct {
 	Environment string
 	AppUrl      string
 	Timeout     int
+	// MaxPageSize caps per_page and limit on list endpoints.
+	MaxPageSize int
 }
 
+// Database drivers selectable with DB_DRIVER. They match the names of the
+// gorm dialectors and of the migration directories.
+const (
+	DriverPostgres = "postgres"
+	DriverMySQL    = "mysql"
+	DriverSQLite   = "sqlite"
+)
+
 type DatabaseConfig struct {
+	Driver       string
 	Host         string
 	Port         int
 	User         string
 	Password     string
-	DBName       string
+	DBName       string // database file for SQLite
 	MaxOpenConns int
 	MaxIdleConns int
+	// SoftDeleteRetention is how long soft-deleted rows are kept before the
+	// purge command removes them.
+	SoftDeleteRetention time.Duration
 }
 
 type JWTConfig struct {
@@ -39,6 +60,17 @@ type JWTConfig struct {
 	RefreshTokenSecret string
 	AccessTokenExpiry  time.Duration
 	RefreshTokenExpiry time.Duration
+	MFATokenExpiry     time.Duration
+	KeysDir            string
+	ActiveKeyID        string
+	DenylistStore      string
+	// RevokeAllOnReuse makes a replayed refresh token revoke every session of
+	// the user instead of only the affected token family.
+	RevokeAllOnReuse bool
+	// HS256AcceptUntil lets access tokens signed with AccessTokenSecret keep
+	// working after KeysDir is configured, until this time. Zero rejects
+	// them right away.
+	HS256AcceptUntil time.Time
 }
 
 type MailerConfig struct {
@@ -50,11 +82,118 @@ type MailerConfig struct {
 }
 
 type AESConfig struct {
-	Key string
-	IV  string
+	// Key and IV are only used by DecryptInternal to decrypt values written
+	// by the legacy AES-CBC implementation.
+	Key           string
+	IV            string
+	Keys          map[int]string
+	ActiveVersion int
+}
+
+type TOTPConfig struct {
+	Issuer string
+}
+
+type LockoutConfig struct {
+	Store         string
+	MaxAttempts   int
+	IPMaxAttempts int
+	Window        time.Duration
+	BaseDuration  time.Duration
+	MaxDuration   time.Duration
+}
+
+const (
+	PasswordAlgorithmArgon2id = "argon2id"
+	PasswordAlgorithmBcrypt   = "bcrypt"
+)
+
+type PasswordConfig struct {
+	Algorithm  string
+	BcryptCost int
+	Argon2     Argon2Config
+}
+
+type Argon2Config struct {
+	Memory      uint32 // KiB
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// Bounds accepted for Argon2id parameters, both from the environment and
+// from stored hashes.
+const (
+	Argon2MaxMemory      = 4 * 1024 * 1024 // KiB
+	Argon2MaxIterations  = 100
+	Argon2MaxParallelism = 255
+)
+
+// ValidateArgon2Params rejects parameters argon2.IDKey cannot use, or that
+// would make a single hash unreasonably expensive.
+func ValidateArgon2Params(memory, iterations, parallelism int) error {
+	if parallelism < 1 || parallelism > Argon2MaxParallelism {
+		return fmt.Errorf("argon2 parallelism must be between 1 and %d, got %d", Argon2MaxParallelism, parallelism)
+	}
+	if iterations < 1 || iterations > Argon2MaxIterations {
+		return fmt.Errorf("argon2 iterations must be between 1 and %d, got %d", Argon2MaxIterations, iterations)
+	}
+	if memory < 8*parallelism || memory > Argon2MaxMemory {
+		return fmt.Errorf("argon2 memory must be between %d and %d KiB, got %d", 8*parallelism, Argon2MaxMemory, memory)
+	}
+	return nil
+}
+
+type PasswordPolicyConfig struct {
+	MinLength            int
+	MaxLength            int
+	RequireUppercase     bool
+	RequireLowercase     bool
+	RequireDigit         bool
+	RequireSpecial       bool
+	DisallowPersonalInfo bool
+	DisallowCommon       bool
+	HistorySize          int
+	// CommonPasswordsFile names a newline separated list of passwords to
+	// reject on top of the embedded one, e.g. a breached password list.
+	CommonPasswordsFile string
+}
+
+type MagicLinkConfig struct {
+	Expiry     time.Duration
+	RateLimit  int
+	RateWindow time.Duration
+}
+
+type OAuthConfig struct {
+	Providers map[string]OAuthProviderConfig
+}
+
+// OAuthProviderConfig describes an OpenID Connect provider. Endpoints are
+// discovered from IssuerURL + "/.well-known/openid-configuration".
+type OAuthProviderConfig struct {
+	Name         string
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
 }
 
 func Load() (*Config, error) {
+	argon2Memory := getEnvAsInt("ARGON2_MEMORY", 64*1024)
+	argon2Iterations := getEnvAsInt("ARGON2_ITERATIONS", 3)
+	argon2Parallelism := getEnvAsInt("ARGON2_PARALLELISM", 2)
+	if err := ValidateArgon2Params(argon2Memory, argon2Iterations, argon2Parallelism); err != nil {
+		return nil, err
+	}
+
+	maxPageSize := getEnvAsInt("MAX_PAGE_SIZE", 100)
+	if maxPageSize < 1 {
+		return nil, fmt.Errorf("MAX_PAGE_SIZE must be at least 1, got %d", maxPageSize)
+	}
+
 	return &Config{
 		Server: ServerConfig{
 			Host:        getEnv("SERVER_HOST", "localhost"),
@@ -62,8 +201,10 @@ func Load() (*Config, error) {
 			Environment: getEnv("ENVIRONMENT", "development"),
 			AppUrl:      getEnv("APP_URL", "http://localhost:8080"),
 			Timeout:     getEnvAsInt("TIMEOUT", 30),
+			MaxPageSize: maxPageSize,
 		},
 		Database: DatabaseConfig{
+			Driver:       strings.ToLower(getEnv("DB_DRIVER", DriverPostgres)),
 			Host:         getEnv("DB_HOST", "localhost"),
 			Port:         getEnvAsInt("DB_PORT", 5432),
 			User:         getEnv("DB_USER", "user"),
@@ -71,6 +212,8 @@ func Load() (*Config, error) {
 			DBName:       getEnv("DB_NAME", "dbname"),
 			MaxOpenConns: getEnvAsInt("DB_MAX_OPEN_CONNS", 25),
 			MaxIdleConns: getEnvAsInt("DB_MAX_IDLE_CONNS", 5),
+
+			SoftDeleteRetention: getEnvAsDuration("SOFT_DELETE_RETENTION", 30*24*time.Hour),
 		},
 		JWT: JWTConfig{
 			JWTIssuer:          getEnv("JWT_ISSUER", "go-gin-clean"),
@@ -78,6 +221,12 @@ func Load() (*Config, error) {
 			RefreshTokenSecret: getEnv("JWT_REFRESH_SECRET", "your-refresh-secret-key"),
 			AccessTokenExpiry:  getEnvAsDuration("JWT_ACCESS_EXPIRY", 1*time.Hour),
 			RefreshTokenExpiry: getEnvAsDuration("JWT_REFRESH_EXPIRY", 7*24*time.Hour),
+			MFATokenExpiry:     getEnvAsDuration("JWT_MFA_EXPIRY", 5*time.Minute),
+			KeysDir:            getEnv("JWT_KEYS_DIR", ""),
+			ActiveKeyID:        getEnv("JWT_ACTIVE_KEY_ID", ""),
+			DenylistStore:      getEnv("JWT_DENYLIST_STORE", "database"),
+			HS256AcceptUntil:   getEnvAsTime("JWT_HS256_ACCEPT_UNTIL"),
+			RevokeAllOnReuse:   getEnvAsBool("JWT_REVOKE_ALL_ON_REUSE", false),
 		},
 		Mailer: MailerConfig{
 			Host:     getEnv("MAILER_HOST", "smtp.example.com"),
@@ -87,19 +236,103 @@ func Load() (*Config, error) {
 			Password: getEnv("MAILER_PASSWORD", "your-email-password"),
 		},
 		AES: AESConfig{
-			Key: getEnv("AES_KEY", "your-aes-encryption-key"),
-			IV:  getEnv("AES_IV", "your-aes-initialization-vector"),
+			Key:           getEnv("AES_KEY", "your-aes-encryption-key"),
+			IV:            getEnv("AES_IV", "your-aes-initialization-vector"),
+			Keys:          getEnvAsVersionedKeys("AES_KEYS"),
+			ActiveVersion: getEnvAsInt("AES_ACTIVE_KEY_VERSION", 0),
+		},
+		TOTP: TOTPConfig{
+			Issuer: getEnv("TOTP_ISSUER", "Go Gin Clean App"),
+		},
+		Lockout: LockoutConfig{
+			Store:         getEnv("LOCKOUT_STORE", "database"),
+			MaxAttempts:   getEnvAsInt("LOCKOUT_MAX_ATTEMPTS", 5),
+			IPMaxAttempts: getEnvAsInt("LOCKOUT_IP_MAX_ATTEMPTS", 20),
+			Window:        getEnvAsDuration("LOCKOUT_WINDOW", 15*time.Minute),
+			BaseDuration:  getEnvAsDuration("LOCKOUT_BASE_DURATION", 1*time.Minute),
+			MaxDuration:   getEnvAsDuration("LOCKOUT_MAX_DURATION", 1*time.Hour),
+		},
+		Password: PasswordConfig{
+			Algorithm:  getEnv("PASSWORD_HASH_ALGORITHM", PasswordAlgorithmArgon2id),
+			BcryptCost: getEnvAsInt("BCRYPT_COST", 10),
+			Argon2: Argon2Config{
+				Memory:      uint32(argon2Memory),
+				Iterations:  uint32(argon2Iterations),
+				Parallelism: uint8(argon2Parallelism),
+				SaltLength:  16,
+				KeyLength:   32,
+			},
+		},
+		Policy: PasswordPolicyConfig{
+			MinLength:            getEnvAsInt("PASSWORD_MIN_LENGTH", 8),
+			MaxLength:            getEnvAsInt("PASSWORD_MAX_LENGTH", 128),
+			RequireUppercase:     getEnvAsBool("PASSWORD_REQUIRE_UPPERCASE", true),
+			RequireLowercase:     getEnvAsBool("PASSWORD_REQUIRE_LOWERCASE", true),
+			RequireDigit:         getEnvAsBool("PASSWORD_REQUIRE_DIGIT", true),
+			RequireSpecial:       getEnvAsBool("PASSWORD_REQUIRE_SPECIAL", false),
+			DisallowPersonalInfo: getEnvAsBool("PASSWORD_DISALLOW_PERSONAL_INFO", true),
+			DisallowCommon:       getEnvAsBool("PASSWORD_DISALLOW_COMMON", true),
+			HistorySize:          getEnvAsInt("PASSWORD_HISTORY_SIZE", 5),
+			CommonPasswordsFile:  getEnv("PASSWORD_COMMON_LIST_FILE", ""),
+		},
+		Magic: MagicLinkConfig{
+			Expiry:     getEnvAsDuration("MAGIC_LINK_EXPIRY", 15*time.Minute),
+			RateLimit:  getEnvAsInt("MAGIC_LINK_RATE_LIMIT", 3),
+			RateWindow: getEnvAsDuration("MAGIC_LINK_RATE_WINDOW", 15*time.Minute),
+		},
+		OAuth: OAuthConfig{
+			Providers: loadOAuthProviders(),
 		},
 	}, nil
 }
 
+// ActiveKeyVersion returns the configured active version, defaulting to the
+// highest configured key version.
+func (c *AESConfig) ActiveKeyVersion() int {
+	if c.ActiveVersion != 0 {
+		return c.ActiveVersion
+	}
+
+	active := 0
+	for version := range c.Keys {
+		if version > active {
+			active = version
+		}
+	}
+	return active
+}
+
+// LoginAttemptRetention is how long an unlocked attempt counter is kept after
+// its last failure. Both the lockout and the magic link rate limit count in
+// the attempt store, so it covers the longer of their windows.
+func (c *Config) LoginAttemptRetention() time.Duration {
+	return max(c.Lockout.Window, c.Magic.RateWindow)
+}
+
 func (c *ServerConfig) Address() string {
 	return fmt.Sprintf("%s:%d", c.Host, c.Port)
 }
 
+// DSN returns the connection string in the format of the selected driver.
 func (c *DatabaseConfig) DSN() string {
-	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d",
-		c.Host, c.User, c.Password, c.DBName, c.Port)
+	switch c.Driver {
+	case DriverMySQL:
+		// multiStatements lets a migration file run as a single Exec.
+		return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=true&loc=UTC&multiStatements=true",
+			c.User, c.Password, c.Host, c.Port, c.DBName)
+	case DriverSQLite:
+		// WAL lets readers run alongside the single writer, and immediate
+		// transactions wait for the write lock up front instead of failing
+		// with "database is locked" when upgrading to it.
+		separator := "?"
+		if strings.Contains(c.DBName, "?") {
+			separator = "&"
+		}
+		return c.DBName + separator + "_foreign_keys=on&_journal_mode=WAL&_busy_timeout=5000&_txlock=immediate"
+	default:
+		return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d",
+			c.Host, c.User, c.Password, c.DBName, c.Port)
+	}
 }
 
 func GetAppURL() string {
@@ -123,6 +356,54 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolVal, err := strconv.ParseBool(value); err == nil {
+			return boolVal
+		}
+	}
+	return defaultValue
+}
+
+// loadOAuthProviders reads OAUTH_PROVIDERS=google,azure and the matching
+// OAUTH_<NAME>_ISSUER_URL, _CLIENT_ID, _CLIENT_SECRET, _REDIRECT_URL and
+// _SCOPES variables.
+func loadOAuthProviders() map[string]OAuthProviderConfig {
+	providers := make(map[string]OAuthProviderConfig)
+	for _, name := range strings.Split(os.Getenv("OAUTH_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		prefix := "OAUTH_" + strings.ToUpper(name) + "_"
+		providers[name] = OAuthProviderConfig{
+			Name:         name,
+			IssuerURL:    strings.TrimSuffix(getEnv(prefix+"ISSUER_URL", ""), "/"),
+			ClientID:     getEnv(prefix+"CLIENT_ID", ""),
+			ClientSecret: getEnv(prefix+"CLIENT_SECRET", ""),
+			RedirectURL:  getEnv(prefix+"REDIRECT_URL", ""),
+			Scopes:       strings.Fields(getEnv(prefix+"SCOPES", "openid email profile")),
+		}
+	}
+	return providers
+}
+
+// getEnvAsVersionedKeys parses "1:first-key,2:second-key" into a version map.
+func getEnvAsVersionedKeys(key string) map[int]string {
+	keys := make(map[int]string)
+	for _, entry := range strings.Split(os.Getenv(key), ",") {
+		version, value, found := strings.Cut(strings.TrimSpace(entry), ":")
+		if !found {
+			continue
+		}
+		if versionInt, err := strconv.Atoi(version); err == nil && value != "" {
+			keys[versionInt] = value
+		}
+	}
+	return keys
+}
+
 func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
 		if duration, err := time.ParseDuration(value); err == nil {
@@ -131,3 +412,14 @@ func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	}
 	return defaultValue
 }
+
+// getEnvAsTime parses an RFC 3339 timestamp, returning the zero time when the
+// variable is unset or malformed.
+func getEnvAsTime(key string) time.Time {
+	if value := os.Getenv(key); value != "" {
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -1,10 +1,14 @@
 package utils
 
 import (
+	"html"
 	"math/rand"
 	"regexp"
 	"slices"
 	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
 )
 
 func SanitizeString(s string) string {
@@ -74,3 +78,60 @@ func GeneratePassword(length int, includeSpecialChars bool) string {
 
 	return string(result)
 }
+
+// foldRune lowercases r and strips its diacritics, so É folds to e. It
+// mirrors what ILIKE on unaccent() matches in the database.
+func foldRune(r rune) []rune {
+	var folded []rune
+	for _, d := range norm.NFD.String(string(r)) {
+		if !unicode.Is(unicode.Mn, d) {
+			folded = append(folded, unicode.ToLower(d))
+		}
+	}
+	return folded
+}
+
+// Highlight HTML-escapes text and wraps every occurrence of term in
+// <mark></mark>, ignoring case and accents. It reports whether term was
+// found.
+func Highlight(text, term string) (string, bool) {
+	var needle []rune
+	for _, r := range strings.TrimSpace(term) {
+		needle = append(needle, foldRune(r)...)
+	}
+
+	runes := []rune(text)
+
+	// origin maps every folded rune back to the rune of text it came from.
+	var folded []rune
+	var origin []int
+	for i, r := range runes {
+		for _, f := range foldRune(r) {
+			folded = append(folded, f)
+			origin = append(origin, i)
+		}
+	}
+
+	var b strings.Builder
+	last, found := 0, false
+	for i := 0; len(needle) > 0 && i+len(needle) <= len(folded); {
+		if !slices.Equal(folded[i:i+len(needle)], needle) {
+			i++
+			continue
+		}
+
+		start, end := origin[i], origin[i+len(needle)-1]+1
+		b.WriteString(html.EscapeString(string(runes[last:start])))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(string(runes[start:end])))
+		b.WriteString("</mark>")
+		last, found = end, true
+
+		for i < len(folded) && origin[i] < end {
+			i++
+		}
+	}
+	b.WriteString(html.EscapeString(string(runes[last:])))
+
+	return b.String(), found
+}